@@ -8,26 +8,40 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"syscall"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
+	"github.com/rudraprasaaad/task-scheduler/internal/audit"
 	"github.com/rudraprasaaad/task-scheduler/internal/cache"
+	"github.com/rudraprasaaad/task-scheduler/internal/certs"
 	"github.com/rudraprasaaad/task-scheduler/internal/config"
 	"github.com/rudraprasaaad/task-scheduler/internal/cron"
 	"github.com/rudraprasaaad/task-scheduler/internal/database"
+	"github.com/rudraprasaaad/task-scheduler/internal/events"
 	"github.com/rudraprasaaad/task-scheduler/internal/handlers"
+	"github.com/rudraprasaaad/task-scheduler/internal/health"
 	"github.com/rudraprasaaad/task-scheduler/internal/middleware"
 	"github.com/rudraprasaaad/task-scheduler/internal/queue"
+	"github.com/rudraprasaaad/task-scheduler/internal/quota"
 	"github.com/rudraprasaaad/task-scheduler/internal/redis"
 	"github.com/rudraprasaaad/task-scheduler/internal/repository"
+	"github.com/rudraprasaaad/task-scheduler/internal/service"
+	"github.com/rudraprasaaad/task-scheduler/internal/session"
+	"github.com/rudraprasaaad/task-scheduler/internal/tasklog"
 
+	"github.com/rudraprasaaad/task-scheduler/internal/grpc/gateway"
+	"github.com/rudraprasaaad/task-scheduler/internal/grpc/generated/openapi"
 	taskpb "github.com/rudraprasaaad/task-scheduler/internal/grpc/generated/task"
 	workerpb "github.com/rudraprasaaad/task-scheduler/internal/grpc/generated/worker"
 	"github.com/rudraprasaaad/task-scheduler/internal/grpc/interceptor"
 	"github.com/rudraprasaaad/task-scheduler/internal/grpc/server"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 func main() {
@@ -72,19 +86,87 @@ func main() {
 	taskRepo := repository.NewTaskRepository(db)
 	workerRepo := repository.NewWorkerRepository(db)
 	userRepo := repository.NewUserRepository(db)
+	if cfg.Auth.BootstrapAdminEmail != "" {
+		granted, err := userRepo.BootstrapAdmin(ctx, cfg.Auth.BootstrapAdminEmail)
+		if err != nil {
+			log.Printf("ERROR: Failed to bootstrap admin %s: %v", cfg.Auth.BootstrapAdminEmail, err)
+		} else if granted {
+			log.Printf("Granted admin role to bootstrap admin %s", cfg.Auth.BootstrapAdminEmail)
+		}
+	}
+	tenantRepo := repository.NewTenantRepository(db)
 	execRepo := repository.NewTaskExecutionRepository(db)
-	redisQueue := queue.NewRedisQueue(redisClient)
+	instructionRepo := repository.NewWorkerInstructionRepository(db)
+	credentialRepo := repository.NewWorkerCredentialRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	bulkJobRepo := repository.NewBulkJobRepository(db)
+	taskLogRepo := repository.NewTaskLogRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	quotas := quota.NewManager(redisClient, tenantRepo, cfg.Quota)
+	redisQueue := queue.NewRedisQueue(redisClient, quotas)
+	if n, err := redisQueue.RebuildTenantQueues(ctx); err != nil {
+		log.Printf("ERROR: %v", err)
+	} else if n > 0 {
+		log.Printf("Indexed %d queued tasks by tenant", n)
+	}
 	cache := cache.NewRedisCache(redisClient, "task_scheduler:")
+	taskLogs := tasklog.NewStore(redisClient, taskLogRepo)
+
+	healthChecker := health.NewChecker(db, redisClient, cfg.HealthCheckInterval)
+	healthChecker.Check(ctx)
+	go healthChecker.Run(ctx)
 
-	authInterceptor := interceptor.NewAuthInterceptor(cfg.Auth.JWTSecret)
+	denylist := session.NewDenylist(redisClient, cfg.Auth.TokenExpiration)
+	sessions := session.NewManager(sessionRepo, userRepo, denylist, cfg.Auth)
+	auditLog := audit.NewRecorder(auditRepo)
 
-	grpcServer := grpc.NewServer(
-		grpc.UnaryInterceptor(authInterceptor.Unary()),
-	)
-	taskServer := server.NewTaskServer(taskRepo, workerRepo)
-	workerServer := server.NewWorkerServer(workerRepo)
-	taskpb.RegisterTaskServiceServer(grpcServer, taskServer)
-	workerpb.RegisterWorkerServiceServer(grpcServer, workerServer)
+	authInterceptor := interceptor.NewAuthInterceptor(cfg.Auth.JWTSecret, credentialRepo, apiKeyRepo, denylist, cfg.GRPCTLS.RequireWorkerCert)
+	auditInterceptor := interceptor.NewAuditInterceptor(auditLog)
+
+	interceptorOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(auditInterceptor.Unary(), authInterceptor.Unary()),
+		grpc.StreamInterceptor(authInterceptor.Stream()),
+	}
+	grpcOpts := slices.Clone(interceptorOpts)
+
+	if cfg.GRPCTLS.Enabled() {
+		grpcCerts, err := certs.NewReloader(cfg.GRPCTLS)
+		if err != nil {
+			log.Fatalf("Failed to load gRPC TLS certificate: %v", err)
+		}
+		go grpcCerts.Watch(ctx)
+		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(grpcCerts.ServerConfig("h2"))))
+		log.Println("gRPC TLS enabled")
+	} else if cfg.GRPCTLS.RequireWorkerCert {
+		log.Fatal("GRPC_REQUIRE_WORKER_CERT needs GRPC_TLS_CERT_FILE and GRPC_TLS_KEY_FILE")
+	}
+
+	grpcServer := grpc.NewServer(grpcOpts...)
+	taskService := service.NewTaskService(taskRepo, execRepo, redisQueue, cache, quotas)
+	bulkService := service.NewBulkService(taskService, bulkJobRepo, auditLog)
+	go bulkService.Run(ctx)
+	eventBroker := events.NewBroker(redisClient)
+	go eventBroker.Run(ctx)
+
+	taskServer := server.NewTaskServer(taskService, workerRepo, eventBroker)
+	workerServer := server.NewWorkerServer(workerRepo, instructionRepo, credentialRepo, healthChecker)
+	registerServices := func(s grpc.ServiceRegistrar) {
+		taskpb.RegisterTaskServiceServer(s, taskServer)
+		workerpb.RegisterWorkerServiceServer(s, workerServer)
+	}
+	registerServices(grpcServer)
+	healthpb.RegisterHealthServer(grpcServer, healthChecker.Server())
+
+	if cfg.GRPCReflection {
+		reflection.Register(grpcServer)
+		log.Println("gRPC server reflection enabled")
+	}
+
+	gatewayHandler, err := gateway.New(ctx, interceptorOpts, registerServices)
+	if err != nil {
+		log.Fatalf("Failed to create REST gateway: %v", err)
+	}
 
 	grpcListener, err := net.Listen("tcp", ":9090")
 	if err != nil {
@@ -98,7 +180,7 @@ func main() {
 		}
 	}()
 
-	cronScheduler, err := cron.NewScheduler(redisQueue)
+	cronScheduler, err := cron.NewScheduler(redisQueue, auditLog)
 	if err != nil {
 		log.Fatalf("Failed to create cron scheduler: %v", err)
 	}
@@ -106,8 +188,18 @@ func main() {
 	cronScheduler.Start()
 	defer cronScheduler.Stop()
 
-	authHandler := handlers.NewAuthHandler(userRepo, cfg.Auth)
-	taskHandler := handlers.NewTaskHandler(taskRepo, workerRepo, execRepo, redisClient, cache, cfg.MaxWorkers)
+	authHandler := handlers.NewAuthHandler(userRepo, sessions, session.NewLoginThrottle(redisClient, cfg.Auth.Login), auditLog, cfg.Auth)
+	roleHandler := handlers.NewRoleHandler(userRepo)
+	tenantHandler := handlers.NewTenantHandler(tenantRepo, userRepo, quotas, redisQueue)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyRepo)
+	taskHandler := handlers.NewTaskHandler(taskService, taskRepo, workerRepo, execRepo, instructionRepo, redisQueue, cache, taskLogs, cfg.MaxWorkers, cfg.Worker)
+	taskLogHandler := handlers.NewTaskLogHandler(taskService, taskLogs)
+	workerHandler := handlers.NewWorkerHandler(workerRepo, instructionRepo, credentialRepo, workerServer, cfg.Worker.DrainGracePeriod)
+	eventHandler := handlers.NewEventHandler(eventBroker)
+	bulkHandler := handlers.NewBulkHandler(bulkService)
+	auditHandler := handlers.NewAuditHandler(auditRepo)
+	dashboardHandler := handlers.NewDashboardHandler(redisClient, cache, eventBroker)
+	go dashboardHandler.Run(ctx)
 
 	taskHandler.StartWorkers(ctx)
 	defer taskHandler.StopWorkers()
@@ -117,31 +209,82 @@ func main() {
 	rateLimiter := middleware.RateLimitMiddleware(10, 20)
 	router.Use(middleware.LoggingMiddleware, rateLimiter)
 
+	csrf := middleware.CSRFMiddleware(cfg.Auth.JWTSecret, apiKeyRepo, denylist)
+
 	authRouter := router.PathPrefix("/api/v1/auth").Subrouter()
 	authRouter.HandleFunc("/register", authHandler.Register).Methods("POST")
 	authRouter.HandleFunc("/login", authHandler.Login).Methods("POST")
+	authRouter.Handle("/refresh", csrf(http.HandlerFunc(authHandler.Refresh))).Methods("POST")
+	authRouter.Handle("/logout", csrf(http.HandlerFunc(authHandler.Logout))).Methods("POST")
+	authRouter.Handle("/logout-all", csrf(middleware.AuthMiddleware(cfg.Auth.JWTSecret, apiKeyRepo, denylist)(http.HandlerFunc(authHandler.LogoutAll)))).Methods("POST")
 
 	api := router.PathPrefix("/api/v1").Subrouter()
-	api.Use(middleware.AuthMiddleware(cfg.Auth.JWTSecret))
+	api.Use(csrf, middleware.AuthMiddleware(cfg.Auth.JWTSecret, apiKeyRepo, denylist), middleware.AuditMiddleware(auditLog), middleware.Authorize)
 
 	api.HandleFunc("/tasks", taskHandler.CreateTask).Methods("POST")
 	api.HandleFunc("/tasks", taskHandler.ListTasks).Methods("GET")
+	api.HandleFunc("/tasks/stats", taskHandler.GetTaskStats).Methods("GET")
+	api.HandleFunc("/tasks/bulk/create", bulkHandler.CreateTasks).Methods("POST")
+	api.HandleFunc("/tasks/bulk/{operation}", bulkHandler.SubmitOperation).Methods("POST")
 	api.HandleFunc("/tasks/{id}", taskHandler.GetTaskByID).Methods("GET")
+	api.HandleFunc("/tasks/{id}", taskHandler.UpdateTask).Methods("PATCH")
 	api.HandleFunc("/tasks/{id}", taskHandler.DeleteTask).Methods("DELETE")
 	api.HandleFunc("/tasks/{id}/cancel", taskHandler.CancelTask).Methods("POST")
+	api.HandleFunc("/tasks/{id}/retry", taskHandler.RetryTask).Methods("POST")
+	api.HandleFunc("/tasks/{id}/executions", taskHandler.ListTaskExecutions).Methods("GET")
+	api.HandleFunc("/tasks/{id}/timeline", taskHandler.GetTaskTimeline).Methods("GET")
+	api.HandleFunc("/tasks/{id}/logs", taskLogHandler.GetLogs).Methods("GET")
 	api.HandleFunc("/queue/status", taskHandler.GetQueueStatus).Methods("GET")
+	api.HandleFunc("/quota", tenantHandler.GetOwnQuota).Methods("GET")
 	api.HandleFunc("/workers/stats", taskHandler.GetWorkerStats).Methods("GET")
-	api.HandleFunc("/tasks/stats", taskHandler.GetTaskStats).Methods("GET")
+	api.HandleFunc("/workers/{id}/drain", workerHandler.DrainWorker).Methods("POST")
+	api.HandleFunc("/workers/{id}/instructions", workerHandler.CreateInstruction).Methods("POST")
+	api.HandleFunc("/workers/{id}/instructions", workerHandler.ListInstructions).Methods("GET")
+	api.HandleFunc("/bulk-jobs", bulkHandler.ListJobs).Methods("GET")
+	api.HandleFunc("/bulk-jobs/{id}", bulkHandler.GetJob).Methods("GET")
+	api.HandleFunc("/bulk-jobs/{id}/items", bulkHandler.ListItems).Methods("GET")
+	api.HandleFunc("/events", eventHandler.StreamEvents).Methods("GET")
+	api.HandleFunc("/dashboard/ws", dashboardHandler.ServeWebSocket).Methods("GET")
+	api.HandleFunc("/audit", auditHandler.ListEvents).Methods("GET")
+
+	admin := api.PathPrefix("/admin").Subrouter()
+	admin.HandleFunc("/workers/resize", taskHandler.ResizeWorkers).Methods("POST")
+	admin.HandleFunc("/workers/enrollment-tokens", workerHandler.CreateEnrollmentToken).Methods("POST")
+	admin.HandleFunc("/workers/{id}/credentials", workerHandler.RevokeCredentials).Methods("DELETE")
+	admin.HandleFunc("/roles", roleHandler.ListRoles).Methods("GET")
+	admin.HandleFunc("/users", roleHandler.ListUsers).Methods("GET")
+	admin.HandleFunc("/users/{id}/roles", roleHandler.GetUserRoles).Methods("GET")
+	admin.HandleFunc("/users/{id}/roles/{role}", roleHandler.GrantRole).Methods("PUT")
+	admin.HandleFunc("/users/{id}/roles/{role}", roleHandler.RevokeRole).Methods("DELETE")
+	admin.HandleFunc("/tenants", tenantHandler.ListTenants).Methods("GET")
+	admin.HandleFunc("/tenants", tenantHandler.CreateTenant).Methods("POST")
+	admin.HandleFunc("/tenants/{id}/quota", tenantHandler.GetQuota).Methods("GET")
+	admin.HandleFunc("/tenants/{id}/quota", tenantHandler.SetQuota).Methods("PUT")
+	admin.HandleFunc("/users/{id}/tenant", tenantHandler.SetUserTenant).Methods("PUT")
+	admin.HandleFunc("/users/{id}/sessions", authHandler.RevokeUserSessions).Methods("DELETE")
+	admin.HandleFunc("/service-accounts", apiKeyHandler.ListServiceAccounts).Methods("GET")
+	admin.HandleFunc("/service-accounts", apiKeyHandler.CreateServiceAccount).Methods("POST")
+	admin.HandleFunc("/api-keys", apiKeyHandler.ListKeys).Methods("GET")
+	admin.HandleFunc("/api-keys", apiKeyHandler.CreateKey).Methods("POST")
+	admin.HandleFunc("/api-keys/{id}", apiKeyHandler.RevokeKey).Methods("DELETE")
+
+	router.HandleFunc("/v1/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openapi.Spec)
+	}).Methods("GET")
+	router.PathPrefix("/v1/").Handler(csrf(gatewayHandler))
 
 	router.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 
-		if err := db.Health(); err != nil {
+		report := healthChecker.Check(r.Context())
+
+		if report.Components[health.ComponentDatabase] != nil {
 			http.Error(w, `{"status": "error", "message": "database unhealthy"}`, http.StatusServiceUnavailable)
 			return
 		}
 
-		if err := redisClient.Health(r.Context()); err != nil {
+		if report.Components[health.ComponentRedis] != nil {
 			http.Error(w, `{"status":"error", "message": "redis unhealthy"}`, http.StatusServiceUnavailable)
 			return
 		}
@@ -162,9 +305,25 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	if cfg.HTTPTLS.Enabled() {
+		httpCerts, err := certs.NewReloader(cfg.HTTPTLS)
+		if err != nil {
+			log.Fatalf("Failed to load HTTP TLS certificate: %v", err)
+		}
+		go httpCerts.Watch(ctx)
+		server.TLSConfig = httpCerts.ServerConfig("h2", "http/1.1")
+	}
+
 	go func() {
-		log.Printf("Server starting on port %s with %d workers", cfg.Port, cfg.MaxWorkers)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Printf("Server starting on port %s with %d workers (TLS: %t)", cfg.Port, cfg.MaxWorkers, cfg.HTTPTLS.Enabled())
+
+		var err error
+		if server.TLSConfig != nil {
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Server failed to start: %v", err)
 		}
 	}()
@@ -175,6 +334,10 @@ func main() {
 
 	log.Println("Shutting down server...")
 
+	healthChecker.Shutdown()
+
+	taskHandler.DrainWorkers()
+
 	grpcServer.GracefulStop()
 
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
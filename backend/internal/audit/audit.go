@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+)
+
+const (
+	ActionRegister    = "auth.register"
+	ActionLogin       = "auth.login"
+	ActionLoginFailed = "auth.login_failed"
+	ActionLockout     = "auth.lockout"
+	ActionLogout      = "auth.logout"
+	ActionLogoutAll   = "auth.logout_all"
+
+	ActionTaskCreate = "task.create"
+)
+
+type Recorder interface {
+	Record(ctx context.Context, event *models.AuditEvent)
+}
+
+// Store is where a DBRecorder appends events.
+type Store interface {
+	Insert(ctx context.Context, event *models.AuditEvent) error
+}
+
+// DBRecorder appends each event to the audit_events table. An event that
+// cannot be stored is still written to the process log, so it is not lost.
+type DBRecorder struct {
+	store    Store
+	fallback *LogRecorder
+}
+
+func NewRecorder(store Store) *DBRecorder {
+	return &DBRecorder{store: store, fallback: NewLogRecorder()}
+}
+
+func (r *DBRecorder) Record(ctx context.Context, event *models.AuditEvent) {
+	normalize(event)
+
+	// The request may already be finished or cancelled, but its event must
+	// still be stored.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if err := r.store.Insert(ctx, event); err != nil {
+		log.Printf("ERROR: Failed to store audit event %s: %v", event.Action, err)
+		r.fallback.Record(ctx, event)
+	}
+}
+
+// LogRecorder writes each event to the process log as one JSON line prefixed
+// with AUDIT, so it can be picked out by log shipping.
+type LogRecorder struct{}
+
+func NewLogRecorder() *LogRecorder {
+	return &LogRecorder{}
+}
+
+func (r *LogRecorder) Record(_ context.Context, event *models.AuditEvent) {
+	normalize(event)
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("ERROR: Failed to encode audit event %s: %v", event.Action, err)
+		return
+	}
+
+	log.Printf("AUDIT: %s", line)
+}
+
+func normalize(event *models.AuditEvent) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	if event.ActorType == "" {
+		event.ActorType = models.ActorAnonymous
+	}
+	if event.Outcome == "" {
+		event.Outcome = models.AuditSuccess
+	}
+}
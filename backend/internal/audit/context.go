@@ -0,0 +1,141 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/auth"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+)
+
+type contextKey struct{}
+
+// NewContext attaches the event being built for a call, so the code handling
+// it can fill in its target and the change it made. Whoever attached it
+// records it once the call is done.
+func NewContext(ctx context.Context, event *models.AuditEvent) context.Context {
+	if event == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, event)
+}
+
+func FromContext(ctx context.Context) *models.AuditEvent {
+	event, _ := ctx.Value(contextKey{}).(*models.AuditEvent)
+	return event
+}
+
+// ActorFromClaims names who a token belongs to: the key for API keys, the
+// worker for worker credentials and the user otherwise.
+func ActorFromClaims(claims *auth.Claims) (actorType, actorID string) {
+	switch {
+	case claims == nil:
+		return models.ActorAnonymous, ""
+	case claims.Role == auth.RoleService:
+		return models.ActorAPIKey, claims.Subject
+	case claims.Role == auth.RoleWorker && claims.Subject != "":
+		return models.ActorWorker, claims.Subject
+	default:
+		return models.ActorUser, claims.UserID
+	}
+}
+
+func SetActor(ctx context.Context, claims *auth.Claims) {
+	event := FromContext(ctx)
+	if event == nil || claims == nil {
+		return
+	}
+
+	event.ActorType, event.ActorID = ActorFromClaims(claims)
+	event.TenantID = claims.TenantID
+	if claims.Role == auth.RoleService {
+		event.Details = withDetail(event.Details, "service_account", claims.UserID)
+	}
+}
+
+func SetTarget(ctx context.Context, targetType, targetID string) {
+	if event := FromContext(ctx); event != nil {
+		event.TargetType = targetType
+		event.TargetID = targetID
+	}
+}
+
+func SetDetail(ctx context.Context, key string, value interface{}) {
+	if event := FromContext(ctx); event != nil {
+		event.Details = withDetail(event.Details, key, value)
+	}
+}
+
+// SetChange stores snapshots of the target before and after the call, either
+// of which may be nil, and the top-level fields that differ between them.
+func SetChange(ctx context.Context, before, after interface{}) {
+	event := FromContext(ctx)
+	if event == nil {
+		return
+	}
+
+	var err error
+	if event.Before, err = snapshot(before); err != nil {
+		log.Printf("ERROR: Failed to encode audit snapshot for %s: %v", event.Action, err)
+	}
+	if event.After, err = snapshot(after); err != nil {
+		log.Printf("ERROR: Failed to encode audit snapshot for %s: %v", event.Action, err)
+	}
+	event.Changes = Diff(event.Before, event.After)
+}
+
+// Diff compares two JSON objects field by field. A field missing on one side
+// shows up as null there.
+func Diff(before, after json.RawMessage) map[string]models.FieldChange {
+	var from, to map[string]json.RawMessage
+	json.Unmarshal(before, &from)
+	json.Unmarshal(after, &to)
+
+	changes := make(map[string]models.FieldChange)
+	for field, value := range from {
+		if !bytes.Equal(value, to[field]) {
+			changes[field] = models.FieldChange{From: value, To: rawOrNil(to[field])}
+		}
+	}
+	for field, value := range to {
+		if _, ok := from[field]; !ok {
+			changes[field] = models.FieldChange{From: nil, To: value}
+		}
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
+
+func snapshot(value interface{}) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if raw, ok := value.(json.RawMessage); ok {
+		return raw, nil
+	}
+	raw, err := json.Marshal(value)
+	if err != nil || string(raw) == "null" {
+		return nil, err
+	}
+	return raw, nil
+}
+
+func rawOrNil(value json.RawMessage) interface{} {
+	if value == nil {
+		return nil
+	}
+	return value
+}
+
+func withDetail(details map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	if details == nil {
+		details = make(map[string]interface{})
+	}
+	details[key] = value
+	return details
+}
@@ -0,0 +1,137 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+)
+
+// changeText renders a diff's values as JSON text, so tests can compare them
+// without caring whether a side is raw JSON or nil.
+func changeText(changes map[string]models.FieldChange) map[string][2]string {
+	text := func(value interface{}) string {
+		if value == nil {
+			return "null"
+		}
+		return string(value.(json.RawMessage))
+	}
+
+	rendered := make(map[string][2]string, len(changes))
+	for field, change := range changes {
+		rendered[field] = [2]string{text(change.From), text(change.To)}
+	}
+	return rendered
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   map[string][2]string
+	}{
+		{
+			name:   "no change",
+			before: `{"priority":5,"name":"report"}`,
+			after:  `{"name":"report","priority":5}`,
+			want:   nil,
+		},
+		{
+			name:   "changed, added and removed fields",
+			before: `{"priority":5,"error":"timeout","name":"report"}`,
+			after:  `{"priority":10,"name":"report","worker_id":"w-1"}`,
+			want: map[string][2]string{
+				"priority":  {"5", "10"},
+				"error":     {`"timeout"`, "null"},
+				"worker_id": {"null", `"w-1"`},
+			},
+		},
+		{
+			name:   "nested change is reported on the top-level field",
+			before: `{"payload":{"to":"a@example.com","retry":true}}`,
+			after:  `{"payload":{"to":"b@example.com","retry":true}}`,
+			want: map[string][2]string{
+				"payload": {`{"to":"a@example.com","retry":true}`, `{"to":"b@example.com","retry":true}`},
+			},
+		},
+		{
+			name:  "created",
+			after: `{"id":"t-1","priority":5}`,
+			want: map[string][2]string{
+				"id":       {"null", `"t-1"`},
+				"priority": {"null", "5"},
+			},
+		},
+		{
+			name:   "deleted",
+			before: `{"id":"t-1"}`,
+			want: map[string][2]string{
+				"id": {`"t-1"`, "null"},
+			},
+		},
+		{
+			name: "neither side",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var before, after json.RawMessage
+			if tt.before != "" {
+				before = json.RawMessage(tt.before)
+			}
+			if tt.after != "" {
+				after = json.RawMessage(tt.after)
+			}
+
+			changes := Diff(before, after)
+			if tt.want == nil {
+				if changes != nil {
+					t.Fatalf("got %v, want no changes", changeText(changes))
+				}
+				return
+			}
+
+			got := changeText(changes)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for field, want := range tt.want {
+				if got[field] != want {
+					t.Errorf("field %s: got %v, want %v", field, got[field], want)
+				}
+			}
+		})
+	}
+}
+
+func TestSetChange(t *testing.T) {
+	before := models.NewTask("report", "generate-report", nil)
+	after := *before
+	after.Priority = models.PriorityHigh
+
+	// Without an event in the context there is nothing to record.
+	SetChange(context.Background(), before, &after)
+
+	event := &models.AuditEvent{Action: "task.update"}
+	ctx := NewContext(context.Background(), event)
+	SetChange(ctx, before, &after)
+
+	got := changeText(event.Changes)
+	if len(got) != 1 || got["priority"] != [2]string{"5", "10"} {
+		t.Errorf("got changes %v, want only priority 5 to 10", got)
+	}
+	if event.Before == nil || event.After == nil {
+		t.Error("snapshots were not stored")
+	}
+
+	var missing *models.Task
+	event = &models.AuditEvent{Action: "task.create"}
+	SetChange(NewContext(context.Background(), event), missing, &after)
+	if event.Before != nil || event.Changes["id"].From != nil {
+		t.Errorf("a nil pointer before a create should snapshot as nothing, got %s", event.Before)
+	}
+}
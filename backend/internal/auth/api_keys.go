@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+)
+
+const (
+	APIKeyPrefix = "tsk_"
+
+	// RoleService marks claims that come from an API key. It grants nothing
+	// by itself; the key's scopes are carried in Claims.Permissions.
+	RoleService = "service"
+
+	// ServiceAccountUserPrefix prefixes the service account ID in
+	// Claims.UserID, so task owners and audit lines tell it apart from users.
+	ServiceAccountUserPrefix = "sa:"
+)
+
+// APIKeyStore loads API keys for authentication. It is satisfied by
+// repository.APIKeyRepository.
+type APIKeyStore interface {
+	GetKey(ctx context.Context, id string) (*models.APIKey, error)
+	MarkUsed(ctx context.Context, id string) error
+}
+
+// GenerateAPIKey works like GenerateWorkerCredential: the key embeds its ID so
+// it can be looked up directly, and only the secret's hash is stored.
+func GenerateAPIKey(keyID string) (secret, key string, err error) {
+	secret, err = randomHex(32)
+	if err != nil {
+		return "", "", err
+	}
+
+	return secret, APIKeyPrefix + keyID + "." + secret, nil
+}
+
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+func ParseAPIKey(key string) (keyID, secret string, err error) {
+	id, secret, ok := strings.Cut(strings.TrimPrefix(key, APIKeyPrefix), ".")
+	if !ok || id == "" || secret == "" {
+		return "", "", fmt.Errorf("malformed API key")
+	}
+
+	return id, secret, nil
+}
+
+// AuthenticateAPIKey checks an API key and returns claims that carry the
+// key's scopes and its service account's tenant.
+func AuthenticateAPIKey(ctx context.Context, store APIKeyStore, key string) (*Claims, error) {
+	keyID, secret, err := ParseAPIKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey, err := store.GetKey(ctx, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("unknown API key")
+	}
+
+	if !apiKey.Active() || !VerifySecret(secret, apiKey.SecretHash) {
+		return nil, fmt.Errorf("invalid API key")
+	}
+
+	if err := store.MarkUsed(ctx, apiKey.ID); err != nil {
+		return nil, err
+	}
+
+	permissions := make([]Permission, len(apiKey.Scopes))
+	for i, scope := range apiKey.Scopes {
+		permissions[i] = Permission(scope)
+	}
+
+	claims := &Claims{
+		UserID:      ServiceAccountUserPrefix + apiKey.ServiceAccountID,
+		Role:        RoleService,
+		Permissions: permissions,
+		TenantID:    apiKey.TenantID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject: apiKey.ID,
+		},
+	}
+	if apiKey.ExpiresAt != nil {
+		claims.ExpiresAt = jwt.NewNumericDate(*apiKey.ExpiresAt)
+	}
+
+	return claims, nil
+}
@@ -2,13 +2,37 @@ package auth
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// Token times keep microseconds, so a token issued just after a logout
+// everywhere is not mistaken for one issued before it within the same second.
+func init() {
+	jwt.TimePrecision = time.Microsecond
+}
+
+const (
+	RoleAdmin  = "admin"
+	RoleUser   = "user"
+	RoleWorker = "worker"
+)
+
 type Claims struct {
-	UserID string `json:"user_id"`
-	Role   string `json:"role"`
+	UserID string   `json:"user_id"`
+	Role   string   `json:"role"`
+	Roles  []string `json:"roles,omitempty"`
+
+	// Permissions are granted directly rather than through a role. Only
+	// API key claims carry them, one per scope.
+	Permissions []Permission `json:"permissions,omitempty"`
+
+	TenantID string `json:"tenant_id,omitempty"`
+
+	// SessionID ties an access token to the login it was issued for. The
+	// token's own ID (jti) is what logout adds to the denylist.
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -0,0 +1,92 @@
+package auth
+
+import "slices"
+
+type Permission string
+
+const (
+	PermTaskRead     Permission = "tasks:read"
+	PermTaskWrite    Permission = "tasks:write"
+	PermTaskDelete   Permission = "tasks:delete"
+	PermWorkerRead   Permission = "workers:read"
+	PermWorkerManage Permission = "workers:manage"
+	PermRoleManage   Permission = "roles:manage"
+	PermHealthRead   Permission = "health:read"
+	PermTenantManage Permission = "tenants:manage"
+	PermAPIKeyManage Permission = "apikeys:manage"
+	PermAuditRead    Permission = "audit:read"
+
+	// PermTaskAllTenants lifts tenant isolation, so task reads and writes
+	// reach every tenant's tasks rather than only the caller's own.
+	PermTaskAllTenants Permission = "tasks:all-tenants"
+
+	// PermWorkerAgent covers the RPCs a worker process uses to register,
+	// claim tasks and report on them.
+	PermWorkerAgent Permission = "workers:agent"
+)
+
+// rolePermissions is the permission matrix. Roles a user holds that are not
+// listed here grant nothing.
+var rolePermissions = map[string][]Permission{
+	RoleAdmin: {
+		PermTaskRead, PermTaskWrite, PermTaskDelete,
+		PermWorkerRead, PermWorkerManage,
+		PermRoleManage, PermHealthRead,
+		PermTenantManage, PermTaskAllTenants,
+		PermAPIKeyManage, PermAuditRead,
+	},
+	RoleUser: {
+		PermTaskRead, PermTaskWrite, PermHealthRead,
+	},
+	RoleWorker: {
+		PermWorkerAgent, PermHealthRead,
+	},
+}
+
+// PrimaryRole picks the role recorded in Claims.Role for a set of roles.
+func PrimaryRole(roles []string) string {
+	if slices.Contains(roles, RoleAdmin) {
+		return RoleAdmin
+	}
+	return RoleUser
+}
+
+// RoleNames returns every role the claims carry. Tokens issued before roles
+// were loaded from the database only have Role.
+func (c *Claims) RoleNames() []string {
+	if len(c.Roles) > 0 {
+		return c.Roles
+	}
+	if c.Role != "" {
+		return []string{c.Role}
+	}
+	return nil
+}
+
+func (c *Claims) HasRole(role string) bool {
+	return slices.Contains(c.RoleNames(), role)
+}
+
+// Can reports whether the permission is granted directly or by any of the
+// caller's roles.
+func (c *Claims) Can(permission Permission) bool {
+	if slices.Contains(c.Permissions, permission) {
+		return true
+	}
+	for _, role := range c.RoleNames() {
+		if slices.Contains(rolePermissions[role], permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// KnownPermission reports whether any role in the matrix grants the permission.
+func KnownPermission(permission Permission) bool {
+	for _, permissions := range rolePermissions {
+		if slices.Contains(permissions, permission) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package auth
+
+import "testing"
+
+var allPermissions = []Permission{
+	PermTaskRead, PermTaskWrite, PermTaskDelete,
+	PermWorkerRead, PermWorkerManage,
+	PermRoleManage, PermHealthRead,
+	PermTenantManage, PermAPIKeyManage, PermAuditRead,
+	PermTaskAllTenants, PermWorkerAgent,
+}
+
+func TestRolePermissionMatrix(t *testing.T) {
+	granted := map[string][]Permission{
+		RoleAdmin: {
+			PermTaskRead, PermTaskWrite, PermTaskDelete,
+			PermWorkerRead, PermWorkerManage,
+			PermRoleManage, PermHealthRead,
+			PermTenantManage, PermAPIKeyManage, PermAuditRead,
+			PermTaskAllTenants,
+		},
+		RoleUser:   {PermTaskRead, PermTaskWrite, PermHealthRead},
+		RoleWorker: {PermWorkerAgent, PermHealthRead},
+		"auditor":  nil,
+	}
+
+	for role, permissions := range granted {
+		claims := &Claims{Role: role, Roles: []string{role}}
+		want := make(map[Permission]bool)
+		for _, permission := range permissions {
+			want[permission] = true
+		}
+
+		for _, permission := range allPermissions {
+			if got := claims.Can(permission); got != want[permission] {
+				t.Errorf("%s can %s: got %v, want %v", role, permission, got, want[permission])
+			}
+		}
+	}
+}
+
+func TestCanCombinesRolesAndDirectPermissions(t *testing.T) {
+	both := &Claims{Role: RoleUser, Roles: []string{RoleUser, RoleWorker}}
+	if !both.Can(PermTaskWrite) || !both.Can(PermWorkerAgent) {
+		t.Error("a caller with two roles should get the permissions of both")
+	}
+	if both.Can(PermTaskDelete) {
+		t.Error("neither role grants tasks:delete")
+	}
+
+	// API keys carry their scopes as permissions, with no role granting more.
+	key := &Claims{Role: RoleService, Permissions: []Permission{PermTaskRead}}
+	if !key.Can(PermTaskRead) || key.Can(PermTaskWrite) {
+		t.Error("an API key should be limited to its scopes")
+	}
+
+	// Tokens issued before roles were loaded only carry Role.
+	legacy := &Claims{Role: RoleAdmin}
+	if !legacy.Can(PermRoleManage) {
+		t.Error("a token with only Role should still get that role's permissions")
+	}
+
+	if (&Claims{}).Can(PermHealthRead) {
+		t.Error("claims without roles should grant nothing")
+	}
+}
+
+func TestPrimaryRole(t *testing.T) {
+	if got := PrimaryRole([]string{RoleUser, RoleAdmin}); got != RoleAdmin {
+		t.Errorf("got %s, want admin for a user who is also an admin", got)
+	}
+	if got := PrimaryRole(nil); got != RoleUser {
+		t.Errorf("got %s, want user for no roles", got)
+	}
+}
+
+func TestKnownPermission(t *testing.T) {
+	if !KnownPermission(PermAuditRead) {
+		t.Error("audit:read is granted by admin")
+	}
+	if KnownPermission("tasks:everything") {
+		t.Error("no role grants tasks:everything")
+	}
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const RefreshTokenPrefix = "rft_"
+
+var ErrTokenRevoked = errors.New("token has been revoked")
+
+// RevocationChecker reports whether an access token was revoked before it
+// expired, by logout or by logging out every session of its user.
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, claims *Claims) (bool, error)
+}
+
+// GenerateRefreshToken embeds the session ID like GenerateWorkerCredential
+// embeds the credential ID. Only the secret's hash is stored.
+func GenerateRefreshToken(sessionID string) (secret, token string, err error) {
+	secret, err = randomHex(32)
+	if err != nil {
+		return "", "", err
+	}
+
+	return secret, RefreshTokenPrefix + sessionID + "." + secret, nil
+}
+
+func ParseRefreshToken(token string) (sessionID, secret string, err error) {
+	if !strings.HasPrefix(token, RefreshTokenPrefix) {
+		return "", "", fmt.Errorf("malformed refresh token")
+	}
+
+	id, secret, ok := strings.Cut(strings.TrimPrefix(token, RefreshTokenPrefix), ".")
+	if !ok || id == "" || secret == "" {
+		return "", "", fmt.Errorf("malformed refresh token")
+	}
+
+	return id, secret, nil
+}
+
+// GenerateCSRFToken returns the value for the double-submit CSRF cookie.
+func GenerateCSRFToken() (string, error) {
+	return randomHex(32)
+}
+
+// ValidateActiveToken validates a JWT and rejects it if it has been revoked.
+func ValidateActiveToken(ctx context.Context, tokenString, secretKey string, revocations RevocationChecker) (*Claims, error) {
+	claims, err := ValidateToken(tokenString, secretKey)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := revocations.IsRevoked(ctx, claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+	if revoked {
+		return nil, ErrTokenRevoked
+	}
+
+	return claims, nil
+}
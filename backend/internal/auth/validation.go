@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"unicode"
+)
+
+const (
+	MinPasswordLength = 10
+
+	// MaxPasswordLength is bcrypt's limit; longer passwords would be
+	// silently truncated.
+	MaxPasswordLength = 72
+)
+
+// ValidateEmail accepts a bare address such as ada@example.com, without a
+// display name.
+func ValidateEmail(email string) error {
+	if len(email) > 255 {
+		return fmt.Errorf("email must be at most 255 characters")
+	}
+
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email || address.Name != "" {
+		return fmt.Errorf("email must be a valid address such as name@example.com")
+	}
+
+	_, domain, _ := strings.Cut(email, "@")
+	if !strings.Contains(domain, ".") || strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") {
+		return fmt.Errorf("email must be a valid address such as name@example.com")
+	}
+
+	return nil
+}
+
+// ValidatePassword requires MinPasswordLength characters mixing at least three
+// of lowercase, uppercase, digits and symbols, and rejects passwords that
+// contain the email's local part.
+func ValidatePassword(password, email string) error {
+	if len([]rune(password)) < MinPasswordLength {
+		return fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+	}
+	if len(password) > MaxPasswordLength {
+		return fmt.Errorf("password must be at most %d bytes", MaxPasswordLength)
+	}
+
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		case !unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+
+	classes := 0
+	for _, present := range []bool{lower, upper, digit, symbol} {
+		if present {
+			classes++
+		}
+	}
+	if classes < 3 {
+		return fmt.Errorf("password must mix at least three of lowercase letters, uppercase letters, digits and symbols")
+	}
+
+	local, _, _ := strings.Cut(strings.ToLower(email), "@")
+	if len(local) >= 3 && strings.Contains(strings.ToLower(password), local) {
+		return fmt.Errorf("password must not contain your email address")
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	EnrollmentTokenPrefix  = "wet_"
+	WorkerCredentialPrefix = "wkc_"
+)
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random secret: %w", err)
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+// HashSecret is used for enrollment tokens and worker credential secrets.
+// They are long random strings, so a plain SHA-256 is enough to keep them
+// unusable if the table leaks.
+func HashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func GenerateEnrollmentToken() (string, error) {
+	secret, err := randomHex(32)
+	if err != nil {
+		return "", err
+	}
+
+	return EnrollmentTokenPrefix + secret, nil
+}
+
+// GenerateWorkerCredential returns the secret to store (hashed) and the full
+// credential handed to the worker, which embeds the credential ID so it can be
+// looked up without scanning.
+func GenerateWorkerCredential(credentialID string) (secret, credential string, err error) {
+	secret, err = randomHex(32)
+	if err != nil {
+		return "", "", err
+	}
+
+	return secret, WorkerCredentialPrefix + credentialID + "." + secret, nil
+}
+
+func IsWorkerCredential(token string) bool {
+	return strings.HasPrefix(token, WorkerCredentialPrefix)
+}
+
+func ParseWorkerCredential(credential string) (credentialID, secret string, err error) {
+	id, secret, ok := strings.Cut(strings.TrimPrefix(credential, WorkerCredentialPrefix), ".")
+	if !ok || id == "" || secret == "" {
+		return "", "", fmt.Errorf("malformed worker credential")
+	}
+
+	return id, secret, nil
+}
+
+func VerifySecret(secret, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashSecret(secret)), []byte(hash)) == 1
+}
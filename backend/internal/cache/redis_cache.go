@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	redisClient "github.com/rudraprasaaad/task-scheduler/internal/redis"
@@ -60,10 +61,50 @@ func (rc *RedisCache) SetWorkerStats(ctx context.Context, workerID string, stats
 func (rc *RedisCache) GetWorkerStats(ctx context.Context, workerID string) (map[string]interface{}, error) {
 	key := fmt.Sprintf("worker:stats:%s", workerID)
 	var stats map[string]interface{}
-	err := rc.Get(ctx, key, stats)
+	err := rc.Get(ctx, key, &stats)
 	return stats, err
 }
 
+// ListWorkerStats returns the cached stats of every worker that reported in the
+// last few minutes, keyed by worker ID.
+func (rc *RedisCache) ListWorkerStats(ctx context.Context) (map[string]map[string]interface{}, error) {
+	keyPrefix := rc.prefix + "worker:stats:"
+
+	var keys []string
+	iter := rc.client.Scan(ctx, 0, keyPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan worker stats: %w", err)
+	}
+
+	workers := make(map[string]map[string]interface{}, len(keys))
+	if len(keys) == 0 {
+		return workers, nil
+	}
+
+	values, err := rc.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worker stats: %w", err)
+	}
+
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		var stats map[string]interface{}
+		if err := json.Unmarshal([]byte(data), &stats); err != nil {
+			continue
+		}
+		workers[strings.TrimPrefix(keys[i], keyPrefix)] = stats
+	}
+
+	return workers, nil
+}
+
 func (rc *RedisCache) CacheTaskStats(ctx context.Context, stats map[string]int) error {
 	return rc.Set(ctx, "task:stats", stats, 1*time.Minute)
 }
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -14,18 +16,97 @@ type Config struct {
 	LogLevel    string
 	Environment string
 	Auth        AuthConfig
+	Worker      WorkerConfig
+	Quota       QuotaConfig
+	HTTPTLS     TLSConfig
+	GRPCTLS     TLSConfig
+
+	// GRPCReflection registers the server reflection service so tools like
+	// grpcurl can discover the API without the proto files.
+	GRPCReflection      bool
+	HealthCheckInterval time.Duration
+}
+
+type TLSConfig struct {
+	CertFile       string
+	KeyFile        string
+	ClientCAFile   string
+	ReloadInterval time.Duration
+
+	// RequireWorkerCert rejects worker RPCs that are not made over a verified
+	// client certificate. Only meaningful for the gRPC listener.
+	RequireWorkerCert bool
+}
+
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" && c.KeyFile != ""
 }
 
 type AuthConfig struct {
-	JWTSecret       string
-	TokenExpiration time.Duration
+	JWTSecret string
+
+	// TokenExpiration is how long an access token lives. Clients renew it
+	// with their refresh token, which lasts RefreshTokenExpiration from login.
+	TokenExpiration        time.Duration
+	RefreshTokenExpiration time.Duration
+
+	// BootstrapAdminEmail is granted the admin role at startup, or when that
+	// user first logs in, while no one else is an admin, so a fresh install
+	// has someone who can grant roles.
+	BootstrapAdminEmail string
+
+	Login LoginThrottleConfig
+}
+
+// LoginThrottleConfig locks an account, or a client IP, out of logging in for
+// LockoutDuration once it fails MaxAccountFailures or MaxIPFailures times
+// within FailureWindow.
+type LoginThrottleConfig struct {
+	MaxAccountFailures int
+	MaxIPFailures      int
+	FailureWindow      time.Duration
+	LockoutDuration    time.Duration
+}
+
+type WorkerConfig struct {
+	Labels     map[string]string
+	TaskTypes  []string
+	Slots      int
+	TypeLimits map[string]int
+
+	DrainGracePeriod time.Duration
+	Autoscale        AutoscaleConfig
+}
+
+// QuotaConfig holds the limits for tenants that do not set their own. A zero
+// limit means no limit.
+type QuotaConfig struct {
+	MaxPending       int
+	EnqueuePerMinute int
+	MaxRunning       int
+	Weight           int
+
+	// CacheTTL is how long a tenant's limits are reused before being read from
+	// the database again.
+	CacheTTL time.Duration
+}
+
+type AutoscaleConfig struct {
+	Enabled              bool
+	MinWorkers           int
+	Interval             time.Duration
+	QueuePerWorker       int
+	MaxQueueAge          time.Duration
+	ScaleUpUtilization   float64
+	ScaleDownUtilization float64
+	ScaleUpCooldown      time.Duration
+	ScaleDownCooldown    time.Duration
 }
 
 func Load() *Config {
-	tokenExp, err := time.ParseDuration(getEnv("JWT_EXPIRATION", "24h"))
-	if err != nil {
-		tokenExp = 24 * time.Hour
-	}
+	tlsReload := getEnvAsDuration("TLS_RELOAD_INTERVAL", time.Minute)
+	httpCert := getEnv("HTTP_TLS_CERT_FILE", "")
+	httpKey := getEnv("HTTP_TLS_KEY_FILE", "")
 
 	return &Config{
 		Port:        getEnv("PORT", "8080"),
@@ -35,9 +116,58 @@ func Load() *Config {
 		LogLevel:    getEnv("LOG_LEVEL", "info"),
 		Environment: getEnv("ENVIRONMENT", "development"),
 		Auth: AuthConfig{
-			JWTSecret:       getEnv("JWT_SECRET_KEY", ""),
-			TokenExpiration: tokenExp,
+			JWTSecret:              getEnv("JWT_SECRET_KEY", ""),
+			TokenExpiration:        getEnvAsPositiveDuration("JWT_EXPIRATION", 15*time.Minute),
+			RefreshTokenExpiration: getEnvAsPositiveDuration("REFRESH_TOKEN_EXPIRATION", 30*24*time.Hour),
+			BootstrapAdminEmail:    strings.ToLower(strings.TrimSpace(getEnv("BOOTSTRAP_ADMIN_EMAIL", ""))),
+			Login: LoginThrottleConfig{
+				MaxAccountFailures: getEnvAsInt("LOGIN_MAX_ACCOUNT_FAILURES", 5),
+				MaxIPFailures:      getEnvAsInt("LOGIN_MAX_IP_FAILURES", 20),
+				FailureWindow:      getEnvAsPositiveDuration("LOGIN_FAILURE_WINDOW", 15*time.Minute),
+				LockoutDuration:    getEnvAsPositiveDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+			},
+		},
+		Worker: WorkerConfig{
+			Labels:     getEnvAsMap("WORKER_LABELS"),
+			TaskTypes:  getEnvAsSlice("WORKER_TASK_TYPES"),
+			Slots:      getEnvAsInt("WORKER_SLOTS", 1),
+			TypeLimits: getEnvAsIntMap("WORKER_TYPE_LIMITS"),
+
+			DrainGracePeriod: getEnvAsDuration("WORKER_DRAIN_GRACE_PERIOD", 30*time.Second),
+			Autoscale: AutoscaleConfig{
+				Enabled:              getEnvAsBool("WORKER_AUTOSCALE", false),
+				MinWorkers:           getEnvAsInt("MIN_WORKERS", 1),
+				Interval:             getEnvAsPositiveDuration("WORKER_AUTOSCALE_INTERVAL", 10*time.Second),
+				QueuePerWorker:       getEnvAsInt("WORKER_SCALE_UP_QUEUE_PER_WORKER", 5),
+				MaxQueueAge:          getEnvAsDuration("WORKER_SCALE_UP_QUEUE_AGE", 30*time.Second),
+				ScaleUpUtilization:   getEnvAsFloat("WORKER_SCALE_UP_UTILIZATION", 0.8),
+				ScaleDownUtilization: getEnvAsFloat("WORKER_SCALE_DOWN_UTILIZATION", 0.3),
+				ScaleUpCooldown:      getEnvAsDuration("WORKER_SCALE_UP_COOLDOWN", 30*time.Second),
+				ScaleDownCooldown:    getEnvAsDuration("WORKER_SCALE_DOWN_COOLDOWN", 2*time.Minute),
+			},
+		},
+		Quota: QuotaConfig{
+			MaxPending:       getEnvAsInt("TENANT_MAX_PENDING", 0),
+			EnqueuePerMinute: getEnvAsInt("TENANT_ENQUEUE_PER_MINUTE", 0),
+			MaxRunning:       getEnvAsInt("TENANT_MAX_RUNNING", 0),
+			Weight:           getEnvAsInt("TENANT_WEIGHT", 1),
+			CacheTTL:         getEnvAsDuration("TENANT_QUOTA_CACHE_TTL", 30*time.Second),
 		},
+		HTTPTLS: TLSConfig{
+			CertFile:       httpCert,
+			KeyFile:        httpKey,
+			ClientCAFile:   getEnv("HTTP_TLS_CLIENT_CA_FILE", ""),
+			ReloadInterval: tlsReload,
+		},
+		GRPCTLS: TLSConfig{
+			CertFile:          getEnv("GRPC_TLS_CERT_FILE", httpCert),
+			KeyFile:           getEnv("GRPC_TLS_KEY_FILE", httpKey),
+			ClientCAFile:      getEnv("GRPC_TLS_CLIENT_CA_FILE", ""),
+			ReloadInterval:    tlsReload,
+			RequireWorkerCert: getEnvAsBool("GRPC_REQUIRE_WORKER_CERT", false),
+		},
+		GRPCReflection:      getEnvAsBool("GRPC_REFLECTION", false),
+		HealthCheckInterval: getEnvAsPositiveDuration("HEALTH_CHECK_INTERVAL", 5*time.Second),
 	}
 }
 
@@ -57,3 +187,72 @@ func getEnvAsInt(key string, defaultValue int) int {
 
 	return defaultValue
 }
+
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value, err := strconv.ParseFloat(getEnv(key, ""), 64); err == nil {
+		return value
+	}
+
+	return defaultValue
+}
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value, err := strconv.ParseBool(getEnv(key, "")); err == nil {
+		return value
+	}
+
+	return defaultValue
+}
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(getEnv(key, "")); err == nil {
+		return value
+	}
+
+	return defaultValue
+}
+
+// getEnvAsPositiveDuration is for settings that make no sense at zero or
+// below, such as ticker intervals and expirations; those fall back to the
+// default instead.
+func getEnvAsPositiveDuration(key string, defaultValue time.Duration) time.Duration {
+	value := getEnvAsDuration(key, defaultValue)
+	if value <= 0 {
+		log.Printf("WARNING: %s must be positive, got %v; using %v", key, value, defaultValue)
+		return defaultValue
+	}
+
+	return value
+}
+
+func getEnvAsSlice(key string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, ""), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+func getEnvAsMap(key string) map[string]string {
+	values := make(map[string]string)
+	for _, pair := range getEnvAsSlice(key) {
+		k, v, _ := strings.Cut(pair, "=")
+		values[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+
+	return values
+}
+
+func getEnvAsIntMap(key string) map[string]int {
+	values := make(map[string]int)
+	for k, v := range getEnvAsMap(key) {
+		if value, err := strconv.Atoi(v); err == nil {
+			values[k] = value
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadRejectsNonPositiveIntervals(t *testing.T) {
+	for _, value := range []string{"0s", "-5s"} {
+		t.Setenv("WORKER_AUTOSCALE_INTERVAL", value)
+		t.Setenv("LOGIN_LOCKOUT_DURATION", value)
+
+		cfg := Load()
+		if cfg.Worker.Autoscale.Interval != 10*time.Second {
+			t.Errorf("WORKER_AUTOSCALE_INTERVAL=%s: got %v, want the default", value, cfg.Worker.Autoscale.Interval)
+		}
+		if cfg.Auth.Login.LockoutDuration != 15*time.Minute {
+			t.Errorf("LOGIN_LOCKOUT_DURATION=%s: got %v, want the default", value, cfg.Auth.Login.LockoutDuration)
+		}
+	}
+
+	t.Setenv("WORKER_AUTOSCALE_INTERVAL", "3s")
+	if got := Load().Worker.Autoscale.Interval; got != 3*time.Second {
+		t.Errorf("got %v, want 3s", got)
+	}
+}
@@ -1,10 +1,12 @@
 package cron
 
 import (
+	"context"
 	"log"
 	"time"
 
 	"github.com/go-co-op/gocron/v2"
+	"github.com/rudraprasaaad/task-scheduler/internal/audit"
 	"github.com/rudraprasaaad/task-scheduler/internal/models"
 	"github.com/rudraprasaaad/task-scheduler/internal/queue"
 )
@@ -12,9 +14,10 @@ import (
 type Scheduler struct {
 	scheduler gocron.Scheduler
 	queue     *queue.RedisQueue
+	audit     audit.Recorder
 }
 
-func NewScheduler(queue *queue.RedisQueue) (*Scheduler, error) {
+func NewScheduler(queue *queue.RedisQueue, recorder audit.Recorder) (*Scheduler, error) {
 	s, err := gocron.NewScheduler()
 	if err != nil {
 		return nil, err
@@ -23,6 +26,7 @@ func NewScheduler(queue *queue.RedisQueue) (*Scheduler, error) {
 	return &Scheduler{
 		scheduler: s,
 		queue:     queue,
+		audit:     recorder,
 	}, nil
 }
 
@@ -65,10 +69,26 @@ func (s *Scheduler) enqueueDailyReportTask() {
 		payload,
 	)
 	task.Priority = models.PriorityHigh
+	task.TenantID = models.SystemTenantID
+	task.CreatedBy = "cron"
+
+	event := &models.AuditEvent{
+		ActorType:  models.ActorCron,
+		ActorID:    "daily-report",
+		TenantID:   task.TenantID,
+		Action:     audit.ActionTaskCreate,
+		TargetType: "task",
+		TargetID:   task.ID,
+	}
 
 	if err := s.queue.Enqueue(task); err != nil {
 		log.Printf("CRON ERROR: Failed to enqueue daily report task: %v", err)
+		event.Outcome = models.AuditFailure
+		event.Details = map[string]interface{}{"error": err.Error()}
 	} else {
 		log.Printf("CRON SUCCESS: Enqueued task %s for daily report generation", task.ID)
+		event.Outcome = models.AuditSuccess
 	}
+
+	s.audit.Record(context.Background(), event)
 }
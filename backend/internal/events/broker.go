@@ -0,0 +1,341 @@
+package events
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+	redisClient "github.com/rudraprasaaad/task-scheduler/internal/redis"
+)
+
+const (
+	TaskEventStream = "task_scheduler:events"
+
+	// streamMaxLen bounds how far back a client can resume from.
+	streamMaxLen     = 10000
+	replayLimit      = 1000
+	subscriberBuffer = 256
+)
+
+var ErrInvalidEventID = errors.New("invalid event id")
+
+// Publish appends the event to the task event stream and sets its ID to the
+// one Redis assigned.
+func Publish(ctx context.Context, client *redisClient.Client, event *models.TaskEvent) error {
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task event: %w", err)
+	}
+
+	id, err := client.XAdd(ctx, &redis.XAddArgs{
+		Stream: TaskEventStream,
+		MaxLen: streamMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{"event": eventJSON},
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("failed to publish task event: %w", err)
+	}
+
+	event.ID = id
+	return nil
+}
+
+// CountSince counts the events published since the given time by new status.
+// Stream IDs start with their millisecond timestamp, so this is a range read.
+func CountSince(ctx context.Context, client *redisClient.Client, since time.Time) (map[models.TaskStatus]int, error) {
+	start := strconv.FormatInt(since.UnixMilli(), 10) + "-0"
+
+	messages, err := client.XRange(ctx, TaskEventStream, start, "+").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read task events: %w", err)
+	}
+
+	counts := make(map[models.TaskStatus]int)
+	for _, message := range messages {
+		event, err := decodeMessage(message)
+		if err != nil {
+			continue
+		}
+		counts[event.NewStatus]++
+	}
+
+	return counts, nil
+}
+
+func decodeMessage(message redis.XMessage) (*models.TaskEvent, error) {
+	raw, ok := message.Values["event"].(string)
+	if !ok {
+		return nil, fmt.Errorf("event %s has no payload", message.ID)
+	}
+
+	var event models.TaskEvent
+	if err := json.Unmarshal([]byte(raw), &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event %s: %w", message.ID, err)
+	}
+	event.ID = message.ID
+
+	return &event, nil
+}
+
+// Filter selects events by task ID, task type, new status and workflow. Empty
+// fields match everything; values within a field are alternatives.
+type Filter struct {
+	TaskIDs   []string
+	Types     []string
+	Statuses  []string
+	Workflows []string
+
+	// TenantID limits the stream to one tenant's tasks when set.
+	TenantID string
+}
+
+func (f Filter) Matches(event *models.TaskEvent) bool {
+	if f.TenantID != "" && event.TenantID != f.TenantID {
+		return false
+	}
+
+	return matchAny(f.TaskIDs, event.TaskID) &&
+		matchAny(f.Types, event.TaskType) &&
+		matchAny(f.Statuses, string(event.NewStatus)) &&
+		matchAny(f.Workflows, event.Workflow)
+}
+
+func matchAny(values []string, value string) bool {
+	return len(values) == 0 || slices.Contains(values, value)
+}
+
+// Broker tails the task event stream once and fans events out to in-process
+// subscribers, so watchers do not each hold a blocking Redis connection.
+type Broker struct {
+	client *redisClient.Client
+
+	mutex       sync.Mutex
+	subscribers map[*Subscription]struct{}
+}
+
+func NewBroker(client *redisClient.Client) *Broker {
+	return &Broker{
+		client:      client,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Run reads new events until ctx is done.
+func (b *Broker) Run(ctx context.Context) {
+	lastID := b.latestID(ctx)
+
+	for ctx.Err() == nil {
+		streams, err := b.client.XRead(ctx, &redis.XReadArgs{
+			Streams: []string{TaskEventStream, lastID},
+			Count:   100,
+			Block:   5 * time.Second,
+		}).Result()
+
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Failed to read task events: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, message := range stream.Messages {
+				lastID = message.ID
+
+				event, err := decodeMessage(message)
+				if err != nil {
+					log.Printf("Skipping task event: %v", err)
+					continue
+				}
+				b.dispatch(event)
+			}
+		}
+	}
+}
+
+func (b *Broker) latestID(ctx context.Context) string {
+	latest, err := b.client.XRevRangeN(ctx, TaskEventStream, "+", "-", 1).Result()
+	if err != nil || len(latest) == 0 {
+		return "0-0"
+	}
+
+	return latest[0].ID
+}
+
+// dispatch hands the event to every matching subscriber. A subscriber whose
+// buffer is full is dropped rather than allowed to stall the others; it can
+// reconnect and resume from the last event it saw.
+func (b *Broker) dispatch(event *models.TaskEvent) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for sub := range b.subscribers {
+		if !sub.filter.Matches(event) {
+			continue
+		}
+
+		select {
+		case sub.live <- event:
+		default:
+			log.Printf("Dropping slow task event subscriber")
+			delete(b.subscribers, sub)
+			close(sub.live)
+		}
+	}
+}
+
+type Subscription struct {
+	// Events is closed when the subscription ends, either because ctx was
+	// cancelled or because the subscriber fell too far behind.
+	Events <-chan *models.TaskEvent
+
+	broker *Broker
+	filter Filter
+	live   chan *models.TaskEvent
+	out    chan *models.TaskEvent
+}
+
+// Subscribe starts delivering events that match filter. If afterID is set,
+// events after it that are still retained in the stream are replayed first.
+func (b *Broker) Subscribe(ctx context.Context, filter Filter, afterID string) (*Subscription, error) {
+	if afterID != "" {
+		if _, _, err := parseID(afterID); err != nil {
+			return nil, err
+		}
+	}
+
+	out := make(chan *models.TaskEvent, 16)
+	sub := &Subscription{
+		Events: out,
+		broker: b,
+		filter: filter,
+		live:   make(chan *models.TaskEvent, subscriberBuffer),
+		out:    out,
+	}
+
+	b.mutex.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mutex.Unlock()
+
+	var replay []*models.TaskEvent
+	if afterID != "" {
+		messages, err := b.client.XRangeN(ctx, TaskEventStream, "("+afterID, "+", replayLimit).Result()
+		if err != nil {
+			sub.Close()
+			return nil, fmt.Errorf("failed to replay task events: %w", err)
+		}
+
+		for _, message := range messages {
+			event, err := decodeMessage(message)
+			if err != nil {
+				log.Printf("Skipping task event: %v", err)
+				continue
+			}
+			if filter.Matches(event) {
+				replay = append(replay, event)
+			}
+		}
+	}
+
+	go sub.forward(ctx, replay, afterID)
+
+	return sub, nil
+}
+
+// forward sends the replayed events and then the live ones, skipping live
+// events the replay already covered.
+func (s *Subscription) forward(ctx context.Context, replay []*models.TaskEvent, lastID string) {
+	defer close(s.out)
+	defer s.Close()
+
+	for _, event := range replay {
+		select {
+		case s.out <- event:
+			lastID = event.ID
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-s.live:
+			if !ok {
+				return
+			}
+			if lastID != "" && compareIDs(event.ID, lastID) <= 0 {
+				continue
+			}
+
+			select {
+			case s.out <- event:
+				lastID = event.ID
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+func (s *Subscription) Close() {
+	s.broker.mutex.Lock()
+	defer s.broker.mutex.Unlock()
+
+	if _, ok := s.broker.subscribers[s]; ok {
+		delete(s.broker.subscribers, s)
+		close(s.live)
+	}
+}
+
+func parseID(id string) (uint64, uint64, error) {
+	msPart, seqPart, ok := strings.Cut(id, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidEventID, id)
+	}
+
+	ms, err := strconv.ParseUint(msPart, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidEventID, id)
+	}
+
+	seq, err := strconv.ParseUint(seqPart, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidEventID, id)
+	}
+
+	return ms, seq, nil
+}
+
+func compareIDs(a, b string) int {
+	aMs, aSeq, _ := parseID(a)
+	bMs, bSeq, _ := parseID(b)
+
+	if c := cmp.Compare(aMs, bMs); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(aSeq, bSeq)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/rudraprasaaad/task-scheduler/internal/models"
+	"github.com/rudraprasaaad/task-scheduler/internal/tasklog"
 )
 
 type TaskExecutor interface {
@@ -41,54 +42,78 @@ func (r *ExecutorRegistry) GetExecutor(taskType string) (TaskExecutor, error) {
 	return executor, nil
 }
 
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 type EmailExecutor struct{}
 
 func (e *EmailExecutor) Execute(ctx context.Context, task *models.Task) error {
+	logger := tasklog.FromContext(ctx)
 	to, _ := task.Payload["to"].(string)
 	subject, _ := task.Payload["subject"].(string)
 
-	fmt.Printf("📧 Sending email to %s with subject: %s\n", to, subject)
+	logger.Printf("📧 Sending email to %s with subject: %s", to, subject)
 
-	time.Sleep(time.Duration(100+int(task.Priority)*50) * time.Millisecond)
+	if err := sleep(ctx, time.Duration(100+int(task.Priority)*50)*time.Millisecond); err != nil {
+		return err
+	}
 
 	if time.Now().UnixNano()%10 == 10 {
 		return fmt.Errorf("failed to send email: SMTP Server unavailable")
 	}
 
-	fmt.Printf("✅ Email sent successfully to %s\n", to)
+	logger.Printf("✅ Email sent successfully to %s", to)
 	return nil
 }
 
 type NotificationExecutor struct{}
 
 func (n *NotificationExecutor) Execute(ctx context.Context, task *models.Task) error {
+	logger := tasklog.FromContext(ctx)
 	message, _ := task.Payload["message"].(string)
 
-	fmt.Printf("🔔 Sending notification: %s\n", message)
-	time.Sleep(50 * time.Millisecond)
+	logger.Printf("🔔 Sending notification: %s", message)
+	if err := sleep(ctx, 50*time.Millisecond); err != nil {
+		return err
+	}
 
-	fmt.Printf("Notification sent: %s\n", message)
+	logger.Printf("Notification sent: %s", message)
 	return nil
 }
 
 type ReportExecutor struct{}
 
 func (r *ReportExecutor) Execute(ctx context.Context, task *models.Task) error {
+	logger := tasklog.FromContext(ctx)
 	reportType, _ := task.Payload["report_type"].(string)
 
-	fmt.Printf("📊 Generating %s report\n", reportType)
-	time.Sleep(time.Second)
+	logger.Printf("📊 Generating %s report", reportType)
+	if err := sleep(ctx, time.Second); err != nil {
+		return err
+	}
 
-	fmt.Printf("✅ Report generated: %s\n", reportType)
+	logger.Printf("✅ Report generated: %s", reportType)
 	return nil
 }
 
 type MaintenanceExecutor struct{}
 
 func (m *MaintenanceExecutor) Execute(ctx context.Context, task *models.Task) error {
-	fmt.Printf("Running maintenance task: %s\n", task.Name)
-	time.Sleep(200 * time.Millisecond)
+	logger := tasklog.FromContext(ctx)
+	logger.Printf("Running maintenance task: %s", task.Name)
+	if err := sleep(ctx, 200*time.Millisecond); err != nil {
+		return err
+	}
 
-	fmt.Printf("Maintenance completed: %s\n", task.Name)
+	logger.Printf("Maintenance completed: %s", task.Name)
 	return nil
 }
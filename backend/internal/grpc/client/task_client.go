@@ -2,12 +2,14 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"log"
 
 	taskpb "github.com/rudraprasaaad/task-scheduler/internal/grpc/generated/task"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
@@ -16,8 +18,33 @@ type TaskClient struct {
 	client taskpb.TaskServiceClient
 }
 
-func NewTaskClient(serverAddr string) (*TaskClient, error) {
-	conn, err := grpc.NewClient(serverAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// tokenCredentials attaches the caller's JWT to every RPC. The server's auth
+// interceptor checks it against the per-method policy, so workers need a token
+// issued with the worker role.
+type tokenCredentials struct {
+	token string
+}
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + t.token}, nil
+}
+
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+// NewTaskClient dials the task service. A nil tlsConfig falls back to a
+// plain-text connection.
+func NewTaskClient(serverAddr, token string, tlsConfig *tls.Config) (*TaskClient, error) {
+	transport := insecure.NewCredentials()
+	if tlsConfig != nil {
+		transport = credentials.NewTLS(tlsConfig)
+	}
+
+	conn, err := grpc.NewClient(serverAddr,
+		grpc.WithTransportCredentials(transport),
+		grpc.WithPerRPCCredentials(tokenCredentials{token: token}),
+	)
 
 	if err != nil {
 		return nil, err
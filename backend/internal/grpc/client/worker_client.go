@@ -0,0 +1,36 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+
+	workerpb "github.com/rudraprasaaad/task-scheduler/internal/grpc/generated/worker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// EnrollWorker exchanges a single-use enrollment token for the worker's
+// long-lived credential, which is then passed as the token to NewTaskClient.
+func EnrollWorker(ctx context.Context, serverAddr string, tlsConfig *tls.Config, enrollmentToken, workerID string) (string, string, error) {
+	transport := insecure.NewCredentials()
+	if tlsConfig != nil {
+		transport = credentials.NewTLS(tlsConfig)
+	}
+
+	conn, err := grpc.NewClient(serverAddr, grpc.WithTransportCredentials(transport))
+	if err != nil {
+		return "", "", err
+	}
+	defer conn.Close()
+
+	res, err := workerpb.NewWorkerServiceClient(conn).EnrollWorker(ctx, &workerpb.EnrollWorkerRequest{
+		EnrollmentToken: enrollmentToken,
+		WorkerId:        workerID,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return res.WorkerId, res.Credential, nil
+}
@@ -0,0 +1,87 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+
+	taskpb "github.com/rudraprasaaad/task-scheduler/internal/grpc/generated/task"
+	workerpb "github.com/rudraprasaaad/task-scheduler/internal/grpc/generated/worker"
+)
+
+const bufferSize = 1 << 20
+
+// New serves the given services on an in-memory gRPC server and returns the
+// REST gateway in front of it. Gateway calls go through the same interceptors
+// as calls on the public listener, but without needing its TLS credentials.
+// The server and connection are closed when ctx is done.
+func New(ctx context.Context, opts []grpc.ServerOption, register func(grpc.ServiceRegistrar)) (http.Handler, error) {
+	listener := bufconn.Listen(bufferSize)
+
+	server := grpc.NewServer(opts...)
+	register(server)
+
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			log.Printf("Gateway gRPC server stopped: %v", err)
+		}
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///gateway",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		server.Stop()
+		return nil, fmt.Errorf("failed to connect gateway to gRPC server: %w", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+		server.GracefulStop()
+	}()
+
+	mux := runtime.NewServeMux(runtime.WithMetadata(cookieToken))
+
+	if err := taskpb.RegisterTaskServiceHandler(ctx, mux, conn); err != nil {
+		return nil, fmt.Errorf("failed to register task gateway: %w", err)
+	}
+
+	if err := workerpb.RegisterWorkerServiceHandler(ctx, mux, conn); err != nil {
+		return nil, fmt.Errorf("failed to register worker gateway: %w", err)
+	}
+
+	return mux, nil
+}
+
+// cookieToken forwards the session cookie the REST API authenticates with, so
+// browser clients can use the gateway without an Authorization header. An
+// X-API-Key header is forwarded as well, since the gateway only passes
+// Authorization through by default.
+func cookieToken(_ context.Context, r *http.Request) metadata.MD {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return metadata.Pairs("x-api-key", key)
+	}
+
+	if r.Header.Get("Authorization") != "" {
+		return nil
+	}
+
+	cookie, err := r.Cookie("auth_token")
+	if err != nil {
+		return nil
+	}
+
+	return metadata.Pairs("authorization", "Bearer "+cookie.Value)
+}
@@ -0,0 +1,8 @@
+// Package openapi embeds the OpenAPI document generated from the protos by
+// scripts/generate-proto.sh.
+package openapi
+
+import _ "embed"
+
+//go:embed task_scheduler.swagger.json
+var Spec []byte
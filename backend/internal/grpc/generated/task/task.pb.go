@@ -7,8 +7,10 @@
 package task
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -39,6 +41,9 @@ type Task struct {
 	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
 	Error         string                 `protobuf:"bytes,14,opt,name=error,proto3" json:"error,omitempty"`
 	WorkerId      string                 `protobuf:"bytes,15,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Selector      map[string]string      `protobuf:"bytes,16,rep,name=selector,proto3" json:"selector,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	TenantId      string                 `protobuf:"bytes,17,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	CreatedBy     string                 `protobuf:"bytes,18,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -178,6 +183,27 @@ func (x *Task) GetWorkerId() string {
 	return ""
 }
 
+func (x *Task) GetSelector() map[string]string {
+	if x != nil {
+		return x.Selector
+	}
+	return nil
+}
+
+func (x *Task) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *Task) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
 type CreateTaskPayload struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -186,6 +212,7 @@ type CreateTaskPayload struct {
 	Priority      int32                  `protobuf:"varint,4,opt,name=priority,proto3" json:"priority,omitempty"`
 	MaxRetries    int32                  `protobuf:"varint,5,opt,name=max_retries,json=maxRetries,proto3" json:"max_retries,omitempty"`
 	ScheduledAt   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=scheduled_at,json=scheduledAt,proto3" json:"scheduled_at,omitempty"`
+	Selector      map[string]string      `protobuf:"bytes,7,rep,name=selector,proto3" json:"selector,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -262,6 +289,13 @@ func (x *CreateTaskPayload) GetScheduledAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *CreateTaskPayload) GetSelector() map[string]string {
+	if x != nil {
+		return x.Selector
+	}
+	return nil
+}
+
 type CreateTaskRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Tasks         []*CreateTaskPayload   `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
@@ -306,16 +340,79 @@ func (x *CreateTaskRequest) GetTasks() []*CreateTaskPayload {
 	return nil
 }
 
+// CreateTaskError reports why the payload at index in the request was not
+// created.
+type CreateTaskError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTaskError) Reset() {
+	*x = CreateTaskError{}
+	mi := &file_proto_task_task_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTaskError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTaskError) ProtoMessage() {}
+
+func (x *CreateTaskError) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_task_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTaskError.ProtoReflect.Descriptor instead.
+func (*CreateTaskError) Descriptor() ([]byte, []int) {
+	return file_proto_task_task_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateTaskError) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *CreateTaskError) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateTaskError) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 type CreateTaskResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Tasks         []*Task                `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	Errors        []*CreateTaskError     `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateTaskResponse) Reset() {
 	*x = CreateTaskResponse{}
-	mi := &file_proto_task_task_proto_msgTypes[3]
+	mi := &file_proto_task_task_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -327,7 +424,7 @@ func (x *CreateTaskResponse) String() string {
 func (*CreateTaskResponse) ProtoMessage() {}
 
 func (x *CreateTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_task_task_proto_msgTypes[3]
+	mi := &file_proto_task_task_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -340,7 +437,7 @@ func (x *CreateTaskResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateTaskResponse.ProtoReflect.Descriptor instead.
 func (*CreateTaskResponse) Descriptor() ([]byte, []int) {
-	return file_proto_task_task_proto_rawDescGZIP(), []int{3}
+	return file_proto_task_task_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *CreateTaskResponse) GetTasks() []*Task {
@@ -350,6 +447,13 @@ func (x *CreateTaskResponse) GetTasks() []*Task {
 	return nil
 }
 
+func (x *CreateTaskResponse) GetErrors() []*CreateTaskError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
 type GetTaskRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
@@ -360,7 +464,7 @@ type GetTaskRequest struct {
 
 func (x *GetTaskRequest) Reset() {
 	*x = GetTaskRequest{}
-	mi := &file_proto_task_task_proto_msgTypes[4]
+	mi := &file_proto_task_task_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -372,7 +476,7 @@ func (x *GetTaskRequest) String() string {
 func (*GetTaskRequest) ProtoMessage() {}
 
 func (x *GetTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_task_task_proto_msgTypes[4]
+	mi := &file_proto_task_task_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -385,7 +489,7 @@ func (x *GetTaskRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTaskRequest.ProtoReflect.Descriptor instead.
 func (*GetTaskRequest) Descriptor() ([]byte, []int) {
-	return file_proto_task_task_proto_rawDescGZIP(), []int{4}
+	return file_proto_task_task_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetTaskRequest) GetWorkerId() string {
@@ -411,7 +515,7 @@ type GetTaskResponse struct {
 
 func (x *GetTaskResponse) Reset() {
 	*x = GetTaskResponse{}
-	mi := &file_proto_task_task_proto_msgTypes[5]
+	mi := &file_proto_task_task_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -423,7 +527,7 @@ func (x *GetTaskResponse) String() string {
 func (*GetTaskResponse) ProtoMessage() {}
 
 func (x *GetTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_task_task_proto_msgTypes[5]
+	mi := &file_proto_task_task_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -436,7 +540,7 @@ func (x *GetTaskResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTaskResponse.ProtoReflect.Descriptor instead.
 func (*GetTaskResponse) Descriptor() ([]byte, []int) {
-	return file_proto_task_task_proto_rawDescGZIP(), []int{5}
+	return file_proto_task_task_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *GetTaskResponse) GetTasks() []*Task {
@@ -455,7 +559,7 @@ type UpdateTaskRequest struct {
 
 func (x *UpdateTaskRequest) Reset() {
 	*x = UpdateTaskRequest{}
-	mi := &file_proto_task_task_proto_msgTypes[6]
+	mi := &file_proto_task_task_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -467,7 +571,7 @@ func (x *UpdateTaskRequest) String() string {
 func (*UpdateTaskRequest) ProtoMessage() {}
 
 func (x *UpdateTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_task_task_proto_msgTypes[6]
+	mi := &file_proto_task_task_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -480,7 +584,7 @@ func (x *UpdateTaskRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateTaskRequest.ProtoReflect.Descriptor instead.
 func (*UpdateTaskRequest) Descriptor() ([]byte, []int) {
-	return file_proto_task_task_proto_rawDescGZIP(), []int{6}
+	return file_proto_task_task_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *UpdateTaskRequest) GetTask() *Task {
@@ -500,7 +604,7 @@ type UpdateTaskResponse struct {
 
 func (x *UpdateTaskResponse) Reset() {
 	*x = UpdateTaskResponse{}
-	mi := &file_proto_task_task_proto_msgTypes[7]
+	mi := &file_proto_task_task_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -512,7 +616,7 @@ func (x *UpdateTaskResponse) String() string {
 func (*UpdateTaskResponse) ProtoMessage() {}
 
 func (x *UpdateTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_task_task_proto_msgTypes[7]
+	mi := &file_proto_task_task_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -525,7 +629,7 @@ func (x *UpdateTaskResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateTaskResponse.ProtoReflect.Descriptor instead.
 func (*UpdateTaskResponse) Descriptor() ([]byte, []int) {
-	return file_proto_task_task_proto_rawDescGZIP(), []int{7}
+	return file_proto_task_task_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *UpdateTaskResponse) GetSuccess() bool {
@@ -542,61 +646,780 @@ func (x *UpdateTaskResponse) GetMessage() string {
 	return ""
 }
 
-var File_proto_task_task_proto protoreflect.FileDescriptor
+type GetTaskByIdRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_proto_task_task_proto_rawDesc = "" +
-	"\n" +
-	"\x15proto/task/task.proto\x12\atask.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb2\x04\n" +
-	"\x04Task\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
-	"\x04type\x18\x03 \x01(\tR\x04type\x12!\n" +
-	"\fpayload_json\x18\x04 \x01(\tR\vpayloadJson\x12\x1a\n" +
-	"\bpriority\x18\x05 \x01(\x05R\bpriority\x12\x16\n" +
-	"\x06status\x18\x06 \x01(\tR\x06status\x12\x18\n" +
-	"\aretries\x18\a \x01(\x05R\aretries\x12\x1f\n" +
-	"\vmax_retries\x18\b \x01(\x05R\n" +
-	"maxRetries\x129\n" +
-	"\n" +
-	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
-	"\n" +
-	"updated_at\x18\n" +
-	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12=\n" +
-	"\fscheduled_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\vscheduledAt\x129\n" +
-	"\n" +
-	"started_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12=\n" +
-	"\fcompleted_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12\x14\n" +
-	"\x05error\x18\x0e \x01(\tR\x05error\x12\x1b\n" +
-	"\tworker_id\x18\x0f \x01(\tR\bworkerId\"\xda\x01\n" +
-	"\x11CreateTaskPayload\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
-	"\x04type\x18\x02 \x01(\tR\x04type\x12!\n" +
-	"\fpayload_json\x18\x03 \x01(\tR\vpayloadJson\x12\x1a\n" +
-	"\bpriority\x18\x04 \x01(\x05R\bpriority\x12\x1f\n" +
-	"\vmax_retries\x18\x05 \x01(\x05R\n" +
-	"maxRetries\x12=\n" +
-	"\fscheduled_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\vscheduledAt\"E\n" +
-	"\x11CreateTaskRequest\x120\n" +
-	"\x05tasks\x18\x01 \x03(\v2\x1a.task.v1.CreateTaskPayloadR\x05tasks\"9\n" +
-	"\x12CreateTaskResponse\x12#\n" +
-	"\x05tasks\x18\x01 \x03(\v2\r.task.v1.TaskR\x05tasks\"C\n" +
-	"\x0eGetTaskRequest\x12\x1b\n" +
-	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12\x14\n" +
-	"\x05limit\x18\x02 \x01(\x05R\x05limit\"6\n" +
-	"\x0fGetTaskResponse\x12#\n" +
-	"\x05tasks\x18\x01 \x03(\v2\r.task.v1.TaskR\x05tasks\"6\n" +
-	"\x11UpdateTaskRequest\x12!\n" +
-	"\x04task\x18\x01 \x01(\v2\r.task.v1.TaskR\x04task\"H\n" +
-	"\x12UpdateTaskResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage2\x9b\x02\n" +
-	"\vTaskService\x12E\n" +
+func (x *GetTaskByIdRequest) Reset() {
+	*x = GetTaskByIdRequest{}
+	mi := &file_proto_task_task_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskByIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskByIdRequest) ProtoMessage() {}
+
+func (x *GetTaskByIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_task_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskByIdRequest.ProtoReflect.Descriptor instead.
+func (*GetTaskByIdRequest) Descriptor() ([]byte, []int) {
+	return file_proto_task_task_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetTaskByIdRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListTasksRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Status      string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Type        string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	WorkerId    string                 `protobuf:"bytes,3,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	PageSize    int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken   string                 `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	Statuses    []string               `protobuf:"bytes,6,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	Types       []string               `protobuf:"bytes,7,rep,name=types,proto3" json:"types,omitempty"`
+	MinPriority *int32                 `protobuf:"varint,8,opt,name=min_priority,json=minPriority,proto3,oneof" json:"min_priority,omitempty"`
+	MaxPriority *int32                 `protobuf:"varint,9,opt,name=max_priority,json=maxPriority,proto3,oneof" json:"max_priority,omitempty"`
+	NamePrefix  string                 `protobuf:"bytes,10,opt,name=name_prefix,json=namePrefix,proto3" json:"name_prefix,omitempty"`
+	// JSON object the task payload must contain.
+	PayloadJson     string                 `protobuf:"bytes,11,opt,name=payload_json,json=payloadJson,proto3" json:"payload_json,omitempty"`
+	CreatedAfter    *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	CreatedBefore   *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	ScheduledAfter  *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=scheduled_after,json=scheduledAfter,proto3" json:"scheduled_after,omitempty"`
+	ScheduledBefore *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=scheduled_before,json=scheduledBefore,proto3" json:"scheduled_before,omitempty"`
+	CompletedAfter  *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=completed_after,json=completedAfter,proto3" json:"completed_after,omitempty"`
+	CompletedBefore *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=completed_before,json=completedBefore,proto3" json:"completed_before,omitempty"`
+	// One of created_at, updated_at, scheduled_at, completed_at, priority, name.
+	Sort string `protobuf:"bytes,18,opt,name=sort,proto3" json:"sort,omitempty"`
+	// asc or desc, defaults to desc.
+	Order string `protobuf:"bytes,19,opt,name=order,proto3" json:"order,omitempty"`
+	// Narrows the list to one tenant; only honoured for callers that can see
+	// every tenant.
+	TenantId      string `protobuf:"bytes,20,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksRequest) Reset() {
+	*x = ListTasksRequest{}
+	mi := &file_proto_task_task_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksRequest) ProtoMessage() {}
+
+func (x *ListTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_task_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksRequest.ProtoReflect.Descriptor instead.
+func (*ListTasksRequest) Descriptor() ([]byte, []int) {
+	return file_proto_task_task_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListTasksRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListTasksRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetStatuses() []string {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *ListTasksRequest) GetTypes() []string {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *ListTasksRequest) GetMinPriority() int32 {
+	if x != nil && x.MinPriority != nil {
+		return *x.MinPriority
+	}
+	return 0
+}
+
+func (x *ListTasksRequest) GetMaxPriority() int32 {
+	if x != nil && x.MaxPriority != nil {
+		return *x.MaxPriority
+	}
+	return 0
+}
+
+func (x *ListTasksRequest) GetNamePrefix() string {
+	if x != nil {
+		return x.NamePrefix
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetPayloadJson() string {
+	if x != nil {
+		return x.PayloadJson
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *ListTasksRequest) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+func (x *ListTasksRequest) GetScheduledAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledAfter
+	}
+	return nil
+}
+
+func (x *ListTasksRequest) GetScheduledBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledBefore
+	}
+	return nil
+}
+
+func (x *ListTasksRequest) GetCompletedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAfter
+	}
+	return nil
+}
+
+func (x *ListTasksRequest) GetCompletedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedBefore
+	}
+	return nil
+}
+
+func (x *ListTasksRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetOrder() string {
+	if x != nil {
+		return x.Order
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+type ListTasksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tasks         []*Task                `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksResponse) Reset() {
+	*x = ListTasksResponse{}
+	mi := &file_proto_task_task_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksResponse) ProtoMessage() {}
+
+func (x *ListTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_task_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksResponse.ProtoReflect.Descriptor instead.
+func (*ListTasksResponse) Descriptor() ([]byte, []int) {
+	return file_proto_task_task_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListTasksResponse) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *ListTasksResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type TaskIdRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskIdRequest) Reset() {
+	*x = TaskIdRequest{}
+	mi := &file_proto_task_task_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskIdRequest) ProtoMessage() {}
+
+func (x *TaskIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_task_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskIdRequest.ProtoReflect.Descriptor instead.
+func (*TaskIdRequest) Descriptor() ([]byte, []int) {
+	return file_proto_task_task_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *TaskIdRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetTaskStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskStatsRequest) Reset() {
+	*x = GetTaskStatsRequest{}
+	mi := &file_proto_task_task_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskStatsRequest) ProtoMessage() {}
+
+func (x *GetTaskStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_task_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetTaskStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_task_task_proto_rawDescGZIP(), []int{13}
+}
+
+type GetTaskStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ByStatus      map[string]int64       `protobuf:"bytes,1,rep,name=by_status,json=byStatus,proto3" json:"by_status,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	QueueSize     int64                  `protobuf:"varint,3,opt,name=queue_size,json=queueSize,proto3" json:"queue_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskStatsResponse) Reset() {
+	*x = GetTaskStatsResponse{}
+	mi := &file_proto_task_task_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskStatsResponse) ProtoMessage() {}
+
+func (x *GetTaskStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_task_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetTaskStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_task_task_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetTaskStatsResponse) GetByStatus() map[string]int64 {
+	if x != nil {
+		return x.ByStatus
+	}
+	return nil
+}
+
+func (x *GetTaskStatsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *GetTaskStatsResponse) GetQueueSize() int64 {
+	if x != nil {
+		return x.QueueSize
+	}
+	return 0
+}
+
+// WatchTasksRequest filters the events sent by WatchTasks. Empty fields match
+// everything. Set after_event_id to resume after the last event received.
+type WatchTasksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskIds       []string               `protobuf:"bytes,1,rep,name=task_ids,json=taskIds,proto3" json:"task_ids,omitempty"`
+	Types         []string               `protobuf:"bytes,2,rep,name=types,proto3" json:"types,omitempty"`
+	Statuses      []string               `protobuf:"bytes,3,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	Workflows     []string               `protobuf:"bytes,4,rep,name=workflows,proto3" json:"workflows,omitempty"`
+	AfterEventId  string                 `protobuf:"bytes,5,opt,name=after_event_id,json=afterEventId,proto3" json:"after_event_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchTasksRequest) Reset() {
+	*x = WatchTasksRequest{}
+	mi := &file_proto_task_task_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTasksRequest) ProtoMessage() {}
+
+func (x *WatchTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_task_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTasksRequest.ProtoReflect.Descriptor instead.
+func (*WatchTasksRequest) Descriptor() ([]byte, []int) {
+	return file_proto_task_task_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *WatchTasksRequest) GetTaskIds() []string {
+	if x != nil {
+		return x.TaskIds
+	}
+	return nil
+}
+
+func (x *WatchTasksRequest) GetTypes() []string {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *WatchTasksRequest) GetStatuses() []string {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *WatchTasksRequest) GetWorkflows() []string {
+	if x != nil {
+		return x.Workflows
+	}
+	return nil
+}
+
+func (x *WatchTasksRequest) GetAfterEventId() string {
+	if x != nil {
+		return x.AfterEventId
+	}
+	return ""
+}
+
+type TaskEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TaskId        string                 `protobuf:"bytes,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	TaskType      string                 `protobuf:"bytes,3,opt,name=task_type,json=taskType,proto3" json:"task_type,omitempty"`
+	Workflow      string                 `protobuf:"bytes,4,opt,name=workflow,proto3" json:"workflow,omitempty"`
+	OldStatus     string                 `protobuf:"bytes,5,opt,name=old_status,json=oldStatus,proto3" json:"old_status,omitempty"`
+	NewStatus     string                 `protobuf:"bytes,6,opt,name=new_status,json=newStatus,proto3" json:"new_status,omitempty"`
+	WorkerId      string                 `protobuf:"bytes,7,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Error         string                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	TenantId      string                 `protobuf:"bytes,10,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskEvent) Reset() {
+	*x = TaskEvent{}
+	mi := &file_proto_task_task_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskEvent) ProtoMessage() {}
+
+func (x *TaskEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_task_task_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskEvent.ProtoReflect.Descriptor instead.
+func (*TaskEvent) Descriptor() ([]byte, []int) {
+	return file_proto_task_task_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *TaskEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TaskEvent) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *TaskEvent) GetTaskType() string {
+	if x != nil {
+		return x.TaskType
+	}
+	return ""
+}
+
+func (x *TaskEvent) GetWorkflow() string {
+	if x != nil {
+		return x.Workflow
+	}
+	return ""
+}
+
+func (x *TaskEvent) GetOldStatus() string {
+	if x != nil {
+		return x.OldStatus
+	}
+	return ""
+}
+
+func (x *TaskEvent) GetNewStatus() string {
+	if x != nil {
+		return x.NewStatus
+	}
+	return ""
+}
+
+func (x *TaskEvent) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *TaskEvent) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *TaskEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *TaskEvent) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+var File_proto_task_task_proto protoreflect.FileDescriptor
+
+const file_proto_task_task_proto_rawDesc = "" +
+	"\n" +
+	"\x15proto/task/task.proto\x12\atask.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe4\x05\n" +
+	"\x04Task\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12!\n" +
+	"\fpayload_json\x18\x04 \x01(\tR\vpayloadJson\x12\x1a\n" +
+	"\bpriority\x18\x05 \x01(\x05R\bpriority\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x18\n" +
+	"\aretries\x18\a \x01(\x05R\aretries\x12\x1f\n" +
+	"\vmax_retries\x18\b \x01(\x05R\n" +
+	"maxRetries\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12=\n" +
+	"\fscheduled_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\vscheduledAt\x129\n" +
+	"\n" +
+	"started_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12=\n" +
+	"\fcompleted_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12\x14\n" +
+	"\x05error\x18\x0e \x01(\tR\x05error\x12\x1b\n" +
+	"\tworker_id\x18\x0f \x01(\tR\bworkerId\x127\n" +
+	"\bselector\x18\x10 \x03(\v2\x1b.task.v1.Task.SelectorEntryR\bselector\x12\x1b\n" +
+	"\ttenant_id\x18\x11 \x01(\tR\btenantId\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x12 \x01(\tR\tcreatedBy\x1a;\n" +
+	"\rSelectorEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xdd\x02\n" +
+	"\x11CreateTaskPayload\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12!\n" +
+	"\fpayload_json\x18\x03 \x01(\tR\vpayloadJson\x12\x1a\n" +
+	"\bpriority\x18\x04 \x01(\x05R\bpriority\x12\x1f\n" +
+	"\vmax_retries\x18\x05 \x01(\x05R\n" +
+	"maxRetries\x12=\n" +
+	"\fscheduled_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\vscheduledAt\x12D\n" +
+	"\bselector\x18\a \x03(\v2(.task.v1.CreateTaskPayload.SelectorEntryR\bselector\x1a;\n" +
+	"\rSelectorEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"E\n" +
+	"\x11CreateTaskRequest\x120\n" +
+	"\x05tasks\x18\x01 \x03(\v2\x1a.task.v1.CreateTaskPayloadR\x05tasks\"Q\n" +
+	"\x0fCreateTaskError\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"k\n" +
+	"\x12CreateTaskResponse\x12#\n" +
+	"\x05tasks\x18\x01 \x03(\v2\r.task.v1.TaskR\x05tasks\x120\n" +
+	"\x06errors\x18\x02 \x03(\v2\x18.task.v1.CreateTaskErrorR\x06errors\"C\n" +
+	"\x0eGetTaskRequest\x12\x1b\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"6\n" +
+	"\x0fGetTaskResponse\x12#\n" +
+	"\x05tasks\x18\x01 \x03(\v2\r.task.v1.TaskR\x05tasks\"6\n" +
+	"\x11UpdateTaskRequest\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.task.v1.TaskR\x04task\"H\n" +
+	"\x12UpdateTaskResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"$\n" +
+	"\x12GetTaskByIdRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xe2\x06\n" +
+	"\x10ListTasksRequest\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x1b\n" +
+	"\tworker_id\x18\x03 \x01(\tR\bworkerId\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x05 \x01(\tR\tpageToken\x12\x1a\n" +
+	"\bstatuses\x18\x06 \x03(\tR\bstatuses\x12\x14\n" +
+	"\x05types\x18\a \x03(\tR\x05types\x12&\n" +
+	"\fmin_priority\x18\b \x01(\x05H\x00R\vminPriority\x88\x01\x01\x12&\n" +
+	"\fmax_priority\x18\t \x01(\x05H\x01R\vmaxPriority\x88\x01\x01\x12\x1f\n" +
+	"\vname_prefix\x18\n" +
+	" \x01(\tR\n" +
+	"namePrefix\x12!\n" +
+	"\fpayload_json\x18\v \x01(\tR\vpayloadJson\x12?\n" +
+	"\rcreated_after\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBefore\x12C\n" +
+	"\x0fscheduled_after\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\x0escheduledAfter\x12E\n" +
+	"\x10scheduled_before\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\x0fscheduledBefore\x12C\n" +
+	"\x0fcompleted_after\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\x0ecompletedAfter\x12E\n" +
+	"\x10completed_before\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\x0fcompletedBefore\x12\x12\n" +
+	"\x04sort\x18\x12 \x01(\tR\x04sort\x12\x14\n" +
+	"\x05order\x18\x13 \x01(\tR\x05order\x12\x1b\n" +
+	"\ttenant_id\x18\x14 \x01(\tR\btenantIdB\x0f\n" +
+	"\r_min_priorityB\x0f\n" +
+	"\r_max_priority\"`\n" +
+	"\x11ListTasksResponse\x12#\n" +
+	"\x05tasks\x18\x01 \x03(\v2\r.task.v1.TaskR\x05tasks\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x1f\n" +
+	"\rTaskIdRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x15\n" +
+	"\x13GetTaskStatsRequest\"\xd2\x01\n" +
+	"\x14GetTaskStatsResponse\x12H\n" +
+	"\tby_status\x18\x01 \x03(\v2+.task.v1.GetTaskStatsResponse.ByStatusEntryR\bbyStatus\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\x12\x1d\n" +
+	"\n" +
+	"queue_size\x18\x03 \x01(\x03R\tqueueSize\x1a;\n" +
+	"\rByStatusEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"\xa4\x01\n" +
+	"\x11WatchTasksRequest\x12\x19\n" +
+	"\btask_ids\x18\x01 \x03(\tR\ataskIds\x12\x14\n" +
+	"\x05types\x18\x02 \x03(\tR\x05types\x12\x1a\n" +
+	"\bstatuses\x18\x03 \x03(\tR\bstatuses\x12\x1c\n" +
+	"\tworkflows\x18\x04 \x03(\tR\tworkflows\x12$\n" +
+	"\x0eafter_event_id\x18\x05 \x01(\tR\fafterEventId\"\xb5\x02\n" +
+	"\tTaskEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\atask_id\x18\x02 \x01(\tR\x06taskId\x12\x1b\n" +
+	"\ttask_type\x18\x03 \x01(\tR\btaskType\x12\x1a\n" +
+	"\bworkflow\x18\x04 \x01(\tR\bworkflow\x12\x1d\n" +
+	"\n" +
+	"old_status\x18\x05 \x01(\tR\toldStatus\x12\x1d\n" +
+	"\n" +
+	"new_status\x18\x06 \x01(\tR\tnewStatus\x12\x1b\n" +
+	"\tworker_id\x18\a \x01(\tR\bworkerId\x12\x14\n" +
+	"\x05error\x18\b \x01(\tR\x05error\x128\n" +
+	"\ttimestamp\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1b\n" +
+	"\ttenant_id\x18\n" +
+	" \x01(\tR\btenantId2\xe5\a\n" +
+	"\vTaskService\x12[\n" +
+	"\n" +
+	"CreateTask\x12\x1a.task.v1.CreateTaskRequest\x1a\x1b.task.v1.CreateTaskResponse\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/v1/tasks\x12u\n" +
+	"\x10GetAvailableTask\x12\x17.task.v1.GetTaskRequest\x1a\x18.task.v1.GetTaskResponse\".\x82\xd3\xe4\x93\x02(:\x01*\"#/v1/workers/{worker_id}/tasks:claim\x12h\n" +
+	"\n" +
+	"UpdateTask\x12\x1a.task.v1.UpdateTaskRequest\x1a\x1b.task.v1.UpdateTaskResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x04task2\x13/v1/tasks/{task.id}\x127\n" +
+	"\vStreamTasks\x12\x17.task.v1.GetTaskRequest\x1a\r.task.v1.Task0\x01\x12M\n" +
+	"\aGetTask\x12\x1b.task.v1.GetTaskByIdRequest\x1a\r.task.v1.Task\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/tasks/{id}\x12U\n" +
+	"\tListTasks\x12\x19.task.v1.ListTasksRequest\x1a\x1a.task.v1.ListTasksResponse\"\x11\x82\xd3\xe4\x93\x02\v\x12\t/v1/tasks\x12R\n" +
+	"\n" +
+	"CancelTask\x12\x16.task.v1.TaskIdRequest\x1a\r.task.v1.Task\"\x1d\x82\xd3\xe4\x93\x02\x17\"\x15/v1/tasks/{id}:cancel\x12P\n" +
+	"\tRetryTask\x12\x16.task.v1.TaskIdRequest\x1a\r.task.v1.Task\"\x1c\x82\xd3\xe4\x93\x02\x16\"\x14/v1/tasks/{id}:retry\x12T\n" +
 	"\n" +
-	"CreateTask\x12\x1a.task.v1.CreateTaskRequest\x1a\x1b.task.v1.CreateTaskResponse\x12E\n" +
-	"\x10GetAvailableTask\x12\x17.task.v1.GetTaskRequest\x1a\x18.task.v1.GetTaskResponse\x12E\n" +
+	"DeleteTask\x12\x16.task.v1.TaskIdRequest\x1a\x16.google.protobuf.Empty\"\x16\x82\xd3\xe4\x93\x02\x10*\x0e/v1/tasks/{id}\x12d\n" +
+	"\fGetTaskStats\x12\x1c.task.v1.GetTaskStatsRequest\x1a\x1d.task.v1.GetTaskStatsResponse\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/tasks:stats\x12W\n" +
 	"\n" +
-	"UpdateTask\x12\x1a.task.v1.UpdateTaskRequest\x1a\x1b.task.v1.UpdateTaskResponse\x127\n" +
-	"\vStreamTasks\x12\x17.task.v1.GetTaskRequest\x1a\r.task.v1.Task0\x01BFZDgithub.com/rudraprasaaad/task-scheduler/internal/grpc/generated/taskb\x06proto3"
+	"WatchTasks\x12\x1a.task.v1.WatchTasksRequest\x1a\x12.task.v1.TaskEvent\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/tasks:watch0\x01BFZDgithub.com/rudraprasaaad/task-scheduler/internal/grpc/generated/taskb\x06proto3"
 
 var (
 	file_proto_task_task_proto_rawDescOnce sync.Once
@@ -610,42 +1433,81 @@ func file_proto_task_task_proto_rawDescGZIP() []byte {
 	return file_proto_task_task_proto_rawDescData
 }
 
-var file_proto_task_task_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_proto_task_task_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_proto_task_task_proto_goTypes = []any{
 	(*Task)(nil),                  // 0: task.v1.Task
 	(*CreateTaskPayload)(nil),     // 1: task.v1.CreateTaskPayload
 	(*CreateTaskRequest)(nil),     // 2: task.v1.CreateTaskRequest
-	(*CreateTaskResponse)(nil),    // 3: task.v1.CreateTaskResponse
-	(*GetTaskRequest)(nil),        // 4: task.v1.GetTaskRequest
-	(*GetTaskResponse)(nil),       // 5: task.v1.GetTaskResponse
-	(*UpdateTaskRequest)(nil),     // 6: task.v1.UpdateTaskRequest
-	(*UpdateTaskResponse)(nil),    // 7: task.v1.UpdateTaskResponse
-	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
+	(*CreateTaskError)(nil),       // 3: task.v1.CreateTaskError
+	(*CreateTaskResponse)(nil),    // 4: task.v1.CreateTaskResponse
+	(*GetTaskRequest)(nil),        // 5: task.v1.GetTaskRequest
+	(*GetTaskResponse)(nil),       // 6: task.v1.GetTaskResponse
+	(*UpdateTaskRequest)(nil),     // 7: task.v1.UpdateTaskRequest
+	(*UpdateTaskResponse)(nil),    // 8: task.v1.UpdateTaskResponse
+	(*GetTaskByIdRequest)(nil),    // 9: task.v1.GetTaskByIdRequest
+	(*ListTasksRequest)(nil),      // 10: task.v1.ListTasksRequest
+	(*ListTasksResponse)(nil),     // 11: task.v1.ListTasksResponse
+	(*TaskIdRequest)(nil),         // 12: task.v1.TaskIdRequest
+	(*GetTaskStatsRequest)(nil),   // 13: task.v1.GetTaskStatsRequest
+	(*GetTaskStatsResponse)(nil),  // 14: task.v1.GetTaskStatsResponse
+	(*WatchTasksRequest)(nil),     // 15: task.v1.WatchTasksRequest
+	(*TaskEvent)(nil),             // 16: task.v1.TaskEvent
+	nil,                           // 17: task.v1.Task.SelectorEntry
+	nil,                           // 18: task.v1.CreateTaskPayload.SelectorEntry
+	nil,                           // 19: task.v1.GetTaskStatsResponse.ByStatusEntry
+	(*timestamppb.Timestamp)(nil), // 20: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),         // 21: google.protobuf.Empty
 }
 var file_proto_task_task_proto_depIdxs = []int32{
-	8,  // 0: task.v1.Task.created_at:type_name -> google.protobuf.Timestamp
-	8,  // 1: task.v1.Task.updated_at:type_name -> google.protobuf.Timestamp
-	8,  // 2: task.v1.Task.scheduled_at:type_name -> google.protobuf.Timestamp
-	8,  // 3: task.v1.Task.started_at:type_name -> google.protobuf.Timestamp
-	8,  // 4: task.v1.Task.completed_at:type_name -> google.protobuf.Timestamp
-	8,  // 5: task.v1.CreateTaskPayload.scheduled_at:type_name -> google.protobuf.Timestamp
-	1,  // 6: task.v1.CreateTaskRequest.tasks:type_name -> task.v1.CreateTaskPayload
-	0,  // 7: task.v1.CreateTaskResponse.tasks:type_name -> task.v1.Task
-	0,  // 8: task.v1.GetTaskResponse.tasks:type_name -> task.v1.Task
-	0,  // 9: task.v1.UpdateTaskRequest.task:type_name -> task.v1.Task
-	2,  // 10: task.v1.TaskService.CreateTask:input_type -> task.v1.CreateTaskRequest
-	4,  // 11: task.v1.TaskService.GetAvailableTask:input_type -> task.v1.GetTaskRequest
-	6,  // 12: task.v1.TaskService.UpdateTask:input_type -> task.v1.UpdateTaskRequest
-	4,  // 13: task.v1.TaskService.StreamTasks:input_type -> task.v1.GetTaskRequest
-	3,  // 14: task.v1.TaskService.CreateTask:output_type -> task.v1.CreateTaskResponse
-	5,  // 15: task.v1.TaskService.GetAvailableTask:output_type -> task.v1.GetTaskResponse
-	7,  // 16: task.v1.TaskService.UpdateTask:output_type -> task.v1.UpdateTaskResponse
-	0,  // 17: task.v1.TaskService.StreamTasks:output_type -> task.v1.Task
-	14, // [14:18] is the sub-list for method output_type
-	10, // [10:14] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	20, // 0: task.v1.Task.created_at:type_name -> google.protobuf.Timestamp
+	20, // 1: task.v1.Task.updated_at:type_name -> google.protobuf.Timestamp
+	20, // 2: task.v1.Task.scheduled_at:type_name -> google.protobuf.Timestamp
+	20, // 3: task.v1.Task.started_at:type_name -> google.protobuf.Timestamp
+	20, // 4: task.v1.Task.completed_at:type_name -> google.protobuf.Timestamp
+	17, // 5: task.v1.Task.selector:type_name -> task.v1.Task.SelectorEntry
+	20, // 6: task.v1.CreateTaskPayload.scheduled_at:type_name -> google.protobuf.Timestamp
+	18, // 7: task.v1.CreateTaskPayload.selector:type_name -> task.v1.CreateTaskPayload.SelectorEntry
+	1,  // 8: task.v1.CreateTaskRequest.tasks:type_name -> task.v1.CreateTaskPayload
+	0,  // 9: task.v1.CreateTaskResponse.tasks:type_name -> task.v1.Task
+	3,  // 10: task.v1.CreateTaskResponse.errors:type_name -> task.v1.CreateTaskError
+	0,  // 11: task.v1.GetTaskResponse.tasks:type_name -> task.v1.Task
+	0,  // 12: task.v1.UpdateTaskRequest.task:type_name -> task.v1.Task
+	20, // 13: task.v1.ListTasksRequest.created_after:type_name -> google.protobuf.Timestamp
+	20, // 14: task.v1.ListTasksRequest.created_before:type_name -> google.protobuf.Timestamp
+	20, // 15: task.v1.ListTasksRequest.scheduled_after:type_name -> google.protobuf.Timestamp
+	20, // 16: task.v1.ListTasksRequest.scheduled_before:type_name -> google.protobuf.Timestamp
+	20, // 17: task.v1.ListTasksRequest.completed_after:type_name -> google.protobuf.Timestamp
+	20, // 18: task.v1.ListTasksRequest.completed_before:type_name -> google.protobuf.Timestamp
+	0,  // 19: task.v1.ListTasksResponse.tasks:type_name -> task.v1.Task
+	19, // 20: task.v1.GetTaskStatsResponse.by_status:type_name -> task.v1.GetTaskStatsResponse.ByStatusEntry
+	20, // 21: task.v1.TaskEvent.timestamp:type_name -> google.protobuf.Timestamp
+	2,  // 22: task.v1.TaskService.CreateTask:input_type -> task.v1.CreateTaskRequest
+	5,  // 23: task.v1.TaskService.GetAvailableTask:input_type -> task.v1.GetTaskRequest
+	7,  // 24: task.v1.TaskService.UpdateTask:input_type -> task.v1.UpdateTaskRequest
+	5,  // 25: task.v1.TaskService.StreamTasks:input_type -> task.v1.GetTaskRequest
+	9,  // 26: task.v1.TaskService.GetTask:input_type -> task.v1.GetTaskByIdRequest
+	10, // 27: task.v1.TaskService.ListTasks:input_type -> task.v1.ListTasksRequest
+	12, // 28: task.v1.TaskService.CancelTask:input_type -> task.v1.TaskIdRequest
+	12, // 29: task.v1.TaskService.RetryTask:input_type -> task.v1.TaskIdRequest
+	12, // 30: task.v1.TaskService.DeleteTask:input_type -> task.v1.TaskIdRequest
+	13, // 31: task.v1.TaskService.GetTaskStats:input_type -> task.v1.GetTaskStatsRequest
+	15, // 32: task.v1.TaskService.WatchTasks:input_type -> task.v1.WatchTasksRequest
+	4,  // 33: task.v1.TaskService.CreateTask:output_type -> task.v1.CreateTaskResponse
+	6,  // 34: task.v1.TaskService.GetAvailableTask:output_type -> task.v1.GetTaskResponse
+	8,  // 35: task.v1.TaskService.UpdateTask:output_type -> task.v1.UpdateTaskResponse
+	0,  // 36: task.v1.TaskService.StreamTasks:output_type -> task.v1.Task
+	0,  // 37: task.v1.TaskService.GetTask:output_type -> task.v1.Task
+	11, // 38: task.v1.TaskService.ListTasks:output_type -> task.v1.ListTasksResponse
+	0,  // 39: task.v1.TaskService.CancelTask:output_type -> task.v1.Task
+	0,  // 40: task.v1.TaskService.RetryTask:output_type -> task.v1.Task
+	21, // 41: task.v1.TaskService.DeleteTask:output_type -> google.protobuf.Empty
+	14, // 42: task.v1.TaskService.GetTaskStats:output_type -> task.v1.GetTaskStatsResponse
+	16, // 43: task.v1.TaskService.WatchTasks:output_type -> task.v1.TaskEvent
+	33, // [33:44] is the sub-list for method output_type
+	22, // [22:33] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
 }
 
 func init() { file_proto_task_task_proto_init() }
@@ -653,13 +1515,14 @@ func file_proto_task_task_proto_init() {
 	if File_proto_task_task_proto != nil {
 		return
 	}
+	file_proto_task_task_proto_msgTypes[10].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_task_task_proto_rawDesc), len(file_proto_task_task_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -7,6 +7,7 @@
 package worker
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
@@ -32,6 +33,7 @@ type Worker struct {
 	MaxConcurrentTasks int32                  `protobuf:"varint,6,opt,name=max_concurrent_tasks,json=maxConcurrentTasks,proto3" json:"max_concurrent_tasks,omitempty"`
 	CurrentTasks       int32                  `protobuf:"varint,7,opt,name=current_tasks,json=currentTasks,proto3" json:"current_tasks,omitempty"`
 	Metadata           map[string]string      `protobuf:"bytes,8,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	TaskTypes          []string               `protobuf:"bytes,9,rep,name=task_types,json=taskTypes,proto3" json:"task_types,omitempty"`
 	unknownFields      protoimpl.UnknownFields
 	sizeCache          protoimpl.SizeCache
 }
@@ -122,6 +124,13 @@ func (x *Worker) GetMetadata() map[string]string {
 	return nil
 }
 
+func (x *Worker) GetTaskTypes() []string {
+	if x != nil {
+		return x.TaskTypes
+	}
+	return nil
+}
+
 type HealthRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
@@ -330,19 +339,148 @@ func (x *RegisterWorkerResponse) GetAssignedId() string {
 	return ""
 }
 
+type Instruction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Params        map[string]string      `protobuf:"bytes,3,rep,name=params,proto3" json:"params,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Instruction) Reset() {
+	*x = Instruction{}
+	mi := &file_proto_worker_worker_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Instruction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Instruction) ProtoMessage() {}
+
+func (x *Instruction) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_worker_worker_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Instruction.ProtoReflect.Descriptor instead.
+func (*Instruction) Descriptor() ([]byte, []int) {
+	return file_proto_worker_worker_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Instruction) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Instruction) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Instruction) GetParams() map[string]string {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *Instruction) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type InstructionAck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InstructionAck) Reset() {
+	*x = InstructionAck{}
+	mi := &file_proto_worker_worker_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstructionAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstructionAck) ProtoMessage() {}
+
+func (x *InstructionAck) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_worker_worker_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstructionAck.ProtoReflect.Descriptor instead.
+func (*InstructionAck) Descriptor() ([]byte, []int) {
+	return file_proto_worker_worker_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *InstructionAck) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *InstructionAck) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *InstructionAck) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 type HeartbeatRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
 	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
 	CurrentTasks  int32                  `protobuf:"varint,3,opt,name=current_tasks,json=currentTasks,proto3" json:"current_tasks,omitempty"`
 	Metrics       map[string]string      `protobuf:"bytes,4,rep,name=metrics,proto3" json:"metrics,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Acks          []*InstructionAck      `protobuf:"bytes,5,rep,name=acks,proto3" json:"acks,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *HeartbeatRequest) Reset() {
 	*x = HeartbeatRequest{}
-	mi := &file_proto_worker_worker_proto_msgTypes[5]
+	mi := &file_proto_worker_worker_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -354,7 +492,7 @@ func (x *HeartbeatRequest) String() string {
 func (*HeartbeatRequest) ProtoMessage() {}
 
 func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_worker_worker_proto_msgTypes[5]
+	mi := &file_proto_worker_worker_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -367,7 +505,7 @@ func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HeartbeatRequest.ProtoReflect.Descriptor instead.
 func (*HeartbeatRequest) Descriptor() ([]byte, []int) {
-	return file_proto_worker_worker_proto_rawDescGZIP(), []int{5}
+	return file_proto_worker_worker_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *HeartbeatRequest) GetWorkerId() string {
@@ -398,17 +536,25 @@ func (x *HeartbeatRequest) GetMetrics() map[string]string {
 	return nil
 }
 
+func (x *HeartbeatRequest) GetAcks() []*InstructionAck {
+	if x != nil {
+		return x.Acks
+	}
+	return nil
+}
+
 type HeartbeatResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Acknowledged  bool                   `protobuf:"varint,1,opt,name=acknowledged,proto3" json:"acknowledged,omitempty"`
 	Instructions  map[string]string      `protobuf:"bytes,2,rep,name=instructions,proto3" json:"instructions,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Commands      []*Instruction         `protobuf:"bytes,3,rep,name=commands,proto3" json:"commands,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *HeartbeatResponse) Reset() {
 	*x = HeartbeatResponse{}
-	mi := &file_proto_worker_worker_proto_msgTypes[6]
+	mi := &file_proto_worker_worker_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -420,7 +566,7 @@ func (x *HeartbeatResponse) String() string {
 func (*HeartbeatResponse) ProtoMessage() {}
 
 func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_worker_worker_proto_msgTypes[6]
+	mi := &file_proto_worker_worker_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -433,7 +579,7 @@ func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HeartbeatResponse.ProtoReflect.Descriptor instead.
 func (*HeartbeatResponse) Descriptor() ([]byte, []int) {
-	return file_proto_worker_worker_proto_rawDescGZIP(), []int{6}
+	return file_proto_worker_worker_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *HeartbeatResponse) GetAcknowledged() bool {
@@ -450,6 +596,117 @@ func (x *HeartbeatResponse) GetInstructions() map[string]string {
 	return nil
 }
 
+func (x *HeartbeatResponse) GetCommands() []*Instruction {
+	if x != nil {
+		return x.Commands
+	}
+	return nil
+}
+
+type EnrollWorkerRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	EnrollmentToken string                 `protobuf:"bytes,1,opt,name=enrollment_token,json=enrollmentToken,proto3" json:"enrollment_token,omitempty"`
+	WorkerId        string                 `protobuf:"bytes,2,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *EnrollWorkerRequest) Reset() {
+	*x = EnrollWorkerRequest{}
+	mi := &file_proto_worker_worker_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnrollWorkerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollWorkerRequest) ProtoMessage() {}
+
+func (x *EnrollWorkerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_worker_worker_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollWorkerRequest.ProtoReflect.Descriptor instead.
+func (*EnrollWorkerRequest) Descriptor() ([]byte, []int) {
+	return file_proto_worker_worker_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *EnrollWorkerRequest) GetEnrollmentToken() string {
+	if x != nil {
+		return x.EnrollmentToken
+	}
+	return ""
+}
+
+func (x *EnrollWorkerRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+type EnrollWorkerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Credential    string                 `protobuf:"bytes,2,opt,name=credential,proto3" json:"credential,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnrollWorkerResponse) Reset() {
+	*x = EnrollWorkerResponse{}
+	mi := &file_proto_worker_worker_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnrollWorkerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollWorkerResponse) ProtoMessage() {}
+
+func (x *EnrollWorkerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_worker_worker_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollWorkerResponse.ProtoReflect.Descriptor instead.
+func (*EnrollWorkerResponse) Descriptor() ([]byte, []int) {
+	return file_proto_worker_worker_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *EnrollWorkerResponse) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *EnrollWorkerResponse) GetCredential() string {
+	if x != nil {
+		return x.Credential
+	}
+	return ""
+}
+
 type GetWorkersRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	StatusFilter  string                 `protobuf:"bytes,1,opt,name=status_filter,json=statusFilter,proto3" json:"status_filter,omitempty"`
@@ -459,7 +716,7 @@ type GetWorkersRequest struct {
 
 func (x *GetWorkersRequest) Reset() {
 	*x = GetWorkersRequest{}
-	mi := &file_proto_worker_worker_proto_msgTypes[7]
+	mi := &file_proto_worker_worker_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -471,7 +728,7 @@ func (x *GetWorkersRequest) String() string {
 func (*GetWorkersRequest) ProtoMessage() {}
 
 func (x *GetWorkersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_worker_worker_proto_msgTypes[7]
+	mi := &file_proto_worker_worker_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -484,7 +741,7 @@ func (x *GetWorkersRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetWorkersRequest.ProtoReflect.Descriptor instead.
 func (*GetWorkersRequest) Descriptor() ([]byte, []int) {
-	return file_proto_worker_worker_proto_rawDescGZIP(), []int{7}
+	return file_proto_worker_worker_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *GetWorkersRequest) GetStatusFilter() string {
@@ -503,7 +760,7 @@ type GetWorkersResponse struct {
 
 func (x *GetWorkersResponse) Reset() {
 	*x = GetWorkersResponse{}
-	mi := &file_proto_worker_worker_proto_msgTypes[8]
+	mi := &file_proto_worker_worker_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -515,7 +772,7 @@ func (x *GetWorkersResponse) String() string {
 func (*GetWorkersResponse) ProtoMessage() {}
 
 func (x *GetWorkersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_worker_worker_proto_msgTypes[8]
+	mi := &file_proto_worker_worker_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -528,7 +785,7 @@ func (x *GetWorkersResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetWorkersResponse.ProtoReflect.Descriptor instead.
 func (*GetWorkersResponse) Descriptor() ([]byte, []int) {
-	return file_proto_worker_worker_proto_rawDescGZIP(), []int{8}
+	return file_proto_worker_worker_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *GetWorkersResponse) GetWorkers() []*Worker {
@@ -542,7 +799,7 @@ var File_proto_worker_worker_proto protoreflect.FileDescriptor
 
 const file_proto_worker_worker_proto_rawDesc = "" +
 	"\n" +
-	"\x19proto/worker/worker.proto\x12\tworker.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xf1\x02\n" +
+	"\x19proto/worker/worker.proto\x12\tworker.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x90\x03\n" +
 	"\x06Worker\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
 	"\x06status\x18\x02 \x01(\tR\x06status\x12\x18\n" +
@@ -551,7 +808,9 @@ const file_proto_worker_worker_proto_rawDesc = "" +
 	"\ttasks_run\x18\x05 \x01(\x05R\btasksRun\x120\n" +
 	"\x14max_concurrent_tasks\x18\x06 \x01(\x05R\x12maxConcurrentTasks\x12#\n" +
 	"\rcurrent_tasks\x18\a \x01(\x05R\fcurrentTasks\x12;\n" +
-	"\bmetadata\x18\b \x03(\v2\x1f.worker.v1.Worker.MetadataEntryR\bmetadata\x1a;\n" +
+	"\bmetadata\x18\b \x03(\v2\x1f.worker.v1.Worker.MetadataEntryR\bmetadata\x12\x1d\n" +
+	"\n" +
+	"task_types\x18\t \x03(\tR\ttaskTypes\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\",\n" +
@@ -570,31 +829,56 @@ const file_proto_worker_worker_proto_rawDesc = "" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1f\n" +
 	"\vassigned_id\x18\x03 \x01(\tR\n" +
-	"assignedId\"\xec\x01\n" +
+	"assignedId\"\xe3\x01\n" +
+	"\vInstruction\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12:\n" +
+	"\x06params\x18\x03 \x03(\v2\".worker.v1.Instruction.ParamsEntryR\x06params\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x1a9\n" +
+	"\vParamsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"N\n" +
+	"\x0eInstructionAck\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\x9b\x02\n" +
 	"\x10HeartbeatRequest\x12\x1b\n" +
 	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12\x16\n" +
 	"\x06status\x18\x02 \x01(\tR\x06status\x12#\n" +
 	"\rcurrent_tasks\x18\x03 \x01(\x05R\fcurrentTasks\x12B\n" +
-	"\ametrics\x18\x04 \x03(\v2(.worker.v1.HeartbeatRequest.MetricsEntryR\ametrics\x1a:\n" +
+	"\ametrics\x18\x04 \x03(\v2(.worker.v1.HeartbeatRequest.MetricsEntryR\ametrics\x12-\n" +
+	"\x04acks\x18\x05 \x03(\v2\x19.worker.v1.InstructionAckR\x04acks\x1a:\n" +
 	"\fMetricsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xcc\x01\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x80\x02\n" +
 	"\x11HeartbeatResponse\x12\"\n" +
 	"\facknowledged\x18\x01 \x01(\bR\facknowledged\x12R\n" +
-	"\finstructions\x18\x02 \x03(\v2..worker.v1.HeartbeatResponse.InstructionsEntryR\finstructions\x1a?\n" +
+	"\finstructions\x18\x02 \x03(\v2..worker.v1.HeartbeatResponse.InstructionsEntryR\finstructions\x122\n" +
+	"\bcommands\x18\x03 \x03(\v2\x16.worker.v1.InstructionR\bcommands\x1a?\n" +
 	"\x11InstructionsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"8\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"]\n" +
+	"\x13EnrollWorkerRequest\x12)\n" +
+	"\x10enrollment_token\x18\x01 \x01(\tR\x0fenrollmentToken\x12\x1b\n" +
+	"\tworker_id\x18\x02 \x01(\tR\bworkerId\"S\n" +
+	"\x14EnrollWorkerResponse\x12\x1b\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12\x1e\n" +
+	"\n" +
+	"credential\x18\x02 \x01(\tR\n" +
+	"credential\"8\n" +
 	"\x11GetWorkersRequest\x12#\n" +
 	"\rstatus_filter\x18\x01 \x01(\tR\fstatusFilter\"A\n" +
 	"\x12GetWorkersResponse\x12+\n" +
-	"\aworkers\x18\x01 \x03(\v2\x11.worker.v1.WorkerR\aworkers2\x8e\x03\n" +
-	"\rWorkerService\x12U\n" +
-	"\x0eRegisterWorker\x12 .worker.v1.RegisterWorkerRequest\x1a!.worker.v1.RegisterWorkerResponse\x12F\n" +
-	"\tHeartbeat\x12\x1b.worker.v1.HeartbeatRequest\x1a\x1c.worker.v1.HeartbeatResponse\x12B\n" +
-	"\vHealthCheck\x12\x18.worker.v1.HealthRequest\x1a\x19.worker.v1.HealthResponse\x12I\n" +
+	"\aworkers\x18\x01 \x03(\v2\x11.worker.v1.WorkerR\aworkers2\xf2\x04\n" +
+	"\rWorkerService\x12n\n" +
+	"\fEnrollWorker\x12\x1e.worker.v1.EnrollWorkerRequest\x1a\x1f.worker.v1.EnrollWorkerResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/v1/workers:enroll\x12r\n" +
+	"\x0eRegisterWorker\x12 .worker.v1.RegisterWorkerRequest\x1a!.worker.v1.RegisterWorkerResponse\"\x1b\x82\xd3\xe4\x93\x02\x15:\x06worker\"\v/v1/workers\x12t\n" +
+	"\tHeartbeat\x12\x1b.worker.v1.HeartbeatRequest\x1a\x1c.worker.v1.HeartbeatResponse\",\x82\xd3\xe4\x93\x02&:\x01*\"!/v1/workers/{worker_id}/heartbeat\x12V\n" +
+	"\vHealthCheck\x12\x18.worker.v1.HealthRequest\x1a\x19.worker.v1.HealthResponse\"\x12\x82\xd3\xe4\x93\x02\f\x12\n" +
+	"/v1/health\x12^\n" +
 	"\n" +
-	"GetWorkers\x12\x1c.worker.v1.GetWorkersRequest\x1a\x1d.worker.v1.GetWorkersResponse\x12O\n" +
+	"GetWorkers\x12\x1c.worker.v1.GetWorkersRequest\x1a\x1d.worker.v1.GetWorkersResponse\"\x13\x82\xd3\xe4\x93\x02\r\x12\v/v1/workers\x12O\n" +
 	"\x10StreamHeartbeats\x12\x1b.worker.v1.HeartbeatRequest\x1a\x1c.worker.v1.HeartbeatResponse0\x01BHZFgithub.com/rudraprasaaad/task-scheduler/internal/grpc/generated/workerb\x06proto3"
 
 var (
@@ -609,47 +893,58 @@ func file_proto_worker_worker_proto_rawDescGZIP() []byte {
 	return file_proto_worker_worker_proto_rawDescData
 }
 
-var file_proto_worker_worker_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_proto_worker_worker_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
 var file_proto_worker_worker_proto_goTypes = []any{
 	(*Worker)(nil),                 // 0: worker.v1.Worker
 	(*HealthRequest)(nil),          // 1: worker.v1.HealthRequest
 	(*HealthResponse)(nil),         // 2: worker.v1.HealthResponse
 	(*RegisterWorkerRequest)(nil),  // 3: worker.v1.RegisterWorkerRequest
 	(*RegisterWorkerResponse)(nil), // 4: worker.v1.RegisterWorkerResponse
-	(*HeartbeatRequest)(nil),       // 5: worker.v1.HeartbeatRequest
-	(*HeartbeatResponse)(nil),      // 6: worker.v1.HeartbeatResponse
-	(*GetWorkersRequest)(nil),      // 7: worker.v1.GetWorkersRequest
-	(*GetWorkersResponse)(nil),     // 8: worker.v1.GetWorkersResponse
-	nil,                            // 9: worker.v1.Worker.MetadataEntry
-	nil,                            // 10: worker.v1.HealthResponse.DetailsEntry
-	nil,                            // 11: worker.v1.HeartbeatRequest.MetricsEntry
-	nil,                            // 12: worker.v1.HeartbeatResponse.InstructionsEntry
-	(*timestamppb.Timestamp)(nil),  // 13: google.protobuf.Timestamp
+	(*Instruction)(nil),            // 5: worker.v1.Instruction
+	(*InstructionAck)(nil),         // 6: worker.v1.InstructionAck
+	(*HeartbeatRequest)(nil),       // 7: worker.v1.HeartbeatRequest
+	(*HeartbeatResponse)(nil),      // 8: worker.v1.HeartbeatResponse
+	(*EnrollWorkerRequest)(nil),    // 9: worker.v1.EnrollWorkerRequest
+	(*EnrollWorkerResponse)(nil),   // 10: worker.v1.EnrollWorkerResponse
+	(*GetWorkersRequest)(nil),      // 11: worker.v1.GetWorkersRequest
+	(*GetWorkersResponse)(nil),     // 12: worker.v1.GetWorkersResponse
+	nil,                            // 13: worker.v1.Worker.MetadataEntry
+	nil,                            // 14: worker.v1.HealthResponse.DetailsEntry
+	nil,                            // 15: worker.v1.Instruction.ParamsEntry
+	nil,                            // 16: worker.v1.HeartbeatRequest.MetricsEntry
+	nil,                            // 17: worker.v1.HeartbeatResponse.InstructionsEntry
+	(*timestamppb.Timestamp)(nil),  // 18: google.protobuf.Timestamp
 }
 var file_proto_worker_worker_proto_depIdxs = []int32{
-	13, // 0: worker.v1.Worker.last_seen:type_name -> google.protobuf.Timestamp
-	9,  // 1: worker.v1.Worker.metadata:type_name -> worker.v1.Worker.MetadataEntry
-	13, // 2: worker.v1.HealthResponse.timestamp:type_name -> google.protobuf.Timestamp
-	10, // 3: worker.v1.HealthResponse.details:type_name -> worker.v1.HealthResponse.DetailsEntry
+	18, // 0: worker.v1.Worker.last_seen:type_name -> google.protobuf.Timestamp
+	13, // 1: worker.v1.Worker.metadata:type_name -> worker.v1.Worker.MetadataEntry
+	18, // 2: worker.v1.HealthResponse.timestamp:type_name -> google.protobuf.Timestamp
+	14, // 3: worker.v1.HealthResponse.details:type_name -> worker.v1.HealthResponse.DetailsEntry
 	0,  // 4: worker.v1.RegisterWorkerRequest.worker:type_name -> worker.v1.Worker
-	11, // 5: worker.v1.HeartbeatRequest.metrics:type_name -> worker.v1.HeartbeatRequest.MetricsEntry
-	12, // 6: worker.v1.HeartbeatResponse.instructions:type_name -> worker.v1.HeartbeatResponse.InstructionsEntry
-	0,  // 7: worker.v1.GetWorkersResponse.workers:type_name -> worker.v1.Worker
-	3,  // 8: worker.v1.WorkerService.RegisterWorker:input_type -> worker.v1.RegisterWorkerRequest
-	5,  // 9: worker.v1.WorkerService.Heartbeat:input_type -> worker.v1.HeartbeatRequest
-	1,  // 10: worker.v1.WorkerService.HealthCheck:input_type -> worker.v1.HealthRequest
-	7,  // 11: worker.v1.WorkerService.GetWorkers:input_type -> worker.v1.GetWorkersRequest
-	5,  // 12: worker.v1.WorkerService.StreamHeartbeats:input_type -> worker.v1.HeartbeatRequest
-	4,  // 13: worker.v1.WorkerService.RegisterWorker:output_type -> worker.v1.RegisterWorkerResponse
-	6,  // 14: worker.v1.WorkerService.Heartbeat:output_type -> worker.v1.HeartbeatResponse
-	2,  // 15: worker.v1.WorkerService.HealthCheck:output_type -> worker.v1.HealthResponse
-	8,  // 16: worker.v1.WorkerService.GetWorkers:output_type -> worker.v1.GetWorkersResponse
-	6,  // 17: worker.v1.WorkerService.StreamHeartbeats:output_type -> worker.v1.HeartbeatResponse
-	13, // [13:18] is the sub-list for method output_type
-	8,  // [8:13] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	15, // 5: worker.v1.Instruction.params:type_name -> worker.v1.Instruction.ParamsEntry
+	18, // 6: worker.v1.Instruction.created_at:type_name -> google.protobuf.Timestamp
+	16, // 7: worker.v1.HeartbeatRequest.metrics:type_name -> worker.v1.HeartbeatRequest.MetricsEntry
+	6,  // 8: worker.v1.HeartbeatRequest.acks:type_name -> worker.v1.InstructionAck
+	17, // 9: worker.v1.HeartbeatResponse.instructions:type_name -> worker.v1.HeartbeatResponse.InstructionsEntry
+	5,  // 10: worker.v1.HeartbeatResponse.commands:type_name -> worker.v1.Instruction
+	0,  // 11: worker.v1.GetWorkersResponse.workers:type_name -> worker.v1.Worker
+	9,  // 12: worker.v1.WorkerService.EnrollWorker:input_type -> worker.v1.EnrollWorkerRequest
+	3,  // 13: worker.v1.WorkerService.RegisterWorker:input_type -> worker.v1.RegisterWorkerRequest
+	7,  // 14: worker.v1.WorkerService.Heartbeat:input_type -> worker.v1.HeartbeatRequest
+	1,  // 15: worker.v1.WorkerService.HealthCheck:input_type -> worker.v1.HealthRequest
+	11, // 16: worker.v1.WorkerService.GetWorkers:input_type -> worker.v1.GetWorkersRequest
+	7,  // 17: worker.v1.WorkerService.StreamHeartbeats:input_type -> worker.v1.HeartbeatRequest
+	10, // 18: worker.v1.WorkerService.EnrollWorker:output_type -> worker.v1.EnrollWorkerResponse
+	4,  // 19: worker.v1.WorkerService.RegisterWorker:output_type -> worker.v1.RegisterWorkerResponse
+	8,  // 20: worker.v1.WorkerService.Heartbeat:output_type -> worker.v1.HeartbeatResponse
+	2,  // 21: worker.v1.WorkerService.HealthCheck:output_type -> worker.v1.HealthResponse
+	12, // 22: worker.v1.WorkerService.GetWorkers:output_type -> worker.v1.GetWorkersResponse
+	8,  // 23: worker.v1.WorkerService.StreamHeartbeats:output_type -> worker.v1.HeartbeatResponse
+	18, // [18:24] is the sub-list for method output_type
+	12, // [12:18] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_proto_worker_worker_proto_init() }
@@ -663,7 +958,7 @@ func file_proto_worker_worker_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_worker_worker_proto_rawDesc), len(file_proto_worker_worker_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   13,
+			NumMessages:   18,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
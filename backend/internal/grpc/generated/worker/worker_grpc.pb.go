@@ -19,6 +19,7 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
+	WorkerService_EnrollWorker_FullMethodName     = "/worker.v1.WorkerService/EnrollWorker"
 	WorkerService_RegisterWorker_FullMethodName   = "/worker.v1.WorkerService/RegisterWorker"
 	WorkerService_Heartbeat_FullMethodName        = "/worker.v1.WorkerService/Heartbeat"
 	WorkerService_HealthCheck_FullMethodName      = "/worker.v1.WorkerService/HealthCheck"
@@ -30,6 +31,7 @@ const (
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type WorkerServiceClient interface {
+	EnrollWorker(ctx context.Context, in *EnrollWorkerRequest, opts ...grpc.CallOption) (*EnrollWorkerResponse, error)
 	RegisterWorker(ctx context.Context, in *RegisterWorkerRequest, opts ...grpc.CallOption) (*RegisterWorkerResponse, error)
 	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
 	HealthCheck(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error)
@@ -45,6 +47,16 @@ func NewWorkerServiceClient(cc grpc.ClientConnInterface) WorkerServiceClient {
 	return &workerServiceClient{cc}
 }
 
+func (c *workerServiceClient) EnrollWorker(ctx context.Context, in *EnrollWorkerRequest, opts ...grpc.CallOption) (*EnrollWorkerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EnrollWorkerResponse)
+	err := c.cc.Invoke(ctx, WorkerService_EnrollWorker_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *workerServiceClient) RegisterWorker(ctx context.Context, in *RegisterWorkerRequest, opts ...grpc.CallOption) (*RegisterWorkerResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(RegisterWorkerResponse)
@@ -108,6 +120,7 @@ type WorkerService_StreamHeartbeatsClient = grpc.ServerStreamingClient[Heartbeat
 // All implementations must embed UnimplementedWorkerServiceServer
 // for forward compatibility.
 type WorkerServiceServer interface {
+	EnrollWorker(context.Context, *EnrollWorkerRequest) (*EnrollWorkerResponse, error)
 	RegisterWorker(context.Context, *RegisterWorkerRequest) (*RegisterWorkerResponse, error)
 	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
 	HealthCheck(context.Context, *HealthRequest) (*HealthResponse, error)
@@ -123,6 +136,9 @@ type WorkerServiceServer interface {
 // pointer dereference when methods are called.
 type UnimplementedWorkerServiceServer struct{}
 
+func (UnimplementedWorkerServiceServer) EnrollWorker(context.Context, *EnrollWorkerRequest) (*EnrollWorkerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnrollWorker not implemented")
+}
 func (UnimplementedWorkerServiceServer) RegisterWorker(context.Context, *RegisterWorkerRequest) (*RegisterWorkerResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RegisterWorker not implemented")
 }
@@ -159,6 +175,24 @@ func RegisterWorkerServiceServer(s grpc.ServiceRegistrar, srv WorkerServiceServe
 	s.RegisterService(&WorkerService_ServiceDesc, srv)
 }
 
+func _WorkerService_EnrollWorker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnrollWorkerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServiceServer).EnrollWorker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkerService_EnrollWorker_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServiceServer).EnrollWorker(ctx, req.(*EnrollWorkerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WorkerService_RegisterWorker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RegisterWorkerRequest)
 	if err := dec(in); err != nil {
@@ -249,6 +283,10 @@ var WorkerService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "worker.v1.WorkerService",
 	HandlerType: (*WorkerServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "EnrollWorker",
+			Handler:    _WorkerService_EnrollWorker_Handler,
+		},
 		{
 			MethodName: "RegisterWorker",
 			Handler:    _WorkerService_RegisterWorker_Handler,
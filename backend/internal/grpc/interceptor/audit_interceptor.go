@@ -0,0 +1,139 @@
+package interceptor
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/audit"
+	taskpb "github.com/rudraprasaaad/task-scheduler/internal/grpc/generated/task"
+	workerpb "github.com/rudraprasaaad/task-scheduler/internal/grpc/generated/worker"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type auditedMethod struct {
+	action     string
+	targetType string
+}
+
+// auditedMethods are the RPCs that change state. Polling, heartbeats and
+// streams are left out; what they change is recorded on the task itself.
+var auditedMethods = map[string]auditedMethod{
+	taskpb.TaskService_CreateTask_FullMethodName: {"task.create", "task"},
+	taskpb.TaskService_UpdateTask_FullMethodName: {"task.report", "task"},
+	taskpb.TaskService_CancelTask_FullMethodName: {"task.cancel", "task"},
+	taskpb.TaskService_RetryTask_FullMethodName:  {"task.retry", "task"},
+	taskpb.TaskService_DeleteTask_FullMethodName: {"task.delete", "task"},
+
+	workerpb.WorkerService_EnrollWorker_FullMethodName:   {"worker.enroll", "worker"},
+	workerpb.WorkerService_RegisterWorker_FullMethodName: {"worker.register", "worker"},
+}
+
+// AuditInterceptor records every state-changing RPC in the audit log. It has
+// to run before AuthInterceptor, so calls that fail authentication are
+// recorded as well; AuthInterceptor fills in the caller.
+type AuditInterceptor struct {
+	recorder audit.Recorder
+}
+
+func NewAuditInterceptor(recorder audit.Recorder) *AuditInterceptor {
+	return &AuditInterceptor{recorder: recorder}
+}
+
+func (i *AuditInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		method, ok := auditedMethods[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		event := &models.AuditEvent{
+			Action:     method.action,
+			TargetType: method.targetType,
+			TargetID:   requestTargetID(req),
+			Method:     info.FullMethod,
+		}
+		event.IPAddress, event.UserAgent = callerAddress(ctx)
+
+		// Enrollment is public, so the worker can only be named from the
+		// request.
+		if info.FullMethod == workerpb.WorkerService_EnrollWorker_FullMethodName {
+			event.ActorType = models.ActorWorker
+			event.ActorID = event.TargetID
+		}
+
+		ctx = audit.NewContext(ctx, event)
+		resp, err := handler(ctx, req)
+
+		event.Outcome = models.AuditSuccess
+		if err != nil {
+			event.Outcome = models.AuditFailure
+			switch status.Code(err) {
+			case codes.Unauthenticated, codes.PermissionDenied:
+				event.Outcome = models.AuditDenied
+			}
+			event.Details = map[string]interface{}{"error": status.Convert(err).Message()}
+		} else if result, ok := resp.(interface {
+			GetSuccess() bool
+			GetMessage() string
+		}); ok && !result.GetSuccess() {
+			event.Outcome = models.AuditFailure
+			event.Details = map[string]interface{}{"error": result.GetMessage()}
+		}
+
+		i.recorder.Record(ctx, event)
+		return resp, err
+	}
+}
+
+func requestTargetID(req interface{}) string {
+	switch r := req.(type) {
+	case *taskpb.TaskIdRequest:
+		return r.GetId()
+	case *taskpb.UpdateTaskRequest:
+		return r.GetTask().GetId()
+	case *workerpb.EnrollWorkerRequest:
+		return r.GetWorkerId()
+	case *workerpb.RegisterWorkerRequest:
+		return r.GetWorker().GetId()
+	default:
+		return ""
+	}
+}
+
+// callerAddress returns the caller's IP and user agent. The IP is the peer's,
+// as the REST middleware uses RemoteAddr. Calls through the in-process REST
+// gateway come from bufconn; for those, only the last x-forwarded-for hop is
+// trusted, since that is the one the gateway appends from the HTTP client's
+// RemoteAddr and anything before it was sent by the client.
+func callerAddress(ctx context.Context) (ip, userAgent string) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ip = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+
+		if p.Addr.Network() == "bufconn" {
+			ip = ""
+			if forwarded := md.Get("x-forwarded-for"); len(forwarded) > 0 {
+				hops := strings.Split(forwarded[len(forwarded)-1], ",")
+				ip = strings.TrimSpace(hops[len(hops)-1])
+			}
+		}
+	}
+
+	if agents := md.Get("grpcgateway-user-agent"); len(agents) > 0 {
+		userAgent = agents[0]
+	} else if agents := md.Get("user-agent"); len(agents) > 0 {
+		userAgent = agents[0]
+	}
+
+	return ip, userAgent
+}
@@ -2,9 +2,13 @@ package interceptor
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rudraprasaaad/task-scheduler/internal/audit"
 	"github.com/rudraprasaaad/task-scheduler/internal/auth"
+	"github.com/rudraprasaaad/task-scheduler/internal/repository"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -16,39 +20,143 @@ type contextKey string
 const GrpcUserContextKey = contextKey("user_claims")
 
 type AuthInterceptor struct {
-	jwtSecret string
+	jwtSecret         string
+	credentialRepo    *repository.WorkerCredentialRepository
+	apiKeys           auth.APIKeyStore
+	revocations       auth.RevocationChecker
+	requireWorkerCert bool
 }
 
-func NewAuthInterceptor(jwtSecret string) *AuthInterceptor {
-	return &AuthInterceptor{jwtSecret: jwtSecret}
+func NewAuthInterceptor(jwtSecret string, credentialRepo *repository.WorkerCredentialRepository, apiKeys auth.APIKeyStore, revocations auth.RevocationChecker, requireWorkerCert bool) *AuthInterceptor {
+	return &AuthInterceptor{jwtSecret: jwtSecret, credentialRepo: credentialRepo, apiKeys: apiKeys, revocations: revocations, requireWorkerCert: requireWorkerCert}
 }
 
 func (i *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		newCtx, err := i.authenticate(ctx)
+		newCtx, err := i.authenticate(ctx, info.FullMethod)
 		if err != nil {
 			return nil, err
 		}
+		if err := checkWorkerIdentity(newCtx, req); err != nil {
+			return nil, err
+		}
 		return handler(newCtx, req)
 	}
 }
 
-func (i *AuthInterceptor) authenticate(ctx context.Context) (context.Context, error) {
+func (i *AuthInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		newCtx, err := i.authenticate(stream.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: newCtx})
+	}
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *authenticatedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	return checkWorkerIdentity(s.ctx, m)
+}
+
+func ClaimsFromContext(ctx context.Context) (*auth.Claims, bool) {
+	claims, ok := ctx.Value(GrpcUserContextKey).(*auth.Claims)
+	return claims, ok
+}
+
+func (i *AuthInterceptor) authenticate(ctx context.Context, method string) (context.Context, error) {
+	if publicMethods[method] {
+		return ctx, nil
+	}
+
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil, status.Error(codes.Unauthenticated, "metadata is not provided")
 	}
 
-	authHeaders := md.Get("authorization")
-	if len(authHeaders) == 0 {
+	var token string
+	if apiKeys := md.Get("x-api-key"); len(apiKeys) > 0 {
+		token = apiKeys[0]
+	} else if authHeaders := md.Get("authorization"); len(authHeaders) > 0 {
+		token = strings.TrimPrefix(authHeaders[0], "Bearer ")
+	} else {
 		return nil, status.Error(codes.Unauthenticated, "Authorization token is not provided")
 	}
 
-	token := strings.TrimPrefix(authHeaders[0], "Bearer ")
-	claims, err := auth.ValidateToken(token, i.jwtSecret)
+	var claims *auth.Claims
+	var err error
+	if auth.IsWorkerCredential(token) {
+		claims, err = i.authenticateWorker(ctx, token)
+	} else if auth.IsAPIKey(token) {
+		claims, err = auth.AuthenticateAPIKey(ctx, i.apiKeys, token)
+	} else {
+		claims, err = auth.ValidateActiveToken(ctx, token, i.jwtSecret, i.revocations)
+	}
 	if err != nil {
 		return nil, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
 	}
 
+	if err := authorize(method, claims); err != nil {
+		return nil, err
+	}
+
+	if claims.Role == auth.RoleWorker {
+		if claims.Subject == "" {
+			return nil, status.Error(codes.Unauthenticated, "worker RPCs require a worker credential")
+		}
+
+		certID := certWorkerID(ctx)
+		if certID == "" && i.requireWorkerCert {
+			return nil, status.Error(codes.Unauthenticated, "worker RPCs require a client certificate")
+		}
+		if certID != "" && certID != claims.Subject {
+			return nil, status.Errorf(codes.PermissionDenied, "client certificate for worker %q does not match credential for worker %q", certID, claims.Subject)
+		}
+
+		ctx = context.WithValue(ctx, WorkerIdentityKey, claims.Subject)
+	}
+
+	audit.SetActor(ctx, claims)
 	return context.WithValue(ctx, GrpcUserContextKey, claims), nil
 }
+
+// authenticateWorker turns a worker credential issued at enrollment into
+// worker claims whose subject is the worker ID the credential belongs to.
+func (i *AuthInterceptor) authenticateWorker(ctx context.Context, token string) (*auth.Claims, error) {
+	credentialID, secret, err := auth.ParseWorkerCredential(token)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := i.credentialRepo.GetActiveCredential(ctx, credentialID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !auth.VerifySecret(secret, credential.SecretHash) {
+		return nil, fmt.Errorf("worker credential does not match")
+	}
+
+	if err := i.credentialRepo.MarkUsed(ctx, credential.ID); err != nil {
+		return nil, err
+	}
+
+	return &auth.Claims{
+		Role: auth.RoleWorker,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject: credential.WorkerID,
+		},
+	}, nil
+}
@@ -0,0 +1,67 @@
+package interceptor
+
+import (
+	"context"
+
+	taskpb "github.com/rudraprasaaad/task-scheduler/internal/grpc/generated/task"
+	workerpb "github.com/rudraprasaaad/task-scheduler/internal/grpc/generated/worker"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const WorkerIdentityKey = contextKey("worker_identity")
+
+// certWorkerID returns the worker ID carried in the common name of a verified
+// client certificate, or "" if the caller did not present one.
+func certWorkerID(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return ""
+	}
+
+	return tlsInfo.State.VerifiedChains[0][0].Subject.CommonName
+}
+
+func requestWorkerID(req interface{}) (string, bool) {
+	switch r := req.(type) {
+	case *taskpb.GetTaskRequest:
+		return r.GetWorkerId(), true
+	case *taskpb.UpdateTaskRequest:
+		return r.GetTask().GetWorkerId(), true
+	case *workerpb.RegisterWorkerRequest:
+		return r.GetWorker().GetId(), true
+	case *workerpb.HeartbeatRequest:
+		return r.GetWorkerId(), true
+	case *workerpb.HealthRequest:
+		return r.GetWorkerId(), true
+	default:
+		return "", false
+	}
+}
+
+// checkWorkerIdentity makes sure a worker only reports on, and claims work
+// for, the worker ID its credential was issued to.
+func checkWorkerIdentity(ctx context.Context, req interface{}) error {
+	identity, ok := ctx.Value(WorkerIdentityKey).(string)
+	if !ok {
+		return nil
+	}
+
+	workerID, ok := requestWorkerID(req)
+	if !ok {
+		return nil
+	}
+
+	if workerID != identity {
+		return status.Errorf(codes.PermissionDenied, "credential for worker %q cannot act as worker %q", identity, workerID)
+	}
+
+	return nil
+}
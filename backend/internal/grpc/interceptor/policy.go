@@ -0,0 +1,63 @@
+package interceptor
+
+import (
+	"github.com/rudraprasaaad/task-scheduler/internal/auth"
+	taskpb "github.com/rudraprasaaad/task-scheduler/internal/grpc/generated/task"
+	workerpb "github.com/rudraprasaaad/task-scheduler/internal/grpc/generated/worker"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alphapb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+)
+
+// publicMethods skip authentication entirely. Enrollment authenticates with
+// the enrollment token in the request body instead. Health probes come from
+// load balancers, and reflection is only registered when GRPC_REFLECTION is
+// set; it exposes the same schema as the published OpenAPI document.
+var publicMethods = map[string]bool{
+	workerpb.WorkerService_EnrollWorker_FullMethodName: true,
+
+	healthpb.Health_Check_FullMethodName: true,
+	healthpb.Health_List_FullMethodName:  true,
+	healthpb.Health_Watch_FullMethodName: true,
+
+	reflectionpb.ServerReflection_ServerReflectionInfo_FullMethodName:        true,
+	reflectionv1alphapb.ServerReflection_ServerReflectionInfo_FullMethodName: true,
+}
+
+// methodPolicies lists the permission needed to call each RPC. Methods
+// missing from the table are denied, so new RPCs have to be added here
+// explicitly.
+var methodPolicies = map[string]auth.Permission{
+	taskpb.TaskService_CreateTask_FullMethodName:       auth.PermTaskWrite,
+	taskpb.TaskService_GetAvailableTask_FullMethodName: auth.PermWorkerAgent,
+	taskpb.TaskService_UpdateTask_FullMethodName:       auth.PermWorkerAgent,
+	taskpb.TaskService_StreamTasks_FullMethodName:      auth.PermWorkerAgent,
+	taskpb.TaskService_GetTask_FullMethodName:          auth.PermTaskRead,
+	taskpb.TaskService_ListTasks_FullMethodName:        auth.PermTaskRead,
+	taskpb.TaskService_CancelTask_FullMethodName:       auth.PermTaskWrite,
+	taskpb.TaskService_RetryTask_FullMethodName:        auth.PermTaskWrite,
+	taskpb.TaskService_DeleteTask_FullMethodName:       auth.PermTaskDelete,
+	taskpb.TaskService_GetTaskStats_FullMethodName:     auth.PermTaskRead,
+	taskpb.TaskService_WatchTasks_FullMethodName:       auth.PermTaskRead,
+
+	workerpb.WorkerService_RegisterWorker_FullMethodName:   auth.PermWorkerAgent,
+	workerpb.WorkerService_Heartbeat_FullMethodName:        auth.PermWorkerAgent,
+	workerpb.WorkerService_StreamHeartbeats_FullMethodName: auth.PermWorkerAgent,
+	workerpb.WorkerService_HealthCheck_FullMethodName:      auth.PermHealthRead,
+	workerpb.WorkerService_GetWorkers_FullMethodName:       auth.PermWorkerRead,
+}
+
+func authorize(method string, claims *auth.Claims) error {
+	permission, ok := methodPolicies[method]
+	if !ok {
+		return status.Errorf(codes.PermissionDenied, "no access policy for %s", method)
+	}
+
+	if !claims.Can(permission) {
+		return status.Errorf(codes.PermissionDenied, "%s requires the %s permission", method, permission)
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package interceptor
+
+import (
+	"testing"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/auth"
+	taskpb "github.com/rudraprasaaad/task-scheduler/internal/grpc/generated/task"
+	workerpb "github.com/rudraprasaaad/task-scheduler/internal/grpc/generated/worker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Every RPC the services expose must either be public or have a policy;
+// anything else would be denied to every caller.
+func TestEveryMethodHasAPolicy(t *testing.T) {
+	for _, service := range []grpc.ServiceDesc{taskpb.TaskService_ServiceDesc, workerpb.WorkerService_ServiceDesc} {
+		var methods []string
+		for _, method := range service.Methods {
+			methods = append(methods, "/"+service.ServiceName+"/"+method.MethodName)
+		}
+		for _, stream := range service.Streams {
+			methods = append(methods, "/"+service.ServiceName+"/"+stream.StreamName)
+		}
+
+		for _, method := range methods {
+			_, hasPolicy := methodPolicies[method]
+			if hasPolicy == publicMethods[method] {
+				t.Errorf("%s: public %v, policy %v; want exactly one", method, publicMethods[method], hasPolicy)
+			}
+		}
+	}
+}
+
+func TestAuthorizeMethods(t *testing.T) {
+	admin := &auth.Claims{Role: auth.RoleAdmin, Roles: []string{auth.RoleAdmin}}
+	user := &auth.Claims{Role: auth.RoleUser, Roles: []string{auth.RoleUser}}
+	worker := &auth.Claims{Role: auth.RoleWorker, Roles: []string{auth.RoleWorker}}
+
+	tests := []struct {
+		method  string
+		claims  *auth.Claims
+		allowed bool
+	}{
+		{taskpb.TaskService_CreateTask_FullMethodName, user, true},
+		{taskpb.TaskService_CreateTask_FullMethodName, worker, false},
+		{taskpb.TaskService_UpdateTask_FullMethodName, worker, true},
+		{taskpb.TaskService_UpdateTask_FullMethodName, user, false},
+		{taskpb.TaskService_UpdateTask_FullMethodName, admin, false},
+		{taskpb.TaskService_DeleteTask_FullMethodName, user, false},
+		{taskpb.TaskService_DeleteTask_FullMethodName, admin, true},
+		{workerpb.WorkerService_Heartbeat_FullMethodName, worker, true},
+		{workerpb.WorkerService_GetWorkers_FullMethodName, worker, false},
+		{workerpb.WorkerService_GetWorkers_FullMethodName, admin, true},
+		{"/task.TaskService/Unknown", admin, false},
+	}
+
+	for _, tt := range tests {
+		err := authorize(tt.method, tt.claims)
+		if tt.allowed && err != nil {
+			t.Errorf("%s as %s: got %v, want allowed", tt.method, tt.claims.Role, err)
+		}
+		if !tt.allowed && status.Code(err) != codes.PermissionDenied {
+			t.Errorf("%s as %s: got %v, want PermissionDenied", tt.method, tt.claims.Role, err)
+		}
+	}
+}
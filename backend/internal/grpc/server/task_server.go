@@ -3,29 +3,39 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"time"
 
 	"github.com/rudraprasaaad/task-scheduler/internal/auth"
+	"github.com/rudraprasaaad/task-scheduler/internal/events"
 	taskpb "github.com/rudraprasaaad/task-scheduler/internal/grpc/generated/task"
 	"github.com/rudraprasaaad/task-scheduler/internal/grpc/interceptor"
 	"github.com/rudraprasaaad/task-scheduler/internal/models"
+	"github.com/rudraprasaaad/task-scheduler/internal/quota"
 	"github.com/rudraprasaaad/task-scheduler/internal/repository"
+	"github.com/rudraprasaaad/task-scheduler/internal/service"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type TaskServer struct {
 	taskpb.UnimplementedTaskServiceServer
-	taskRepo   *repository.TaskRepository
+	tasks      *service.TaskService
 	workerRepo *repository.WorkerRepository
+	broker     *events.Broker
 }
 
-func NewTaskServer(taskRepo *repository.TaskRepository, workerRepo *repository.WorkerRepository) *TaskServer {
+func NewTaskServer(tasks *service.TaskService, workerRepo *repository.WorkerRepository, broker *events.Broker) *TaskServer {
 	return &TaskServer{
-		taskRepo:   taskRepo,
+		tasks:      tasks,
 		workerRepo: workerRepo,
+		broker:     broker,
 	}
 }
 
@@ -36,44 +46,64 @@ func (s *TaskServer) CreateTask(ctx context.Context, req *taskpb.CreateTaskReque
 	}
 
 	log.Printf("CreateTask request received from UserID: %s", claims.UserID)
+	ctx = scoped(ctx)
 
 	if len(req.Tasks) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "No tasks provided in request")
 	}
 
-	createdTasks := make([]*taskpb.Task, 0, len(req.Tasks))
+	response := &taskpb.CreateTaskResponse{
+		Tasks: make([]*taskpb.Task, 0, len(req.Tasks)),
+	}
 
-	for _, payload := range req.Tasks {
-		var pld map[string]interface{}
-		if err := json.Unmarshal([]byte(payload.PayloadJson), &pld); err != nil {
-			log.Printf("Failed to unmarshal payload for task '%s': %v", payload.Name, err)
+	for i, payload := range req.Tasks {
+		task, err := s.createOne(ctx, claims, payload)
+		if err != nil {
+			log.Printf("Failed to create task '%s': %v", payload.Name, err)
+			response.Errors = append(response.Errors, &taskpb.CreateTaskError{
+				Index: int32(i),
+				Name:  payload.Name,
+				Error: err.Error(),
+			})
 			continue
 		}
 
-		task := models.NewTask(payload.Name, payload.Type, pld)
-		task.Priority = models.TaskPriority(payload.Priority)
-		task.MaxRetries = int(payload.MaxRetries)
-		if payload.ScheduledAt.IsValid() {
-			task.ScheduledAt = payload.ScheduledAt.AsTime()
-		}
+		response.Tasks = append(response.Tasks, task)
+	}
 
-		if err := s.taskRepo.Create(ctx, task); err != nil {
-			log.Printf("Failed to create task '%s' in database: %v", task.Name, err)
+	return response, nil
+}
 
-			continue
+func (s *TaskServer) createOne(ctx context.Context, claims *auth.Claims, payload *taskpb.CreateTaskPayload) (*taskpb.Task, error) {
+	var pld map[string]interface{}
+	if payload.PayloadJson != "" {
+		if err := json.Unmarshal([]byte(payload.PayloadJson), &pld); err != nil {
+			return nil, fmt.Errorf("invalid payload_json: %w", err)
 		}
+	}
 
-		pbTask, err := s.modelToProto(task)
-		if err != nil {
-			log.Printf("Failed to convert created task back to proto: %v", err)
-			continue
-		}
-		createdTasks = append(createdTasks, pbTask)
+	input := service.CreateTaskInput{
+		TenantID:   service.CallerTenant(claims),
+		CreatedBy:  claims.UserID,
+		Name:       payload.Name,
+		Type:       payload.Type,
+		Payload:    pld,
+		Priority:   models.TaskPriority(payload.Priority),
+		MaxRetries: int(payload.MaxRetries),
+		Selector:   payload.Selector,
 	}
 
-	return &taskpb.CreateTaskResponse{
-		Tasks: createdTasks,
-	}, nil
+	if payload.ScheduledAt.IsValid() {
+		scheduledAt := payload.ScheduledAt.AsTime()
+		input.ScheduleAt = &scheduledAt
+	}
+
+	task, err := s.tasks.Create(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.modelToProto(task)
 }
 
 func (s *TaskServer) GetAvailableTask(ctx context.Context, req *taskpb.GetTaskRequest) (*taskpb.GetTaskResponse, error) {
@@ -85,7 +115,7 @@ func (s *TaskServer) GetAvailableTask(ctx context.Context, req *taskpb.GetTaskRe
 		limit = 10
 	}
 
-	tasks, err := s.taskRepo.GetReadyTasks(ctx, limit)
+	tasks, err := s.tasks.Claim(ctx, s.lookupWorker(ctx, req.WorkerId), limit)
 	if err != nil {
 		log.Printf("Failed to get ready tasks: %v", err)
 		return nil, status.Errorf(codes.Internal, "failed to get tasks: %v", err)
@@ -106,6 +136,8 @@ func (s *TaskServer) GetAvailableTask(ctx context.Context, req *taskpb.GetTaskRe
 	}, nil
 }
 
+// UpdateTask records a status report from the worker running a task. The
+// worker is the one its credential names, whatever the reported task says.
 func (s *TaskServer) UpdateTask(ctx context.Context, req *taskpb.UpdateTaskRequest) (*taskpb.UpdateTaskResponse, error) {
 	if req.Task == nil {
 		return &taskpb.UpdateTaskResponse{
@@ -114,24 +146,31 @@ func (s *TaskServer) UpdateTask(ctx context.Context, req *taskpb.UpdateTaskReque
 		}, nil
 	}
 
-	task, err := s.protoToModel(req.Task)
-
-	if err != nil {
-		return &taskpb.UpdateTaskResponse{
-			Success: false,
-			Message: err.Error(),
-		}, nil
+	workerID, _ := ctx.Value(interceptor.WorkerIdentityKey).(string)
+	report := service.TaskReport{
+		TaskID:   req.Task.Id,
+		WorkerID: workerID,
+		Status:   models.TaskStatus(req.Task.Status),
+		Error:    req.Task.Error,
+	}
+	if req.Task.CompletedAt != nil {
+		completedAt := req.Task.CompletedAt.AsTime()
+		report.CompletedAt = &completedAt
 	}
 
-	if err := s.taskRepo.Update(ctx, task); err != nil {
-		log.Printf("Failed to update task %s: %v", task.ID, err)
+	if _, err := s.tasks.Report(ctx, report); err != nil {
+		if errors.Is(err, service.ErrNotLeaseHolder) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+
+		log.Printf("Failed to update task %s: %v", report.TaskID, err)
 		return &taskpb.UpdateTaskResponse{
 			Success: false,
 			Message: err.Error(),
 		}, nil
 	}
 
-	log.Printf("Task %s updated successfully by worker %s", task.ID, task.WorkerID)
+	log.Printf("Task %s updated successfully by worker %s", report.TaskID, workerID)
 
 	return &taskpb.UpdateTaskResponse{
 		Success: true,
@@ -142,6 +181,11 @@ func (s *TaskServer) UpdateTask(ctx context.Context, req *taskpb.UpdateTaskReque
 func (s *TaskServer) StreamTasks(req *taskpb.GetTaskRequest, stream taskpb.TaskService_StreamTasksServer) error {
 	log.Printf("Starting task stream for worker %s", req.WorkerId)
 
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = 10
+	}
+
 	ticker := time.NewTicker(2 * time.Second)
 	defer ticker.Stop()
 
@@ -151,7 +195,9 @@ func (s *TaskServer) StreamTasks(req *taskpb.GetTaskRequest, stream taskpb.TaskS
 			log.Printf("Task stream ended for worker %s", req.WorkerId)
 			return nil
 		case <-ticker.C:
-			tasks, err := s.taskRepo.GetReadyTasks(stream.Context(), int(req.Limit))
+			// Look the worker up again each time, so its labels, limits and
+			// free slots are current.
+			tasks, err := s.tasks.Claim(stream.Context(), s.lookupWorker(stream.Context(), req.WorkerId), limit)
 
 			if err != nil {
 				log.Printf("Failed to get ready tasks for stream: %v", err)
@@ -174,6 +220,227 @@ func (s *TaskServer) StreamTasks(req *taskpb.GetTaskRequest, stream taskpb.TaskS
 	}
 }
 
+func (s *TaskServer) GetTask(ctx context.Context, req *taskpb.GetTaskByIdRequest) (*taskpb.Task, error) {
+	task, err := s.tasks.Get(scoped(ctx), req.Id)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return s.taskResponse(task)
+}
+
+func (s *TaskServer) ListTasks(ctx context.Context, req *taskpb.ListTasksRequest) (*taskpb.ListTasksResponse, error) {
+	input := service.ListTasksInput{
+		TenantID:        req.TenantId,
+		Statuses:        req.Statuses,
+		Types:           req.Types,
+		WorkerID:        req.WorkerId,
+		NamePrefix:      req.NamePrefix,
+		CreatedAfter:    optionalTime(req.CreatedAfter),
+		CreatedBefore:   optionalTime(req.CreatedBefore),
+		ScheduledAfter:  optionalTime(req.ScheduledAfter),
+		ScheduledBefore: optionalTime(req.ScheduledBefore),
+		CompletedAfter:  optionalTime(req.CompletedAfter),
+		CompletedBefore: optionalTime(req.CompletedBefore),
+		Sort:            req.Sort,
+		Order:           req.Order,
+		Limit:           int(req.PageSize),
+		PageToken:       req.PageToken,
+	}
+
+	if req.Status != "" {
+		input.Statuses = append(input.Statuses, req.Status)
+	}
+	if req.Type != "" {
+		input.Types = append(input.Types, req.Type)
+	}
+	if req.MinPriority != nil {
+		minPriority := int(*req.MinPriority)
+		input.MinPriority = &minPriority
+	}
+	if req.MaxPriority != nil {
+		maxPriority := int(*req.MaxPriority)
+		input.MaxPriority = &maxPriority
+	}
+	if req.PayloadJson != "" {
+		if err := json.Unmarshal([]byte(req.PayloadJson), &input.Payload); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "payload_json must be a JSON object: %v", err)
+		}
+	}
+
+	tasks, nextPageToken, err := s.tasks.List(scoped(ctx), input)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	pbTasks := make([]*taskpb.Task, 0, len(tasks))
+	for _, task := range tasks {
+		pbTask, err := s.modelToProto(task)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to convert task %s: %v", task.ID, err)
+		}
+		pbTasks = append(pbTasks, pbTask)
+	}
+
+	return &taskpb.ListTasksResponse{
+		Tasks:         pbTasks,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+func optionalTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+
+	t := ts.AsTime()
+	return &t
+}
+
+func (s *TaskServer) CancelTask(ctx context.Context, req *taskpb.TaskIdRequest) (*taskpb.Task, error) {
+	task, err := s.tasks.Cancel(scoped(ctx), req.Id)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return s.taskResponse(task)
+}
+
+func (s *TaskServer) RetryTask(ctx context.Context, req *taskpb.TaskIdRequest) (*taskpb.Task, error) {
+	task, err := s.tasks.Retry(scoped(ctx), req.Id, service.RetryOptions{ResetRetries: true})
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return s.taskResponse(task)
+}
+
+func (s *TaskServer) DeleteTask(ctx context.Context, req *taskpb.TaskIdRequest) (*emptypb.Empty, error) {
+	if err := s.tasks.Delete(scoped(ctx), req.Id); err != nil {
+		return nil, serviceError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *TaskServer) GetTaskStats(ctx context.Context, req *taskpb.GetTaskStatsRequest) (*taskpb.GetTaskStatsResponse, error) {
+	stats, err := s.tasks.Stats(scoped(ctx))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	byStatus := make(map[string]int64, len(stats.ByStatus))
+	for taskStatus, count := range stats.ByStatus {
+		byStatus[taskStatus] = int64(count)
+	}
+
+	return &taskpb.GetTaskStatsResponse{
+		ByStatus:  byStatus,
+		Total:     int64(stats.Total),
+		QueueSize: int64(stats.QueueSize),
+	}, nil
+}
+
+func (s *TaskServer) WatchTasks(req *taskpb.WatchTasksRequest, stream taskpb.TaskService_WatchTasksServer) error {
+	filter := events.Filter{
+		TaskIDs:   req.TaskIds,
+		Types:     req.Types,
+		Statuses:  req.Statuses,
+		Workflows: req.Workflows,
+	}
+	if claims, ok := stream.Context().Value(interceptor.GrpcUserContextKey).(*auth.Claims); ok {
+		filter.TenantID = service.TenantScope(claims)
+	}
+
+	sub, err := s.broker.Subscribe(stream.Context(), filter, req.AfterEventId)
+	if errors.Is(err, events.ErrInvalidEventID) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to watch tasks: %v", err)
+	}
+	defer sub.Close()
+
+	for event := range sub.Events {
+		if err := stream.Send(eventToProto(event)); err != nil {
+			return err
+		}
+	}
+
+	if stream.Context().Err() != nil {
+		return nil
+	}
+
+	return status.Error(codes.Unavailable, "event subscription fell behind; resume with after_event_id")
+}
+
+func eventToProto(event *models.TaskEvent) *taskpb.TaskEvent {
+	return &taskpb.TaskEvent{
+		Id:        event.ID,
+		TaskId:    event.TaskID,
+		TaskType:  event.TaskType,
+		TenantId:  event.TenantID,
+		Workflow:  event.Workflow,
+		OldStatus: string(event.OldStatus),
+		NewStatus: string(event.NewStatus),
+		WorkerId:  event.WorkerID,
+		Error:     event.Error,
+		Timestamp: timestamppb.New(event.Timestamp),
+	}
+}
+
+func (s *TaskServer) taskResponse(task *models.Task) (*taskpb.Task, error) {
+	pbTask, err := s.modelToProto(task)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to convert task %s: %v", task.ID, err)
+	}
+
+	return pbTask, nil
+}
+
+// scoped limits the task operations of a user RPC to the caller's tenant.
+func scoped(ctx context.Context) context.Context {
+	claims, ok := ctx.Value(interceptor.GrpcUserContextKey).(*auth.Claims)
+	if !ok {
+		return ctx
+	}
+	return service.WithTenant(ctx, service.TenantScope(claims))
+}
+
+func serviceError(err error) error {
+	var exceeded *quota.ExceededError
+
+	switch {
+	case errors.As(err, &exceeded):
+		st, detailErr := status.New(codes.ResourceExhausted, err.Error()).WithDetails(&errdetails.RetryInfo{
+			RetryDelay: durationpb.New(exceeded.RetryAfter),
+		})
+		if detailErr != nil {
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+		return st.Err()
+	case errors.Is(err, service.ErrTaskNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidTask):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrInvalidState):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		log.Printf("Task service error: %v", err)
+		return status.Errorf(codes.Internal, "internal error: %v", err)
+	}
+}
+
+func (s *TaskServer) lookupWorker(ctx context.Context, workerID string) *models.Worker {
+	worker, err := s.workerRepo.GetByID(ctx, workerID)
+	if err != nil {
+		log.Printf("Worker %s is not registered, only offering tasks without a selector: %v", workerID, err)
+		return &models.Worker{ID: workerID}
+	}
+
+	return worker
+}
+
 func (s *TaskServer) modelToProto(task *models.Task) (*taskpb.Task, error) {
 	payloadJSON, err := json.Marshal(task.Payload)
 	if err != nil {
@@ -194,6 +461,9 @@ func (s *TaskServer) modelToProto(task *models.Task) (*taskpb.Task, error) {
 		ScheduledAt: timestamppb.New(task.ScheduledAt),
 		Error:       task.Error,
 		WorkerId:    task.WorkerID,
+		Selector:    task.Selector,
+		TenantId:    task.TenantID,
+		CreatedBy:   task.CreatedBy,
 	}
 
 	if task.StartedAt != nil {
@@ -206,37 +476,3 @@ func (s *TaskServer) modelToProto(task *models.Task) (*taskpb.Task, error) {
 
 	return pbTask, nil
 }
-
-func (s *TaskServer) protoToModel(pbTask *taskpb.Task) (*models.Task, error) {
-	var payload map[string]interface{}
-
-	if err := json.Unmarshal([]byte(pbTask.PayloadJson), &payload); err != nil {
-		return nil, err
-	}
-
-	task := &models.Task{
-		ID:        pbTask.Id,
-		Name:      pbTask.Name,
-		Type:      pbTask.Type,
-		Payload:   payload,
-		Priority:  models.TaskPriority(pbTask.Priority),
-		Status:    models.TaskStatus(pbTask.Status),
-		Retries:   int(pbTask.Retries),
-		CreatedAt: pbTask.CreatedAt.AsTime(),
-		UpdatedAt: pbTask.UpdatedAt.AsTime(),
-		Error:     pbTask.Error,
-		WorkerID:  pbTask.WorkerId,
-	}
-
-	if pbTask.StartedAt != nil {
-		startedAt := pbTask.StartedAt.AsTime()
-		task.StartedAt = &startedAt
-	}
-
-	if pbTask.CompletedAt != nil {
-		completedAt := pbTask.CompletedAt.AsTime()
-		task.CompletedAt = &completedAt
-	}
-
-	return task, nil
-}
@@ -6,7 +6,10 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/rudraprasaaad/task-scheduler/internal/auth"
 	workerpb "github.com/rudraprasaaad/task-scheduler/internal/grpc/generated/worker"
+	"github.com/rudraprasaaad/task-scheduler/internal/health"
 	"github.com/rudraprasaaad/task-scheduler/internal/models"
 	"github.com/rudraprasaaad/task-scheduler/internal/repository"
 	"google.golang.org/grpc/codes"
@@ -16,18 +19,153 @@ import (
 
 type WorkerServer struct {
 	workerpb.UnimplementedWorkerServiceServer
-	workerRepo   *repository.WorkerRepository
-	activeStream map[string]chan *workerpb.HeartbeatResponse
-	streamsMutex sync.RWMutex
+	workerRepo      *repository.WorkerRepository
+	instructionRepo *repository.WorkerInstructionRepository
+	credentialRepo  *repository.WorkerCredentialRepository
+	health          *health.Checker
+	activeStream    map[string]chan *workerpb.HeartbeatResponse
+	streamsMutex    sync.RWMutex
 }
 
-func NewWorkerServer(workerRepo *repository.WorkerRepository) *WorkerServer {
+func NewWorkerServer(workerRepo *repository.WorkerRepository, instructionRepo *repository.WorkerInstructionRepository, credentialRepo *repository.WorkerCredentialRepository, healthChecker *health.Checker) *WorkerServer {
 	return &WorkerServer{
-		workerRepo:   workerRepo,
-		activeStream: make(map[string]chan *workerpb.HeartbeatResponse),
+		workerRepo:      workerRepo,
+		instructionRepo: instructionRepo,
+		credentialRepo:  credentialRepo,
+		health:          healthChecker,
+		activeStream:    make(map[string]chan *workerpb.HeartbeatResponse),
 	}
 }
 
+// NotifyInstructions pushes a worker's outstanding instructions down its
+// heartbeat stream if it has one open. Workers without a stream pick them up
+// on their next Heartbeat. Instructions dropped here because the stream's
+// buffer is full are sent again once their redelivery delay has passed.
+func (s *WorkerServer) NotifyInstructions(ctx context.Context, workerID string) {
+	s.streamsMutex.RLock()
+	_, streaming := s.activeStream[workerID]
+	s.streamsMutex.RUnlock()
+
+	if !streaming {
+		return
+	}
+
+	commands, err := s.takeCommands(ctx, workerID)
+	if err != nil {
+		log.Printf("Failed to load instructions for worker %s: %v", workerID, err)
+		return
+	}
+
+	if len(commands) == 0 {
+		return
+	}
+
+	s.streamsMutex.RLock()
+	if stream, exists := s.activeStream[workerID]; exists {
+		select {
+		case stream <- &workerpb.HeartbeatResponse{Acknowledged: true, Commands: commands}:
+		default:
+		}
+	}
+	s.streamsMutex.RUnlock()
+}
+
+func (s *WorkerServer) takeCommands(ctx context.Context, workerID string) ([]*workerpb.Instruction, error) {
+	instructions, err := s.instructionRepo.TakeUndelivered(ctx, workerID)
+	if err != nil {
+		return nil, err
+	}
+
+	commands := make([]*workerpb.Instruction, 0, len(instructions))
+	for _, instruction := range instructions {
+		commands = append(commands, &workerpb.Instruction{
+			Id:        instruction.ID,
+			Type:      string(instruction.Type),
+			Params:    instruction.Params,
+			CreatedAt: timestamppb.New(instruction.CreatedAt),
+		})
+	}
+
+	return commands, nil
+}
+
+func (s *WorkerServer) recordAcks(ctx context.Context, workerID string, acks []*workerpb.InstructionAck) {
+	for _, ack := range acks {
+		ackStatus := models.InstructionStatus(ack.Status)
+		switch ackStatus {
+		case models.InstructionStatusAcknowledged, models.InstructionStatusApplied, models.InstructionStatusFailed:
+		default:
+			log.Printf("Worker %s sent an invalid status %q for instruction %s", workerID, ack.Status, ack.Id)
+			continue
+		}
+
+		if err := s.instructionRepo.Acknowledge(ctx, ack.Id, workerID, ackStatus, ack.Error); err != nil {
+			log.Printf("Failed to record ack for instruction %s: %v", ack.Id, err)
+		}
+	}
+}
+
+// EnrollWorker exchanges a single-use enrollment token for a long-lived worker
+// credential. A token minted for a specific worker ID can only enroll that
+// worker and replaces its existing credentials; an open token cannot be used
+// to take over a worker ID that already holds a credential.
+func (s *WorkerServer) EnrollWorker(ctx context.Context, req *workerpb.EnrollWorkerRequest) (*workerpb.EnrollWorkerResponse, error) {
+	if req.EnrollmentToken == "" {
+		return nil, status.Error(codes.InvalidArgument, "enrollment_token is required")
+	}
+
+	token, err := s.credentialRepo.GetUsableEnrollmentToken(ctx, auth.HashSecret(req.EnrollmentToken))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired enrollment token")
+	}
+
+	workerID := req.WorkerId
+	if token.WorkerID != "" {
+		if workerID != "" && workerID != token.WorkerID {
+			return nil, status.Errorf(codes.PermissionDenied, "enrollment token is bound to worker %s", token.WorkerID)
+		}
+		workerID = token.WorkerID
+	}
+
+	if workerID == "" {
+		return nil, status.Error(codes.InvalidArgument, "worker_id is required")
+	}
+
+	if token.WorkerID == "" {
+		enrolled, err := s.credentialRepo.HasActiveCredential(ctx, workerID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to enroll worker: %v", err)
+		}
+		if enrolled {
+			return nil, status.Errorf(codes.AlreadyExists, "worker %s is already enrolled; use a token bound to it to rotate its credential", workerID)
+		}
+	}
+
+	credential := &models.WorkerCredential{
+		ID:        uuid.New().String(),
+		WorkerID:  workerID,
+		CreatedAt: time.Now(),
+	}
+
+	secret, credentialString, err := auth.GenerateWorkerCredential(credential.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to enroll worker: %v", err)
+	}
+	credential.SecretHash = auth.HashSecret(secret)
+
+	if err := s.credentialRepo.Enroll(ctx, token.ID, credential); err != nil {
+		log.Printf("Failed to enroll worker %s: %v", workerID, err)
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired enrollment token")
+	}
+
+	log.Printf("Worker %s enrolled", workerID)
+
+	return &workerpb.EnrollWorkerResponse{
+		WorkerId:   workerID,
+		Credential: credentialString,
+	}, nil
+}
+
 func (s *WorkerServer) RegisterWorker(ctx context.Context, req *workerpb.RegisterWorkerRequest) (*workerpb.RegisterWorkerResponse, error) {
 	if req.Worker == nil {
 		return &workerpb.RegisterWorkerResponse{
@@ -63,7 +201,7 @@ func (s *WorkerServer) Heartbeat(ctx context.Context, req *workerpb.HeartbeatReq
 		return nil, status.Errorf(codes.InvalidArgument, "worker_id is required")
 	}
 
-	if err := s.workerRepo.UpdateStatus(ctx, req.WorkerId, models.WorkerStatus(req.Status)); err != nil {
+	if err := s.workerRepo.UpdateLoad(ctx, req.WorkerId, models.WorkerStatus(req.Status), int(req.CurrentTasks)); err != nil {
 		log.Printf("Failed to update worker %s status: %v", req.WorkerId, err)
 
 		return &workerpb.HeartbeatResponse{
@@ -74,6 +212,8 @@ func (s *WorkerServer) Heartbeat(ctx context.Context, req *workerpb.HeartbeatReq
 		}, nil
 	}
 
+	s.recordAcks(ctx, req.WorkerId, req.Acks)
+
 	response := &workerpb.HeartbeatResponse{
 		Acknowledged: true,
 		Instructions: map[string]string{
@@ -81,8 +221,11 @@ func (s *WorkerServer) Heartbeat(ctx context.Context, req *workerpb.HeartbeatReq
 		},
 	}
 
+	// A worker with a stream open gets its instructions there only, so one
+	// using both does not apply them twice.
 	s.streamsMutex.RLock()
-	if stream, exists := s.activeStream[req.WorkerId]; exists {
+	stream, streaming := s.activeStream[req.WorkerId]
+	if streaming {
 		select {
 		case stream <- response:
 		default:
@@ -90,17 +233,49 @@ func (s *WorkerServer) Heartbeat(ctx context.Context, req *workerpb.HeartbeatReq
 	}
 	s.streamsMutex.RUnlock()
 
-	return response, nil
+	if streaming {
+		s.NotifyInstructions(ctx, req.WorkerId)
+		return response, nil
+	}
+
+	commands, err := s.takeCommands(ctx, req.WorkerId)
+	if err != nil {
+		log.Printf("Failed to load instructions for worker %s: %v", req.WorkerId, err)
+	}
+
+	return &workerpb.HeartbeatResponse{
+		Acknowledged: response.Acknowledged,
+		Instructions: response.Instructions,
+		Commands:     commands,
+	}, nil
 }
 
 func (s *WorkerServer) HealthCheck(ctx context.Context, req *workerpb.HealthRequest) (*workerpb.HealthResponse, error) {
+	report := s.health.Report()
+	if report == nil {
+		report = s.health.Check(ctx)
+	}
+
+	details := map[string]string{
+		"service": "worker-service",
+		"version": "1.0.0",
+	}
+	for component, err := range report.Components {
+		details[component] = "ok"
+		if err != nil {
+			details[component] = err.Error()
+		}
+	}
+
+	healthStatus := "healthy"
+	if !report.Healthy {
+		healthStatus = "unhealthy"
+	}
+
 	return &workerpb.HealthResponse{
-		Status:    "healthy",
-		Timestamp: timestamppb.New(time.Now()),
-		Details: map[string]string{
-			"service": "worker-service",
-			"version": "1.0.0",
-		},
+		Status:    healthStatus,
+		Timestamp: timestamppb.New(report.CheckedAt),
+		Details:   details,
 	}, nil
 }
 
@@ -140,6 +315,9 @@ func (s *WorkerServer) StreamHeartbeats(req *workerpb.HeartbeatRequest, stream w
 		log.Printf("Heartbeat stream ended for worker %s", workerID)
 	}()
 
+	s.recordAcks(stream.Context(), workerID, req.Acks)
+	go s.NotifyInstructions(stream.Context(), workerID)
+
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
 
@@ -171,18 +349,26 @@ func (s *WorkerServer) StreamHeartbeats(req *workerpb.HeartbeatRequest, stream w
 
 func (s *WorkerServer) protoToModel(pbWorker *workerpb.Worker) *models.Worker {
 	return &models.Worker{
-		ID:       pbWorker.Id,
-		Status:   models.WorkerStatus(pbWorker.Status),
-		LastSeen: pbWorker.LastSeen.AsTime(),
-		TasksRun: int(pbWorker.TasksRun),
+		ID:                 pbWorker.Id,
+		Status:             models.WorkerStatus(pbWorker.Status),
+		LastSeen:           pbWorker.LastSeen.AsTime(),
+		TasksRun:           int(pbWorker.TasksRun),
+		Labels:             pbWorker.Metadata,
+		TaskTypes:          pbWorker.TaskTypes,
+		MaxConcurrentTasks: int(pbWorker.MaxConcurrentTasks),
+		CurrentTasks:       int(pbWorker.CurrentTasks),
 	}
 }
 
 func (s *WorkerServer) modelToProto(worker *models.Worker) *workerpb.Worker {
 	return &workerpb.Worker{
-		Id:       worker.ID,
-		Status:   string(worker.Status),
-		LastSeen: timestamppb.New(worker.LastSeen),
-		TasksRun: int32(worker.TasksRun),
+		Id:                 worker.ID,
+		Status:             string(worker.Status),
+		LastSeen:           timestamppb.New(worker.LastSeen),
+		TasksRun:           int32(worker.TasksRun),
+		Metadata:           worker.Labels,
+		TaskTypes:          worker.TaskTypes,
+		MaxConcurrentTasks: int32(worker.MaxConcurrentTasks),
+		CurrentTasks:       int32(worker.CurrentTasks),
 	}
 }
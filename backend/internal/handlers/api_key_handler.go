@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/rudraprasaaad/task-scheduler/internal/audit"
+	"github.com/rudraprasaaad/task-scheduler/internal/auth"
+	"github.com/rudraprasaaad/task-scheduler/internal/middleware"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+	"github.com/rudraprasaaad/task-scheduler/internal/repository"
+	"github.com/rudraprasaaad/task-scheduler/internal/service"
+)
+
+var serviceAccountNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,99}$`)
+
+// APIKeyHandler manages service accounts and their API keys. Callers only see
+// and manage accounts in their own tenant unless they can reach all tenants.
+type APIKeyHandler struct {
+	apiKeys *repository.APIKeyRepository
+}
+
+func NewAPIKeyHandler(apiKeys *repository.APIKeyRepository) *APIKeyHandler {
+	return &APIKeyHandler{apiKeys: apiKeys}
+}
+
+type CreateServiceAccountRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	TenantID    string `json:"tenant_id,omitempty"`
+}
+
+type CreateAPIKeyRequest struct {
+	ServiceAccount string   `json:"service_account"`
+	Name           string   `json:"name"`
+	Scopes         []string `json:"scopes"`
+	ExpiresIn      string   `json:"expires_in,omitempty"`
+}
+
+func requestClaims(r *http.Request) *auth.Claims {
+	claims, _ := r.Context().Value(middleware.UserContextkey).(*auth.Claims)
+	return claims
+}
+
+func (h *APIKeyHandler) ListServiceAccounts(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	accounts, err := h.apiKeys.ListServiceAccounts(ctx, service.TenantScope(requestClaims(r)))
+	if err != nil {
+		writeAPIKeyError(w, err, "Failed to list service accounts")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"service_accounts": accounts,
+		"count":            len(accounts),
+	})
+}
+
+func (h *APIKeyHandler) CreateServiceAccount(w http.ResponseWriter, r *http.Request) {
+	var req CreateServiceAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if !serviceAccountNamePattern.MatchString(req.Name) {
+		http.Error(w, "Service account name must be lowercase letters, digits, dots, dashes and underscores, at most 100 characters", http.StatusBadRequest)
+		return
+	}
+
+	claims := requestClaims(r)
+	tenantID := service.CallerTenant(claims)
+	if req.TenantID != "" && req.TenantID != tenantID {
+		if service.TenantScope(claims) != "" {
+			http.Error(w, "Forbidden: service accounts can only be created in your own tenant", http.StatusForbidden)
+			return
+		}
+		tenantID = req.TenantID
+	}
+
+	account := models.NewServiceAccount(req.Name, strings.TrimSpace(req.Description), tenantID)
+	account.CreatedBy = claims.UserID
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.apiKeys.CreateServiceAccount(ctx, account); err != nil {
+		writeAPIKeyError(w, err, "Failed to create service account")
+		return
+	}
+
+	log.Printf("Service account %s created in tenant %s by user %s", account.Name, account.TenantID, claims.UserID)
+	audit.SetTarget(r.Context(), "service_account", account.ID)
+	audit.SetChange(r.Context(), nil, account)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(account)
+}
+
+func (h *APIKeyHandler) ListKeys(w http.ResponseWriter, r *http.Request) {
+	claims := requestClaims(r)
+	scope := service.TenantScope(claims)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var accountID string
+	if name := r.URL.Query().Get("service_account"); name != "" {
+		account, err := h.apiKeys.GetServiceAccount(ctx, name, service.CallerTenant(claims))
+		if errors.Is(err, repository.ErrServiceAccountNotFound) && scope == "" {
+			account, err = h.apiKeys.GetServiceAccount(ctx, name, "")
+		}
+		if err != nil {
+			writeAPIKeyError(w, err, "Failed to list API keys")
+			return
+		}
+		accountID = account.ID
+	}
+
+	keys, err := h.apiKeys.ListKeys(ctx, scope, accountID)
+	if err != nil {
+		writeAPIKeyError(w, err, "Failed to list API keys")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"api_keys": keys,
+		"count":    len(keys),
+	})
+}
+
+// CreateKey issues a key for a service account. The key is returned once and
+// only its hash is stored. Each scope must be a permission the caller holds,
+// so a key never grants more than its creator could do.
+func (h *APIKeyHandler) CreateKey(w http.ResponseWriter, r *http.Request) {
+	var req CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.ServiceAccount == "" || req.Name == "" {
+		http.Error(w, "Request body must name a service_account and a key name", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Scopes) == 0 {
+		http.Error(w, "An API key needs at least one scope", http.StatusBadRequest)
+		return
+	}
+
+	claims := requestClaims(r)
+	for _, scope := range req.Scopes {
+		permission := auth.Permission(scope)
+		if !auth.KnownPermission(permission) || permission == auth.PermWorkerAgent {
+			http.Error(w, "Unknown or unsupported scope: "+scope, http.StatusBadRequest)
+			return
+		}
+		if !claims.Can(permission) {
+			http.Error(w, "Forbidden: you cannot grant the "+scope+" scope", http.StatusForbidden)
+			return
+		}
+	}
+
+	var ttl time.Duration
+	if req.ExpiresIn != "" {
+		parsed, err := time.ParseDuration(req.ExpiresIn)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "Invalid expires_in", http.StatusBadRequest)
+			return
+		}
+		ttl = parsed
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	account, err := h.apiKeys.GetServiceAccount(ctx, req.ServiceAccount, service.CallerTenant(claims))
+	if errors.Is(err, repository.ErrServiceAccountNotFound) && service.TenantScope(claims) == "" {
+		account, err = h.apiKeys.GetServiceAccount(ctx, req.ServiceAccount, "")
+	}
+	if err != nil {
+		writeAPIKeyError(w, err, "Failed to create API key")
+		return
+	}
+
+	key := models.NewAPIKey(account.ID, req.Name, "", req.Scopes, ttl)
+	secret, fullKey, err := auth.GenerateAPIKey(key.ID)
+	if err != nil {
+		log.Printf("ERROR: Failed to generate API key: %v", err)
+		http.Error(w, "Failed to create API key", http.StatusInternalServerError)
+		return
+	}
+	key.SecretHash = auth.HashSecret(secret)
+	key.CreatedBy = claims.UserID
+	key.ServiceAccountName = account.Name
+	key.TenantID = account.TenantID
+
+	if err := h.apiKeys.CreateKey(ctx, key); err != nil {
+		writeAPIKeyError(w, err, "Failed to create API key")
+		return
+	}
+
+	log.Printf("API key %s created for service account %s by user %s", key.ID, account.Name, claims.UserID)
+	audit.SetTarget(r.Context(), "api_key", key.ID)
+	audit.SetChange(r.Context(), nil, key)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"key":     fullKey,
+		"api_key": key,
+	})
+}
+
+func (h *APIKeyHandler) RevokeKey(w http.ResponseWriter, r *http.Request) {
+	keyID := mux.Vars(r)["id"]
+	claims := requestClaims(r)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.apiKeys.RevokeKey(ctx, keyID, service.TenantScope(claims)); err != nil {
+		writeAPIKeyError(w, err, "Failed to revoke API key")
+		return
+	}
+
+	log.Printf("API key %s revoked by user %s", keyID, claims.UserID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"id":      keyID,
+		"revoked": true,
+	})
+}
+
+func writeAPIKeyError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, repository.ErrServiceAccountNotFound), errors.Is(err, repository.ErrAPIKeyNotFound), errors.Is(err, repository.ErrTenantNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, repository.ErrServiceAccountExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("ERROR: %s: %v", message, err)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
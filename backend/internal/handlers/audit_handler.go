@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/repository"
+	"github.com/rudraprasaaad/task-scheduler/internal/service"
+)
+
+const (
+	defaultAuditPageSize = 100
+	maxAuditPageSize     = 1000
+)
+
+// AuditHandler serves the audit log. Callers limited to one tenant only see
+// that tenant's events.
+type AuditHandler struct {
+	auditRepo *repository.AuditRepository
+}
+
+func NewAuditHandler(auditRepo *repository.AuditRepository) *AuditHandler {
+	return &AuditHandler{auditRepo: auditRepo}
+}
+
+// ListEvents returns audit events newest first. The next page continues from
+// next_before, passed back as before.
+func (h *AuditHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAuditQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if scope := service.TenantScope(requestClaims(r)); scope != "" {
+		filter.TenantID = scope
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	events, err := h.auditRepo.List(ctx, *filter)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, "Failed to list audit events", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"events": events,
+		"count":  len(events),
+	}
+	if len(events) == filter.Limit {
+		response["next_before"] = events[len(events)-1].ID
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// parseAuditQuery reads the audit log filters. An action ending in a dot,
+// such as "task.", matches every action under it. Times are RFC 3339.
+func parseAuditQuery(query url.Values) (*repository.AuditFilter, error) {
+	filter := &repository.AuditFilter{
+		TenantID:   query.Get("tenant_id"),
+		ActorType:  query.Get("actor_type"),
+		ActorID:    query.Get("actor_id"),
+		Action:     query.Get("action"),
+		TargetType: query.Get("target_type"),
+		TargetID:   query.Get("target_id"),
+		Outcome:    query.Get("outcome"),
+		Limit:      defaultAuditPageSize,
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("invalid limit: %q", value)
+		}
+		filter.Limit = min(limit, maxAuditPageSize)
+	}
+
+	if value := query.Get("before"); value != "" {
+		before, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || before <= 0 {
+			return nil, fmt.Errorf("invalid before: %q", value)
+		}
+		filter.BeforeID = before
+	}
+
+	for param, target := range map[string]**time.Time{
+		"since": &filter.Since,
+		"until": &filter.Until,
+	} {
+		if value := query.Get(param); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: expected an RFC 3339 time", param)
+			}
+			*target = &t
+		}
+	}
+
+	return filter, nil
+}
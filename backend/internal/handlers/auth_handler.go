@@ -1,27 +1,54 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"net"
 	"net/http"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"strconv"
 
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/rudraprasaaad/task-scheduler/internal/audit"
 	"github.com/rudraprasaaad/task-scheduler/internal/auth"
 	"github.com/rudraprasaaad/task-scheduler/internal/config"
+	"github.com/rudraprasaaad/task-scheduler/internal/middleware"
 	"github.com/rudraprasaaad/task-scheduler/internal/models"
 	"github.com/rudraprasaaad/task-scheduler/internal/repository"
+	"github.com/rudraprasaaad/task-scheduler/internal/session"
 )
 
 type AuthHandler struct {
 	userRepo *repository.UserRepository
+	sessions *session.Manager
+	throttle *session.LoginThrottle
+	audit    audit.Recorder
 	authCfg  config.AuthConfig
 }
 
-func NewAuthHandler(userRepo *repository.UserRepository, authCfg config.AuthConfig) *AuthHandler {
+// dummyUser has a password hash of the same cost as real users'. Checking the
+// password of an unknown email against it makes a failed login take as long
+// whether or not the account exists.
+var dummyUser = sync.OnceValue(func() *models.User {
+	user := &models.User{}
+	if err := user.SetPassword("not-a-real-password"); err != nil {
+		log.Printf("ERROR: Failed to hash dummy password: %v", err)
+	}
+	return user
+})
+
+func NewAuthHandler(userRepo *repository.UserRepository, sessions *session.Manager, throttle *session.LoginThrottle, recorder audit.Recorder, authCfg config.AuthConfig) *AuthHandler {
 	return &AuthHandler{
 		userRepo: userRepo,
+		sessions: sessions,
+		throttle: throttle,
+		audit:    recorder,
 		authCfg:  authCfg,
 	}
 }
@@ -37,6 +64,16 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	creds.Email = strings.ToLower(strings.TrimSpace(creds.Email))
+	if err := auth.ValidateEmail(creds.Email); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := auth.ValidatePassword(creds.Password, creds.Email); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	user := &models.User{Email: creds.Email}
 	if err := user.SetPassword(creds.Password); err != nil {
 		http.Error(w, "Failed to hash password", http.StatusInternalServerError)
@@ -48,6 +85,17 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	userID := strconv.FormatInt(user.ID, 10)
+	h.record(r, &models.AuditEvent{
+		Action:     audit.ActionRegister,
+		ActorType:  models.ActorUser,
+		ActorID:    userID,
+		TenantID:   user.TenantID,
+		TargetType: "user",
+		TargetID:   userID,
+		Details:    map[string]interface{}{"email": user.Email},
+	})
+
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(map[string]string{"message": "User created successfully"})
 }
@@ -63,50 +111,304 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	creds.Email = strings.ToLower(strings.TrimSpace(creds.Email))
+	client := sessionClient(r)
+
+	if err := h.throttle.Check(r.Context(), creds.Email, client.IPAddress); err != nil {
+		if errors.Is(err, session.ErrLockedOut) {
+			h.recordLoginFailure(r, creds.Email, "locked_out")
+		}
+		h.writeThrottleError(w, r, creds.Email, err)
+		return
+	}
+
 	user, err := h.userRepo.GetByEmail(r.Context(), creds.Email)
 	if err != nil {
-		http.Error(w, "Invalid email or password", http.StatusUnauthorized)
+		dummyUser().CheckPassword(creds.Password)
+		h.loginFailed(w, r, creds.Email, "unknown_email")
 		return
 	}
 
 	if !user.CheckPassword(creds.Password) {
-		http.Error(w, "Invalid credentials", http.StatusUnauthorized)
+		h.loginFailed(w, r, creds.Email, "wrong_password")
 		return
 	}
 
-	role := "user"
+	if err := h.throttle.Succeed(r.Context(), creds.Email); err != nil {
+		log.Printf("ERROR: Failed to clear failed logins for %s: %v", creds.Email, err)
+	}
 
-	expirationTime := time.Now().Add(h.authCfg.TokenExpiration)
+	roles, err := h.userRepo.GetRoles(r.Context(), user.ID)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, "Failed to load user roles", http.StatusInternalServerError)
+		return
+	}
 
-	claims := &auth.Claims{
-		UserID: strconv.FormatInt(user.ID, 10),
-		Role:   role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-		},
+	if h.authCfg.BootstrapAdminEmail != "" && user.Email == h.authCfg.BootstrapAdminEmail && !slices.Contains(roles, auth.RoleAdmin) {
+		granted, err := h.userRepo.BootstrapAdmin(r.Context(), user.Email)
+		if err != nil {
+			log.Printf("ERROR: Failed to grant admin role to bootstrap admin %s: %v", user.Email, err)
+		} else if granted {
+			log.Printf("Granted admin role to bootstrap admin %s", user.Email)
+			roles = append(roles, auth.RoleAdmin)
+		}
 	}
 
-	tokenString, err := auth.GenrerateToken(claims, h.authCfg.JWTSecret)
+	tokens, err := h.sessions.Start(r.Context(), user, roles, client)
 	if err != nil {
+		log.Printf("ERROR: Failed to start session for user %d: %v", user.ID, err)
 		http.Error(w, "Failed to create token", http.StatusInternalServerError)
 		return
 	}
 
+	h.record(r, &models.AuditEvent{
+		Action:     audit.ActionLogin,
+		ActorType:  models.ActorUser,
+		ActorID:    tokens.Claims.UserID,
+		TenantID:   user.TenantID,
+		TargetType: "session",
+		TargetID:   tokens.Claims.SessionID,
+		Details:    map[string]interface{}{"email": user.Email},
+	})
+
+	setSessionCookies(w, tokens)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"message": "Login Successful", "roles": roles, "tenant_id": user.TenantID, "expires_at": tokens.AccessExpiresAt})
+}
+
+// Refresh exchanges the refresh token, sent as the refresh_token cookie or in
+// the JSON body, for a new access token and a new refresh token.
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	refreshToken := requestRefreshToken(r)
+	if refreshToken == "" {
+		http.Error(w, "Unauthorized: No refresh token provided", http.StatusUnauthorized)
+		return
+	}
+
+	tokens, err := h.sessions.Refresh(r.Context(), refreshToken)
+	if err != nil {
+		if errors.Is(err, session.ErrInvalidRefreshToken) || errors.Is(err, session.ErrRefreshTokenReused) {
+			clearSessionCookies(w)
+			http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+			return
+		}
+		log.Printf("ERROR: Failed to refresh session: %v", err)
+		http.Error(w, "Failed to refresh token", http.StatusInternalServerError)
+		return
+	}
+
+	setSessionCookies(w, tokens)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"message": "Token refreshed", "roles": tokens.Claims.Roles, "tenant_id": tokens.Claims.TenantID, "expires_at": tokens.AccessExpiresAt})
+}
+
+// Logout ends the caller's session and revokes their access token. It works
+// with an expired access token too, as long as the refresh token is sent.
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	var claims *auth.Claims
+	if token := middleware.RequestToken(r); token != "" && !auth.IsAPIKey(token) {
+		claims, _ = auth.ValidateToken(token, h.authCfg.JWTSecret)
+	}
+
+	if err := h.sessions.Logout(r.Context(), claims, requestRefreshToken(r)); err != nil {
+		log.Printf("ERROR: Failed to log out: %v", err)
+		http.Error(w, "Failed to log out", http.StatusInternalServerError)
+		return
+	}
+
+	if claims != nil {
+		h.record(r, &models.AuditEvent{
+			Action:     audit.ActionLogout,
+			ActorType:  models.ActorUser,
+			ActorID:    claims.UserID,
+			TenantID:   claims.TenantID,
+			TargetType: "session",
+			TargetID:   claims.SessionID,
+		})
+	}
+
+	clearSessionCookies(w)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Logout Successful"})
+}
+
+// LogoutAll ends every session of the caller, on every device.
+func (h *AuthHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	claims := requestClaims(r)
+	userID, err := strconv.ParseInt(claims.UserID, 10, 64)
+	if err != nil {
+		http.Error(w, "Only user logins have sessions to log out", http.StatusBadRequest)
+		return
+	}
+
+	clearSessionCookies(w)
+	h.logoutAll(w, r, userID)
+}
+
+// RevokeUserSessions lets an admin log a user out everywhere.
+func (h *AuthHandler) RevokeUserSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := parseUserID(w, r)
+	if !ok {
+		return
+	}
+
+	h.logoutAll(w, r, userID)
+}
+
+func (h *AuthHandler) logoutAll(w http.ResponseWriter, r *http.Request, userID int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	revoked, err := h.sessions.LogoutAll(ctx, userID)
+	if err != nil {
+		log.Printf("ERROR: Failed to log out user %d everywhere: %v", userID, err)
+		http.Error(w, "Failed to log out sessions", http.StatusInternalServerError)
+		return
+	}
+
+	// Admin calls are recorded by the audit middleware; logging out of one's
+	// own sessions goes around it.
+	if audit.FromContext(r.Context()) != nil {
+		audit.SetTarget(r.Context(), "user", strconv.FormatInt(userID, 10))
+		audit.SetDetail(r.Context(), "revoked", revoked)
+	} else {
+		claims := requestClaims(r)
+		actorType, actorID := audit.ActorFromClaims(claims)
+		h.record(r, &models.AuditEvent{
+			Action:     audit.ActionLogoutAll,
+			ActorType:  actorType,
+			ActorID:    actorID,
+			TenantID:   claims.TenantID,
+			TargetType: "user",
+			TargetID:   strconv.FormatInt(userID, 10),
+			Details:    map[string]interface{}{"revoked": revoked},
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"user_id": userID,
+		"revoked": revoked,
+	})
+}
+
+// loginFailed counts the failure towards the lockouts and answers with the
+// same message whether or not the email exists.
+func (h *AuthHandler) loginFailed(w http.ResponseWriter, r *http.Request, email, reason string) {
+	h.recordLoginFailure(r, email, reason)
+
+	if err := h.throttle.Fail(r.Context(), email, sessionClient(r).IPAddress); err != nil {
+		h.writeThrottleError(w, r, email, err)
+		return
+	}
+
+	http.Error(w, "Invalid email or password", http.StatusUnauthorized)
+}
+
+func (h *AuthHandler) writeThrottleError(w http.ResponseWriter, r *http.Request, email string, err error) {
+	var locked *session.LockedOutError
+	if !errors.As(err, &locked) {
+		log.Printf("ERROR: Login throttling failed: %v", err)
+		http.Error(w, "Failed to check login attempts", http.StatusInternalServerError)
+		return
+	}
+
+	if locked.Started {
+		h.record(r, &models.AuditEvent{
+			Action:  audit.ActionLockout,
+			Outcome: models.AuditDenied,
+			Details: map[string]interface{}{"email": email, "scope": locked.Scope, "duration": locked.RetryAfter.String()},
+		})
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+	http.Error(w, locked.Error(), http.StatusTooManyRequests)
+}
+
+func (h *AuthHandler) recordLoginFailure(r *http.Request, email, reason string) {
+	h.record(r, &models.AuditEvent{
+		Action:  audit.ActionLoginFailed,
+		Outcome: models.AuditFailure,
+		Details: map[string]interface{}{"email": email, "reason": reason},
+	})
+}
+
+func (h *AuthHandler) record(r *http.Request, event *models.AuditEvent) {
+	client := sessionClient(r)
+	event.Method = r.Method
+	event.Path = r.URL.Path
+	event.IPAddress = client.IPAddress
+	event.UserAgent = client.UserAgent
+	h.audit.Record(r.Context(), event)
+}
+
+func sessionClient(r *http.Request) session.Client {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	return session.Client{UserAgent: r.UserAgent(), IPAddress: ip}
+}
+
+func requestRefreshToken(r *http.Request) string {
+	if cookie, err := r.Cookie(refreshCookieName); err == nil && cookie.Value != "" {
+		return cookie.Value
+	}
+
+	var body struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if r.ContentLength > 0 && json.NewDecoder(r.Body).Decode(&body) == nil {
+		return body.RefreshToken
+	}
+
+	return ""
+}
+
+// The refresh token cookie is only sent to the auth endpoints, never with
+// ordinary API requests.
+const (
+	refreshCookieName = "refresh_token"
+	refreshCookiePath = "/api/v1/auth"
+)
+
+func setSessionCookies(w http.ResponseWriter, tokens *session.Tokens) {
 	http.SetCookie(w, &http.Cookie{
 		Name:     "auth_token",
-		Value:    tokenString,
-		Expires:  expirationTime,
+		Value:    tokens.AccessToken,
+		Expires:  tokens.AccessExpiresAt,
 		HttpOnly: true,
 		Path:     "/",
 		SameSite: http.SameSiteLaxMode,
 	})
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"message": "Login Successful"})
+	http.SetCookie(w, &http.Cookie{
+		Name:     refreshCookieName,
+		Value:    tokens.RefreshToken,
+		Expires:  tokens.RefreshExpiresAt,
+		HttpOnly: true,
+		Path:     refreshCookiePath,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	// Not HttpOnly: the page reads it to send the X-CSRF-Token header.
+	http.SetCookie(w, &http.Cookie{
+		Name:     middleware.CSRFCookieName,
+		Value:    tokens.CSRFToken,
+		Expires:  tokens.RefreshExpiresAt,
+		Path:     "/",
+		SameSite: http.SameSiteLaxMode,
+	})
 }
 
-func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+func clearSessionCookies(w http.ResponseWriter) {
 	http.SetCookie(w, &http.Cookie{
 		Name:     "auth_token",
 		Value:    "",
@@ -116,7 +418,19 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 		SameSite: http.SameSiteLaxMode,
 	})
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"message": "Logout Successful"})
+	http.SetCookie(w, &http.Cookie{
+		Name:     refreshCookieName,
+		Value:    "",
+		Expires:  time.Now().Add(-time.Hour),
+		HttpOnly: true,
+		Path:     refreshCookiePath,
+		SameSite: http.SameSiteStrictMode,
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     middleware.CSRFCookieName,
+		Value:    "",
+		Expires:  time.Now().Add(-time.Hour),
+		Path:     "/",
+		SameSite: http.SameSiteLaxMode,
+	})
 }
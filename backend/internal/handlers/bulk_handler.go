@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/rudraprasaaad/task-scheduler/internal/auth"
+	"github.com/rudraprasaaad/task-scheduler/internal/middleware"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+	"github.com/rudraprasaaad/task-scheduler/internal/service"
+)
+
+const maxBulkUploadBytes = 64 << 20
+
+type BulkHandler struct {
+	bulk *service.BulkService
+}
+
+func NewBulkHandler(bulk *service.BulkService) *BulkHandler {
+	return &BulkHandler{bulk: bulk}
+}
+
+// BulkTaskRequest selects the tasks for a bulk operation, either by ID or by
+// a filter written as a task list query string, e.g. "status=failed&type=email".
+type BulkTaskRequest struct {
+	TaskIDs     []string             `json:"task_ids,omitempty"`
+	Filter      string               `json:"filter,omitempty"`
+	Priority    *models.TaskPriority `json:"priority,omitempty"`
+	ScheduledAt *time.Time           `json:"scheduled_at,omitempty"`
+}
+
+// CreateTasks accepts a JSON array of tasks or one task per line (NDJSON) and
+// creates them in a background job.
+func (h *BulkHandler) CreateTasks(w http.ResponseWriter, r *http.Request) {
+	tasks, err := decodeTaskUpload(http.MaxBytesReader(w, r.Body, maxBulkUploadBytes))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	inputs := make([]service.CreateTaskInput, len(tasks))
+	for i := range tasks {
+		inputs[i] = tasks[i].input()
+	}
+
+	h.submit(w, r, service.BulkRequest{Operation: models.BulkCreate, Tasks: inputs})
+}
+
+func decodeTaskUpload(body io.Reader) ([]CreateTaskRequest, error) {
+	reader := bufio.NewReader(body)
+
+	first, err := peekNonSpace(reader)
+	if err != nil {
+		return nil, fmt.Errorf("empty upload")
+	}
+
+	decoder := json.NewDecoder(reader)
+
+	var tasks []CreateTaskRequest
+	if first == '[' {
+		if err := decoder.Decode(&tasks); err != nil {
+			return nil, fmt.Errorf("invalid JSON array: %v", err)
+		}
+		return tasks, nil
+	}
+
+	for {
+		var task CreateTaskRequest
+		err := decoder.Decode(&task)
+		if errors.Is(err, io.EOF) {
+			return tasks, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid task at item %d: %v", len(tasks), err)
+		}
+		tasks = append(tasks, task)
+	}
+}
+
+func peekNonSpace(reader *bufio.Reader) (byte, error) {
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return b, reader.UnreadByte()
+	}
+}
+
+// SubmitOperation starts a bulk cancel, retry, reprioritize, reschedule or
+// delete named by the operation path variable.
+func (h *BulkHandler) SubmitOperation(w http.ResponseWriter, r *http.Request) {
+	operation := models.BulkOperation(mux.Vars(r)["operation"])
+	if operation == models.BulkCreate {
+		http.Error(w, "Use /tasks/bulk/create to upload tasks", http.StatusBadRequest)
+		return
+	}
+
+	// The route only needs write access; deleting in bulk needs the same
+	// permission as deleting one task.
+	if operation == models.BulkDelete {
+		if claims, ok := r.Context().Value(middleware.UserContextkey).(*auth.Claims); !ok || !claims.Can(auth.PermTaskDelete) {
+			http.Error(w, "Forbidden: requires the "+string(auth.PermTaskDelete)+" permission", http.StatusForbidden)
+			return
+		}
+	}
+
+	var req BulkTaskRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBulkUploadBytes)).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	bulkReq := service.BulkRequest{
+		Operation:   operation,
+		TaskIDs:     req.TaskIDs,
+		Priority:    req.Priority,
+		ScheduledAt: req.ScheduledAt,
+	}
+
+	if req.Filter != "" {
+		query, err := url.ParseQuery(req.Filter)
+		if err != nil {
+			http.Error(w, "Invalid filter: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		filter, err := parseListTasksQuery(query)
+		if err != nil {
+			http.Error(w, "Invalid filter: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		bulkReq.Filter = filter
+		bulkReq.FilterQuery = req.Filter
+	}
+
+	h.submit(w, r, bulkReq)
+}
+
+func (h *BulkHandler) submit(w http.ResponseWriter, r *http.Request, req service.BulkRequest) {
+	if claims, ok := r.Context().Value(middleware.UserContextkey).(*auth.Claims); ok {
+		req.CreatedBy = claims.UserID
+		for i := range req.Tasks {
+			req.Tasks[i].CreatedBy = claims.UserID
+			req.Tasks[i].TenantID = service.CallerTenant(claims)
+		}
+	}
+
+	ctx, cancel := detachedContext(r, 5*time.Second)
+	defer cancel()
+
+	job, err := h.bulk.Submit(ctx, req)
+	if err != nil {
+		writeServiceError(w, err, "Failed to submit bulk job")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/api/v1/bulk-jobs/"+job.ID)
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job)
+}
+
+func (h *BulkHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	ctx, cancel := scopedContext(r, 5*time.Second)
+	defer cancel()
+
+	jobs, err := h.bulk.ListJobs(ctx, limit)
+	if err != nil {
+		writeServiceError(w, err, "Failed to list bulk jobs")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"jobs":  jobs,
+		"count": len(jobs),
+	})
+}
+
+func (h *BulkHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := scopedContext(r, 5*time.Second)
+	defer cancel()
+
+	job, err := h.bulk.GetJob(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		writeServiceError(w, err, "Failed to get bulk job")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+// ListItems returns the job's per-item report. failed=true limits it to the
+// items that did not succeed.
+func (h *BulkHandler) ListItems(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	failedOnly, _ := strconv.ParseBool(query.Get("failed"))
+
+	ctx, cancel := scopedContext(r, 10*time.Second)
+	defer cancel()
+
+	items, err := h.bulk.Items(ctx, mux.Vars(r)["id"], failedOnly, limit, offset)
+	if err != nil {
+		writeServiceError(w, err, "Failed to get bulk job items")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"items":  items,
+		"count":  len(items),
+		"offset": max(offset, 0),
+	})
+}
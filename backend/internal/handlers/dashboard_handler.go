@@ -0,0 +1,439 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/auth"
+	"github.com/rudraprasaaad/task-scheduler/internal/cache"
+	"github.com/rudraprasaaad/task-scheduler/internal/events"
+	"github.com/rudraprasaaad/task-scheduler/internal/middleware"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+	"github.com/rudraprasaaad/task-scheduler/internal/queue"
+	"github.com/rudraprasaaad/task-scheduler/internal/redis"
+	"github.com/rudraprasaaad/task-scheduler/internal/service"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	DashboardTopicQueue      = "queue"
+	DashboardTopicWorkers    = "workers"
+	DashboardTopicThroughput = "throughput"
+	DashboardTopicTasks      = "tasks"
+	DashboardTopicError      = "error"
+
+	dashboardSnapshotInterval = time.Second
+	dashboardWriteTimeout     = 10 * time.Second
+	dashboardClientBuffer     = 64
+	throughputWindow          = time.Minute
+)
+
+var snapshotTopics = []string{DashboardTopicQueue, DashboardTopicWorkers, DashboardTopicThroughput}
+
+type DashboardMessage struct {
+	Topic     string      `json:"topic"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// DashboardRequest is sent by clients to pick the topics they receive. The
+// filter fields apply to the tasks topic.
+type DashboardRequest struct {
+	Action    string   `json:"action"`
+	Topics    []string `json:"topics"`
+	TaskIDs   []string `json:"task_ids,omitempty"`
+	Types     []string `json:"types,omitempty"`
+	Statuses  []string `json:"statuses,omitempty"`
+	Workflows []string `json:"workflows,omitempty"`
+}
+
+// DashboardHandler builds one set of snapshots per interval and fans them out
+// to every connected dashboard, so the Redis cost does not grow with viewers.
+type DashboardHandler struct {
+	queue       *queue.RedisQueue
+	cache       *cache.RedisCache
+	redisClient *redis.Client
+	broker      *events.Broker
+
+	mutex   sync.Mutex
+	clients map[*dashboardClient]struct{}
+}
+
+func NewDashboardHandler(redisClient *redis.Client, cache *cache.RedisCache, broker *events.Broker) *DashboardHandler {
+	return &DashboardHandler{
+		queue:       queue.NewRedisQueue(redisClient, nil),
+		cache:       cache,
+		redisClient: redisClient,
+		broker:      broker,
+		clients:     make(map[*dashboardClient]struct{}),
+	}
+}
+
+type dashboardClient struct {
+	out  chan *DashboardMessage
+	done chan struct{}
+
+	// tenantID limits the client to one tenant's task events; the queue,
+	// workers and throughput snapshots cover every tenant, so such clients
+	// cannot subscribe to them.
+	tenantID string
+
+	mutex      sync.Mutex
+	topics     map[string]bool
+	stopEvents context.CancelFunc
+}
+
+func (c *dashboardClient) subscribed(topic string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.topics[topic]
+}
+
+// send drops the message if the client is not keeping up; snapshots are
+// superseded on the next tick anyway.
+func (c *dashboardClient) send(message *DashboardMessage) {
+	select {
+	case c.out <- message:
+	case <-c.done:
+	default:
+	}
+}
+
+func (c *dashboardClient) sendError(format string, args ...interface{}) {
+	c.send(&DashboardMessage{
+		Topic:     DashboardTopicError,
+		Timestamp: time.Now(),
+		Data:      map[string]string{"message": fmt.Sprintf(format, args...)},
+	})
+}
+
+func (c *dashboardClient) stopTaskEvents() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.stopEvents != nil {
+		c.stopEvents()
+		c.stopEvents = nil
+	}
+}
+
+func (h *DashboardHandler) Run(ctx context.Context) {
+	ticker := time.NewTicker(dashboardSnapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.broadcastSnapshots(ctx)
+		}
+	}
+}
+
+func (h *DashboardHandler) broadcastSnapshots(ctx context.Context) {
+	h.mutex.Lock()
+	clients := make([]*dashboardClient, 0, len(h.clients))
+	for client := range h.clients {
+		clients = append(clients, client)
+	}
+	h.mutex.Unlock()
+
+	for _, topic := range snapshotTopics {
+		var subscribers []*dashboardClient
+		for _, client := range clients {
+			if client.subscribed(topic) {
+				subscribers = append(subscribers, client)
+			}
+		}
+
+		if len(subscribers) == 0 {
+			continue
+		}
+
+		data, err := h.snapshot(ctx, topic)
+		if err != nil {
+			log.Printf("Failed to build %s dashboard snapshot: %v", topic, err)
+			continue
+		}
+
+		message := &DashboardMessage{Topic: topic, Timestamp: time.Now(), Data: data}
+		for _, client := range subscribers {
+			client.send(message)
+		}
+	}
+}
+
+func (h *DashboardHandler) snapshot(ctx context.Context, topic string) (interface{}, error) {
+	switch topic {
+	case DashboardTopicQueue:
+		return h.queueSnapshot()
+	case DashboardTopicWorkers:
+		return h.workersSnapshot(ctx)
+	case DashboardTopicThroughput:
+		return h.throughputSnapshot(ctx)
+	default:
+		return nil, fmt.Errorf("unknown topic %q", topic)
+	}
+}
+
+func (h *DashboardHandler) queueSnapshot() (interface{}, error) {
+	sizes, err := h.queue.SizeByPriority()
+	if err != nil {
+		return nil, err
+	}
+
+	total := 0
+	byPriority := make(map[string]int, len(sizes))
+	for priority, count := range sizes {
+		byPriority[strconv.Itoa(priority)] = count
+		total += count
+	}
+
+	oldestReadyAge, err := h.queue.OldestReadyAge()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"queue_size":               total,
+		"by_priority":              byPriority,
+		"oldest_ready_age_seconds": oldestReadyAge.Seconds(),
+	}, nil
+}
+
+func (h *DashboardHandler) workersSnapshot(ctx context.Context) (interface{}, error) {
+	workers, err := h.cache.ListWorkerStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var inFlight, totalSlots int
+	byStatus := make(map[string]int)
+	for _, stats := range workers {
+		if status, ok := stats["status"].(string); ok {
+			byStatus[status]++
+		}
+
+		// JSON numbers decode as float64.
+		if current, ok := stats["current_tasks"].(float64); ok {
+			inFlight += int(current)
+		}
+		if slots, ok := stats["max_concurrent_tasks"].(float64); ok {
+			totalSlots += int(slots)
+		}
+	}
+
+	return map[string]interface{}{
+		"workers":     workers,
+		"by_status":   byStatus,
+		"in_flight":   inFlight,
+		"total_slots": totalSlots,
+	}, nil
+}
+
+func (h *DashboardHandler) throughputSnapshot(ctx context.Context) (interface{}, error) {
+	counts, err := events.CountSince(ctx, h.redisClient, time.Now().Add(-throughputWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	completed := counts[models.TaskStatusCompleted]
+	failed := counts[models.TaskStatusFailed]
+
+	return map[string]interface{}{
+		"window_seconds": throughputWindow.Seconds(),
+		"created":        counts[models.TaskStatusPending],
+		"completed":      completed,
+		"failed":         failed,
+		"cancelled":      counts[models.TaskStatusCancelled],
+		"per_second":     float64(completed+failed) / throughputWindow.Seconds(),
+	}, nil
+}
+
+// ServeWebSocket upgrades the request to the dashboard feed. Clients start
+// with no topics and send subscribe messages to pick them.
+func (h *DashboardHandler) ServeWebSocket(w http.ResponseWriter, r *http.Request) {
+	var tenantID string
+	if claims, ok := r.Context().Value(middleware.UserContextkey).(*auth.Claims); ok {
+		tenantID = service.TenantScope(claims)
+	}
+
+	server := websocket.Server{
+		Handshake: checkSameOrigin,
+		Handler: func(ws *websocket.Conn) {
+			h.serve(ws, tenantID)
+		},
+	}
+	server.ServeHTTP(w, r)
+}
+
+// checkSameOrigin rejects cross-site browser connections. The API
+// authenticates with a cookie, which browsers attach to WebSocket handshakes
+// from any site.
+func checkSameOrigin(config *websocket.Config, r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+
+	parsed, err := url.Parse(origin)
+	if err != nil || parsed.Host != r.Host {
+		return fmt.Errorf("origin %q not allowed", origin)
+	}
+
+	return nil
+}
+
+func (h *DashboardHandler) serve(ws *websocket.Conn, tenantID string) {
+	// The hijacked connection keeps the HTTP server's deadlines.
+	ws.SetDeadline(time.Time{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := &dashboardClient{
+		out:      make(chan *DashboardMessage, dashboardClientBuffer),
+		done:     make(chan struct{}),
+		tenantID: tenantID,
+		topics:   make(map[string]bool),
+	}
+
+	h.mutex.Lock()
+	h.clients[client] = struct{}{}
+	h.mutex.Unlock()
+
+	defer func() {
+		h.mutex.Lock()
+		delete(h.clients, client)
+		h.mutex.Unlock()
+
+		client.stopTaskEvents()
+		close(client.done)
+	}()
+
+	go func() {
+		defer cancel()
+		h.readRequests(ctx, ws, client)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message := <-client.out:
+			ws.SetWriteDeadline(time.Now().Add(dashboardWriteTimeout))
+			if err := websocket.JSON.Send(ws, message); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (h *DashboardHandler) readRequests(ctx context.Context, ws *websocket.Conn, client *dashboardClient) {
+	for {
+		var raw string
+		if err := websocket.Message.Receive(ws, &raw); err != nil {
+			return
+		}
+
+		var req DashboardRequest
+		if err := json.Unmarshal([]byte(raw), &req); err != nil {
+			client.sendError("invalid message: %v", err)
+			continue
+		}
+
+		if err := h.applyRequest(ctx, client, &req); err != nil {
+			client.sendError("%v", err)
+		}
+	}
+}
+
+func (h *DashboardHandler) applyRequest(ctx context.Context, client *dashboardClient, req *DashboardRequest) error {
+	for _, topic := range req.Topics {
+		if topic != DashboardTopicTasks && !slices.Contains(snapshotTopics, topic) {
+			return fmt.Errorf("unknown topic %q", topic)
+		}
+	}
+
+	if req.Action == "subscribe" && client.tenantID != "" {
+		for _, topic := range req.Topics {
+			if topic != DashboardTopicTasks {
+				return fmt.Errorf("topic %q covers every tenant and is not available to this account", topic)
+			}
+		}
+	}
+
+	switch req.Action {
+	case "subscribe":
+		client.mutex.Lock()
+		for _, topic := range req.Topics {
+			client.topics[topic] = true
+		}
+		client.mutex.Unlock()
+
+		if slices.Contains(req.Topics, DashboardTopicTasks) {
+			return h.watchTasks(ctx, client, events.Filter{
+				TaskIDs:   req.TaskIDs,
+				Types:     req.Types,
+				Statuses:  req.Statuses,
+				Workflows: req.Workflows,
+				TenantID:  client.tenantID,
+			})
+		}
+	case "unsubscribe":
+		client.mutex.Lock()
+		for _, topic := range req.Topics {
+			delete(client.topics, topic)
+		}
+		client.mutex.Unlock()
+
+		if slices.Contains(req.Topics, DashboardTopicTasks) {
+			client.stopTaskEvents()
+		}
+	default:
+		return fmt.Errorf("unknown action %q", req.Action)
+	}
+
+	return nil
+}
+
+// watchTasks replaces the client's task event subscription, so subscribing to
+// tasks again changes the filter.
+func (h *DashboardHandler) watchTasks(ctx context.Context, client *dashboardClient, filter events.Filter) error {
+	client.stopTaskEvents()
+
+	eventsCtx, stop := context.WithCancel(ctx)
+	sub, err := h.broker.Subscribe(eventsCtx, filter, "")
+	if err != nil {
+		stop()
+		return fmt.Errorf("failed to subscribe to task events: %w", err)
+	}
+
+	client.mutex.Lock()
+	client.stopEvents = stop
+	client.mutex.Unlock()
+
+	go func() {
+		defer sub.Close()
+
+		for event := range sub.Events {
+			client.send(&DashboardMessage{Topic: DashboardTopicTasks, Timestamp: event.Timestamp, Data: event})
+		}
+
+		if eventsCtx.Err() == nil {
+			client.sendError("task events interrupted, subscribe to tasks again")
+		}
+	}()
+
+	return nil
+}
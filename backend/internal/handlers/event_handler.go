@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/auth"
+	"github.com/rudraprasaaad/task-scheduler/internal/events"
+	"github.com/rudraprasaaad/task-scheduler/internal/middleware"
+	"github.com/rudraprasaaad/task-scheduler/internal/service"
+)
+
+const sseKeepAliveInterval = 15 * time.Second
+
+type EventHandler struct {
+	broker *events.Broker
+}
+
+func NewEventHandler(broker *events.Broker) *EventHandler {
+	return &EventHandler{broker: broker}
+}
+
+// StreamEvents serves task events as Server-Sent Events. Clients resume with
+// the standard Last-Event-ID header or the last_event_id query parameter.
+func (h *EventHandler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := events.Filter{
+		TaskIDs:   splitQueryList(query["task_id"]),
+		Types:     splitQueryList(query["type"]),
+		Statuses:  splitQueryList(query["status"]),
+		Workflows: splitQueryList(query["workflow"]),
+	}
+	if claims, ok := r.Context().Value(middleware.UserContextkey).(*auth.Claims); ok {
+		filter.TenantID = service.TenantScope(claims)
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = query.Get("last_event_id")
+	}
+
+	sub, err := h.broker.Subscribe(r.Context(), filter, lastEventID)
+	if errors.Is(err, events.ErrInvalidEventID) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to subscribe to task events: %v", err)
+		http.Error(w, "Failed to subscribe to events", http.StatusInternalServerError)
+		return
+	}
+	defer sub.Close()
+
+	// The server's write timeout is meant for ordinary requests, not a stream
+	// that stays open for as long as the client listens.
+	controller := http.NewResponseController(w)
+	if err := controller.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Printf("Failed to clear write deadline for event stream: %v", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if err := controller.Flush(); err != nil {
+		log.Printf("Event stream not supported by response writer: %v", err)
+		return
+	}
+
+	keepAlive := time.NewTicker(sseKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-sub.Events:
+			if !ok {
+				return
+			}
+
+			eventJSON, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("Failed to marshal task event %s: %v", event.ID, err)
+				continue
+			}
+
+			if _, err := fmt.Fprintf(w, "id: %s\nevent: task\ndata: %s\n\n", event.ID, eventJSON); err != nil {
+				return
+			}
+		}
+
+		if err := controller.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// splitQueryList accepts both repeated parameters and comma separated values.
+func splitQueryList(values []string) []string {
+	var list []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+
+	return list
+}
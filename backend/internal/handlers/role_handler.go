@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/rudraprasaaad/task-scheduler/internal/audit"
+	"github.com/rudraprasaaad/task-scheduler/internal/auth"
+	"github.com/rudraprasaaad/task-scheduler/internal/middleware"
+	"github.com/rudraprasaaad/task-scheduler/internal/repository"
+)
+
+// RoleHandler serves the admin endpoints that grant and revoke roles. Changes
+// apply when the user next refreshes their token, since roles travel in it.
+type RoleHandler struct {
+	userRepo *repository.UserRepository
+}
+
+func NewRoleHandler(userRepo *repository.UserRepository) *RoleHandler {
+	return &RoleHandler{userRepo: userRepo}
+}
+
+func (h *RoleHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	roles, err := h.userRepo.ListRoles(ctx)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, "Failed to list roles", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"roles": roles})
+}
+
+func (h *RoleHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	users, err := h.userRepo.List(ctx, limit, max(offset, 0))
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, "Failed to list users", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"users": users,
+		"count": len(users),
+	})
+}
+
+func (h *RoleHandler) GetUserRoles(w http.ResponseWriter, r *http.Request) {
+	userID, ok := parseUserID(w, r)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := h.userRepo.GetByID(ctx, userID); err != nil {
+		writeRoleError(w, err, "Failed to get user")
+		return
+	}
+
+	roles, err := h.userRepo.GetRoles(ctx, userID)
+	if err != nil {
+		writeRoleError(w, err, "Failed to get user roles")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"user_id": userID,
+		"roles":   roles,
+	})
+}
+
+func (h *RoleHandler) GrantRole(w http.ResponseWriter, r *http.Request) {
+	userID, ok := parseUserID(w, r)
+	if !ok {
+		return
+	}
+	role := mux.Vars(r)["role"]
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	before, err := h.userRepo.GetRoles(ctx, userID)
+	if err != nil {
+		writeRoleError(w, err, "Failed to get user roles")
+		return
+	}
+
+	if err := h.userRepo.GrantRole(ctx, userID, role); err != nil {
+		writeRoleError(w, err, "Failed to grant role")
+		return
+	}
+
+	log.Printf("Role %s granted to user %d by user %s", role, userID, actingUserID(r))
+	h.writeUserRoles(ctx, w, r, userID, before)
+}
+
+func (h *RoleHandler) RevokeRole(w http.ResponseWriter, r *http.Request) {
+	userID, ok := parseUserID(w, r)
+	if !ok {
+		return
+	}
+	role := mux.Vars(r)["role"]
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := h.userRepo.GetByID(ctx, userID); err != nil {
+		writeRoleError(w, err, "Failed to get user")
+		return
+	}
+
+	before, err := h.userRepo.GetRoles(ctx, userID)
+	if err != nil {
+		writeRoleError(w, err, "Failed to get user roles")
+		return
+	}
+
+	revoked, err := h.userRepo.RevokeRole(ctx, userID, role)
+	if err != nil {
+		writeRoleError(w, err, "Failed to revoke role")
+		return
+	}
+
+	if revoked {
+		log.Printf("Role %s revoked from user %d by user %s", role, userID, actingUserID(r))
+	}
+	h.writeUserRoles(ctx, w, r, userID, before)
+}
+
+// writeUserRoles answers a role change with the user's roles, which it also
+// notes in the audit log next to the roles they had before.
+func (h *RoleHandler) writeUserRoles(ctx context.Context, w http.ResponseWriter, r *http.Request, userID int64, before []string) {
+	roles, err := h.userRepo.GetRoles(ctx, userID)
+	if err != nil {
+		writeRoleError(w, err, "Failed to get user roles")
+		return
+	}
+	audit.SetChange(r.Context(), map[string]interface{}{"roles": before}, map[string]interface{}{"roles": roles})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"user_id": userID,
+		"roles":   roles,
+		"message": "Role changes apply from the user's next token refresh",
+	})
+}
+
+func parseUserID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	userID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return userID, true
+}
+
+func actingUserID(r *http.Request) string {
+	if claims, ok := r.Context().Value(middleware.UserContextkey).(*auth.Claims); ok {
+		return claims.UserID
+	}
+	return "unknown"
+}
+
+func writeRoleError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, repository.ErrUserNotFound), errors.Is(err, repository.ErrRoleNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, repository.ErrLastAdmin):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("ERROR: %s: %v", message, err)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
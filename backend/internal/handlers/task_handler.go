@@ -3,41 +3,54 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
+	"math"
 	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/rudraprasaaad/task-scheduler/internal/audit"
+	"github.com/rudraprasaaad/task-scheduler/internal/auth"
 	"github.com/rudraprasaaad/task-scheduler/internal/cache"
+	"github.com/rudraprasaaad/task-scheduler/internal/config"
+	"github.com/rudraprasaaad/task-scheduler/internal/middleware"
 	"github.com/rudraprasaaad/task-scheduler/internal/models"
 	"github.com/rudraprasaaad/task-scheduler/internal/queue"
-	"github.com/rudraprasaaad/task-scheduler/internal/redis"
+	"github.com/rudraprasaaad/task-scheduler/internal/quota"
 	"github.com/rudraprasaaad/task-scheduler/internal/repository"
+	"github.com/rudraprasaaad/task-scheduler/internal/service"
+	"github.com/rudraprasaaad/task-scheduler/internal/tasklog"
 	"github.com/rudraprasaaad/task-scheduler/internal/worker"
 )
 
 type TaskHandler struct {
+	tasks      *service.TaskService
 	taskRepo   *repository.TaskRepository
 	workerRepo *repository.WorkerRepository
 	execRepo   *repository.TaskExecutionRepository
 	queue      *queue.RedisQueue
 	cache      *cache.RedisCache
 	pool       *worker.Pool
+	drainGrace time.Duration
 }
 
-func NewTaskHandler(taskRepo *repository.TaskRepository, workerRepo *repository.WorkerRepository, execRepo *repository.TaskExecutionRepository, redisClient *redis.Client, cache *cache.RedisCache, workerCount int) *TaskHandler {
-	redisQueue := queue.NewRedisQueue(redisClient)
-
+func NewTaskHandler(tasks *service.TaskService, taskRepo *repository.TaskRepository, workerRepo *repository.WorkerRepository, execRepo *repository.TaskExecutionRepository, instructionRepo *repository.WorkerInstructionRepository, redisQueue *queue.RedisQueue, cache *cache.RedisCache, logs *tasklog.Store, workerCount int, workerCfg config.WorkerConfig) *TaskHandler {
 	handler := &TaskHandler{
+		tasks:      tasks,
 		taskRepo:   taskRepo,
 		workerRepo: workerRepo,
 		execRepo:   execRepo,
 		queue:      redisQueue,
 		cache:      cache,
+		drainGrace: workerCfg.DrainGracePeriod,
 	}
 
-	handler.pool = worker.NewPool(workerCount, redisQueue, workerRepo, taskRepo, execRepo, cache)
+	handler.pool = worker.NewPool(workerCount, workerCfg, redisQueue, workerRepo, taskRepo, execRepo, instructionRepo, cache, logs)
 
 	return handler
 }
@@ -50,6 +63,10 @@ func (h *TaskHandler) StopWorkers() {
 	h.pool.Stop()
 }
 
+func (h *TaskHandler) DrainWorkers() {
+	h.pool.DrainAll(h.drainGrace)
+}
+
 type CreateTaskRequest struct {
 	Name       string                 `json:"name"`
 	Type       string                 `json:"type"`
@@ -57,6 +74,19 @@ type CreateTaskRequest struct {
 	Priority   models.TaskPriority    `json:"priority,omitempty"`
 	ScheduleAt *time.Time             `json:"schedule_at,omitempty"`
 	MaxRetries int                    `json:"max_retries,omitempty"`
+	Selector   map[string]string      `json:"selector,omitempty"`
+}
+
+func (req *CreateTaskRequest) input() service.CreateTaskInput {
+	return service.CreateTaskInput{
+		Name:       req.Name,
+		Type:       req.Type,
+		Payload:    req.Payload,
+		Priority:   req.Priority,
+		ScheduleAt: req.ScheduleAt,
+		MaxRetries: req.MaxRetries,
+		Selector:   req.Selector,
+	}
 }
 
 func (h *TaskHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
@@ -66,28 +96,18 @@ func (h *TaskHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Name == "" || req.Type == "" {
-		http.Error(w, "Name and type are required", http.StatusBadRequest)
-		return
-	}
-
-	task := models.NewTask(req.Name, req.Type, req.Payload)
-
-	if req.Priority > 0 {
-		task.Priority = req.Priority
-	}
-
-	if req.ScheduleAt != nil {
-		task.ScheduledAt = *req.ScheduleAt
-	}
+	ctx, cancel := detachedContext(r, 5*time.Second)
+	defer cancel()
 
-	if req.MaxRetries > 0 {
-		task.MaxRetries = req.MaxRetries
+	input := req.input()
+	if claims, ok := r.Context().Value(middleware.UserContextkey).(*auth.Claims); ok {
+		input.CreatedBy = claims.UserID
+		input.TenantID = service.CallerTenant(claims)
 	}
 
-	if err := h.queue.Enqueue(task); err != nil {
-		log.Printf("ERROR: Failed to enqueue task in Redis: %v", err)
-		http.Error(w, "Failed to schedule task", http.StatusInternalServerError)
+	task, err := h.tasks.Create(ctx, input)
+	if err != nil {
+		writeServiceError(w, err, "Failed to schedule task")
 		return
 	}
 
@@ -100,12 +120,12 @@ func (h *TaskHandler) GetTaskByID(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	taskID := vars["id"]
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := scopedContext(r, 5*time.Second)
 	defer cancel()
 
-	task, err := h.taskRepo.GetByID(ctx, taskID)
+	task, err := h.tasks.Get(ctx, taskID)
 	if err != nil {
-		http.Error(w, "Task not found", http.StatusNotFound)
+		writeServiceError(w, err, "Failed to get task")
 		return
 	}
 
@@ -113,9 +133,46 @@ func (h *TaskHandler) GetTaskByID(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(task)
 }
 
+func (h *TaskHandler) ListTaskExecutions(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["id"]
+
+	ctx, cancel := scopedContext(r, 5*time.Second)
+	defer cancel()
+
+	executions, err := h.tasks.Executions(ctx, taskID)
+	if err != nil {
+		writeServiceError(w, err, "Failed to list task executions")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"task_id":    taskID,
+		"executions": executions,
+		"count":      len(executions),
+	})
+}
+
+// GetTaskTimeline returns the task, its attempts and a single timeline of
+// creation, scheduling, each attempt, retries and the final state.
+func (h *TaskHandler) GetTaskTimeline(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := scopedContext(r, 5*time.Second)
+	defer cancel()
+
+	history, err := h.tasks.History(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		writeServiceError(w, err, "Failed to get task timeline")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
 func (h *TaskHandler) ListTasks(w http.ResponseWriter, r *http.Request) {
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
+	query := r.URL.Query()
+	limitStr := query.Get("limit")
+	offsetStr := query.Get("offset")
 
 	limit := 50
 	if limitStr != "" {
@@ -131,26 +188,94 @@ func (h *TaskHandler) ListTasks(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	input, err := parseListTasksQuery(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	input.Limit = limit
+	input.Offset = offset
+
+	ctx, cancel := scopedContext(r, 10*time.Second)
 	defer cancel()
 
-	tasks, err := h.taskRepo.List(ctx, limit, offset)
+	tasks, nextPageToken, err := h.tasks.List(ctx, *input)
 	if err != nil {
-		http.Error(w, "Failed to retrieve tasks", http.StatusInternalServerError)
+		writeServiceError(w, err, "Failed to retrieve tasks")
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"tasks":  tasks,
-		"count":  len(tasks),
-		"limit":  limit,
-		"offset": offset,
+		"tasks":           tasks,
+		"count":           len(tasks),
+		"limit":           limit,
+		"offset":          offset,
+		"next_page_token": nextPageToken,
 	})
 }
 
+// parseListTasksQuery reads the task list filters. Multi-valued filters may be
+// repeated or comma-separated, times are RFC 3339 and payload is a JSON object
+// the task payload must contain.
+func parseListTasksQuery(query url.Values) (*service.ListTasksInput, error) {
+	input := &service.ListTasksInput{
+		Statuses:   splitQueryList(query["status"]),
+		Types:      splitQueryList(query["type"]),
+		TenantID:   query.Get("tenant_id"),
+		WorkerID:   query.Get("worker_id"),
+		NamePrefix: query.Get("name_prefix"),
+		Sort:       query.Get("sort"),
+		Order:      query.Get("order"),
+		PageToken:  query.Get("page_token"),
+	}
+
+	for param, target := range map[string]**int{
+		"min_priority": &input.MinPriority,
+		"max_priority": &input.MaxPriority,
+	} {
+		if value := query.Get(param); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %q", param, value)
+			}
+			*target = &n
+		}
+	}
+
+	for param, target := range map[string]**time.Time{
+		"created_after":    &input.CreatedAfter,
+		"created_before":   &input.CreatedBefore,
+		"scheduled_after":  &input.ScheduledAfter,
+		"scheduled_before": &input.ScheduledBefore,
+		"completed_after":  &input.CompletedAfter,
+		"completed_before": &input.CompletedBefore,
+	} {
+		if value := query.Get(param); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: expected an RFC 3339 time", param)
+			}
+			*target = &t
+		}
+	}
+
+	if value := query.Get("payload"); value != "" {
+		if err := json.Unmarshal([]byte(value), &input.Payload); err != nil {
+			return nil, fmt.Errorf("invalid payload: expected a JSON object")
+		}
+	}
+
+	return input, nil
+}
+
+// GetQueueStatus reports the depth of the caller's tenant queue. Only callers
+// allowed to see every tenant get the depth of the whole queue.
 func (h *TaskHandler) GetQueueStatus(w http.ResponseWriter, r *http.Request) {
-	queueSize, err := h.queue.Size()
+	ctx, cancel := scopedContext(r, 5*time.Second)
+	defer cancel()
+
+	queueSize, err := h.tasks.QueueSize(ctx)
 	if err != nil {
 		http.Error(w, "Failed to get queue status", http.StatusInternalServerError)
 		return
@@ -176,36 +301,57 @@ func (h *TaskHandler) GetWorkerStats(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(stats)
 }
 
-func (h *TaskHandler) GetTaskStats(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+type ResizeWorkersRequest struct {
+	Workers int `json:"workers"`
+}
+
+func (h *TaskHandler) ResizeWorkers(w http.ResponseWriter, r *http.Request) {
+	var req ResizeWorkersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
 
-	cachedStats, err := h.cache.GetCachedTaskStats(ctx)
-	if err == nil && cachedStats != nil {
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(cachedStats)
+	before, _ := h.pool.GetWorkerStats()
+	if err := h.pool.Resize(req.Workers); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	audit.SetChange(r.Context(), map[string]interface{}{"target_workers": before["target_workers"]}, map[string]interface{}{"target_workers": req.Workers})
 
-	stats, err := h.taskRepo.GetStats(ctx)
+	stats, err := h.pool.GetWorkerStats()
 	if err != nil {
-		http.Error(w, "Failed to get task stats", http.StatusInternalServerError)
+		http.Error(w, "Failed to get worker stats", http.StatusInternalServerError)
 		return
 	}
 
-	queueSize, _ := h.queue.Size()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"current_workers": stats["current_workers"],
+		"target_workers":  stats["target_workers"],
+		"min_workers":     stats["min_workers"],
+		"max_workers":     stats["max_workers"],
+	})
+}
+
+func (h *TaskHandler) GetTaskStats(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := scopedContext(r, 5*time.Second)
+	defer cancel()
+
+	stats, err := h.tasks.Stats(ctx)
+	if err != nil {
+		http.Error(w, "Failed to get task stats", http.StatusInternalServerError)
+		return
+	}
 
 	responseStats := make(map[string]interface{})
-	total := 0
-	for status, count := range stats {
+	for status, count := range stats.ByStatus {
 		responseStats[status] = count
-		total += count
 	}
 
-	responseStats["queue_size"] = queueSize
-	responseStats["total_tasks"] = total
-
-	h.cache.CacheTaskStats(ctx, stats)
+	responseStats["queue_size"] = stats.QueueSize
+	responseStats["total_tasks"] = stats.Total
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(responseStats)
@@ -215,11 +361,11 @@ func (h *TaskHandler) DeleteTask(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	taskID := vars["id"]
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := detachedContext(r, 5*time.Second)
 	defer cancel()
 
-	if err := h.taskRepo.Delete(ctx, taskID); err != nil {
-		http.Error(w, "Failed to delete task", http.StatusInternalServerError)
+	if err := h.tasks.Delete(ctx, taskID); err != nil {
+		writeServiceError(w, err, "Failed to delete task")
 		return
 	}
 
@@ -230,27 +376,124 @@ func (h *TaskHandler) CancelTask(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	taskID := vars["id"]
 
-	status, err := h.taskRepo.GetStatus(r.Context(), taskID)
-	if err != nil {
-		http.Error(w, "Task Not Found", http.StatusNotFound)
+	ctx, cancel := detachedContext(r, 5*time.Second)
+	defer cancel()
+
+	if _, err := h.tasks.Cancel(ctx, taskID); err != nil {
+		writeServiceError(w, err, "Failed to cancel task")
 		return
 	}
 
-	if status != models.TaskStatusPending {
-		http.Error(w, "Cannot cancel task that is not pending", http.StatusBadRequest)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Task cancelled successfully"})
+}
+
+type RetryTaskRequest struct {
+	ResetRetries bool                   `json:"reset_retries,omitempty"`
+	Payload      map[string]interface{} `json:"payload,omitempty"`
+}
+
+// RetryTask reruns a failed or cancelled task. The body is optional.
+func (h *TaskHandler) RetryTask(w http.ResponseWriter, r *http.Request) {
+	var req RetryTaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
 
-	if err := h.queue.Remove(taskID); err != nil {
-		http.Error(w, "Failed to remove task from queue, it may have already started", http.StatusInternalServerError)
+	ctx, cancel := detachedContext(r, 5*time.Second)
+	defer cancel()
+
+	task, err := h.tasks.Retry(ctx, mux.Vars(r)["id"], service.RetryOptions{
+		ResetRetries: req.ResetRetries,
+		Payload:      req.Payload,
+	})
+	if err != nil {
+		writeServiceError(w, err, "Failed to retry task")
 		return
 	}
 
-	if err := h.taskRepo.UpdateStatus(r.Context(), taskID, models.TaskStatusCancelled); err != nil {
-		http.Error(w, "Failed to update task status in database", http.StatusInternalServerError)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(task)
+}
+
+type UpdateTaskRequest struct {
+	Priority    *models.TaskPriority `json:"priority,omitempty"`
+	ScheduledAt *time.Time           `json:"scheduled_at,omitempty"`
+	MaxRetries  *int                 `json:"max_retries,omitempty"`
+}
+
+// UpdateTask changes the priority, schedule or retry budget of a pending task.
+func (h *TaskHandler) UpdateTask(w http.ResponseWriter, r *http.Request) {
+	var req UpdateTaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"message": "Task cancelled successfully"})
+	ctx, cancel := detachedContext(r, 5*time.Second)
+	defer cancel()
+
+	task, err := h.tasks.Update(ctx, mux.Vars(r)["id"], service.TaskUpdate{
+		Priority:    req.Priority,
+		ScheduledAt: req.ScheduledAt,
+		MaxRetries:  req.MaxRetries,
+	})
+	if err != nil {
+		writeServiceError(w, err, "Failed to update task")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(task)
+}
+
+// writeServiceError maps task service errors onto status codes. Anything
+// unexpected is logged and reported with the given fallback message.
+func writeServiceError(w http.ResponseWriter, err error, fallback string) {
+	var exceeded *quota.ExceededError
+
+	switch {
+	case errors.As(err, &exceeded):
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(exceeded.RetryAfter.Seconds()))))
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+	case errors.Is(err, service.ErrTaskNotFound):
+		http.Error(w, "Task not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrBulkJobNotFound):
+		http.Error(w, "Bulk job not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrBulkQueueFull):
+		w.Header().Set("Retry-After", "30")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	case errors.Is(err, service.ErrInvalidTask):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrInvalidState):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("ERROR: %s: %v", fallback, err)
+		http.Error(w, fallback, http.StatusInternalServerError)
+	}
+}
+
+// scopedContext returns a timeout context for the request, limited to the
+// caller's tenant unless the caller may see every tenant. It is cancelled if
+// the client goes away, and carries the request's audit event so the service
+// can note what it changed.
+func scopedContext(r *http.Request, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return tenantContext(r.Context(), timeout)
+}
+
+// detachedContext is scopedContext for changes that must run to completion
+// once started, so a client disconnecting cannot leave a task half written
+// to the database and the queue.
+func detachedContext(r *http.Request, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return tenantContext(context.WithoutCancel(r.Context()), timeout)
+}
+
+func tenantContext(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	if claims, ok := parent.Value(middleware.UserContextkey).(*auth.Claims); ok {
+		ctx = service.WithTenant(ctx, service.TenantScope(claims))
+	}
+	return ctx, cancel
 }
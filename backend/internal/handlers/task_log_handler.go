@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+	"github.com/rudraprasaaad/task-scheduler/internal/service"
+	"github.com/rudraprasaaad/task-scheduler/internal/tasklog"
+)
+
+const logFollowInterval = 500 * time.Millisecond
+
+type TaskLogHandler struct {
+	tasks *service.TaskService
+	logs  *tasklog.Store
+}
+
+func NewTaskLogHandler(tasks *service.TaskService, logs *tasklog.Store) *TaskLogHandler {
+	return &TaskLogHandler{tasks: tasks, logs: logs}
+}
+
+// GetLogs returns what the executor logged during one attempt, the latest
+// unless attempt=N is given. With follow=true it streams the lines as plain
+// text until the attempt ends or the client goes away.
+func (h *TaskLogHandler) GetLogs(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["id"]
+	query := r.URL.Query()
+
+	follow, _ := strconv.ParseBool(query.Get("follow"))
+
+	ctx, cancel := scopedContext(r, 5*time.Second)
+	defer cancel()
+
+	if _, err := h.tasks.Get(ctx, taskID); err != nil {
+		writeServiceError(w, err, "Failed to get task")
+		return
+	}
+
+	var attempt int
+	if value := query.Get("attempt"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			http.Error(w, "attempt must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		attempt = n
+	} else {
+		n, err := h.logs.LatestAttempt(ctx, taskID)
+		if err != nil {
+			log.Printf("ERROR: %v", err)
+			http.Error(w, "Failed to get task logs", http.StatusInternalServerError)
+			return
+		}
+		if n == 0 {
+			http.Error(w, "No logs recorded for this task yet", http.StatusNotFound)
+			return
+		}
+		attempt = n
+	}
+
+	taskLog, err := h.logs.Read(ctx, taskID, attempt, 0)
+	if errors.Is(err, tasklog.ErrNotFound) {
+		http.Error(w, fmt.Sprintf("No logs recorded for attempt %d", attempt), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, "Failed to get task logs", http.StatusInternalServerError)
+		return
+	}
+
+	if !follow {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(taskLog)
+		return
+	}
+
+	h.follow(w, r, taskLog)
+}
+
+func (h *TaskLogHandler) follow(w http.ResponseWriter, r *http.Request, taskLog *models.TaskLog) {
+	controller := http.NewResponseController(w)
+	if err := controller.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Printf("Failed to clear write deadline for log stream: %v", err)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.Header().Set("X-Task-Attempt", strconv.Itoa(taskLog.Attempt))
+	w.WriteHeader(http.StatusOK)
+
+	ticker := time.NewTicker(logFollowInterval)
+	defer ticker.Stop()
+
+	offset := 0
+	for {
+		for _, line := range taskLog.Lines {
+			if _, err := fmt.Fprintf(w, "%s %s\n", line.Time.Format(time.RFC3339Nano), line.Message); err != nil {
+				return
+			}
+		}
+		offset += len(taskLog.Lines)
+
+		if taskLog.Done {
+			if taskLog.Dropped > 0 {
+				fmt.Fprintf(w, "... %d more lines were dropped after the limit\n", taskLog.Dropped)
+			}
+			controller.Flush()
+			return
+		}
+
+		if err := controller.Flush(); err != nil {
+			log.Printf("Log stream not supported by response writer: %v", err)
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		next, err := h.logs.Read(ctx, taskLog.TaskID, taskLog.Attempt, offset)
+		cancel()
+		if err != nil {
+			log.Printf("ERROR: Failed to follow log for task %s attempt %d: %v", taskLog.TaskID, taskLog.Attempt, err)
+			return
+		}
+		taskLog = next
+	}
+}
@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/rudraprasaaad/task-scheduler/internal/audit"
+	"github.com/rudraprasaaad/task-scheduler/internal/auth"
+	"github.com/rudraprasaaad/task-scheduler/internal/middleware"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+	"github.com/rudraprasaaad/task-scheduler/internal/queue"
+	"github.com/rudraprasaaad/task-scheduler/internal/quota"
+	"github.com/rudraprasaaad/task-scheduler/internal/repository"
+	"github.com/rudraprasaaad/task-scheduler/internal/service"
+)
+
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,99}$`)
+
+// TenantHandler serves the admin endpoints that create tenants and move users
+// between them. Like roles, a user's tenant travels in the token and changes
+// apply when they next refresh their token.
+type TenantHandler struct {
+	tenantRepo *repository.TenantRepository
+	userRepo   *repository.UserRepository
+	quotas     *quota.Manager
+	queue      *queue.RedisQueue
+}
+
+func NewTenantHandler(tenantRepo *repository.TenantRepository, userRepo *repository.UserRepository, quotas *quota.Manager, queue *queue.RedisQueue) *TenantHandler {
+	return &TenantHandler{tenantRepo: tenantRepo, userRepo: userRepo, quotas: quotas, queue: queue}
+}
+
+func (h *TenantHandler) ListTenants(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tenants, err := h.tenantRepo.List(ctx)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, "Failed to list tenants", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"tenants": tenants,
+		"count":   len(tenants),
+	})
+}
+
+func (h *TenantHandler) CreateTenant(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if !tenantIDPattern.MatchString(req.ID) {
+		http.Error(w, "Tenant id must be lowercase letters, digits and dashes, at most 100 characters", http.StatusBadRequest)
+		return
+	}
+
+	tenant := &models.Tenant{ID: req.ID, Name: strings.TrimSpace(req.Name)}
+	if tenant.Name == "" {
+		tenant.Name = tenant.ID
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.tenantRepo.Create(ctx, tenant); err != nil {
+		writeTenantError(w, err, "Failed to create tenant")
+		return
+	}
+
+	log.Printf("Tenant %s created by user %s", tenant.ID, actingUserID(r))
+	audit.SetTarget(r.Context(), "tenant", tenant.ID)
+	audit.SetChange(r.Context(), nil, tenant)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(tenant)
+}
+
+func (h *TenantHandler) SetUserTenant(w http.ResponseWriter, r *http.Request) {
+	userID, ok := parseUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		TenantID string `json:"tenant_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.TenantID == "" {
+		http.Error(w, "Request body must name a tenant_id", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	user, err := h.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		writeTenantError(w, err, "Failed to set user tenant")
+		return
+	}
+
+	if err := h.userRepo.SetTenant(ctx, userID, req.TenantID); err != nil {
+		writeTenantError(w, err, "Failed to set user tenant")
+		return
+	}
+
+	log.Printf("User %d moved to tenant %s by user %s", userID, req.TenantID, actingUserID(r))
+	audit.SetChange(r.Context(), map[string]interface{}{"tenant_id": user.TenantID}, map[string]interface{}{"tenant_id": req.TenantID})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"user_id":   userID,
+		"tenant_id": req.TenantID,
+		"message":   "Tenant changes apply from the user's next token refresh",
+	})
+}
+
+// TenantQuotaRequest replaces a tenant's quota overrides. Omitted or null
+// limits go back to the server-wide defaults.
+type TenantQuotaRequest struct {
+	MaxPending       *int `json:"max_pending"`
+	EnqueuePerMinute *int `json:"enqueue_per_minute"`
+	MaxRunning       *int `json:"max_running"`
+	Weight           *int `json:"weight"`
+}
+
+func (h *TenantHandler) GetQuota(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tenant, err := h.tenantRepo.Get(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		writeTenantError(w, err, "Failed to get tenant")
+		return
+	}
+
+	h.writeQuota(ctx, w, tenant)
+}
+
+func (h *TenantHandler) SetQuota(w http.ResponseWriter, r *http.Request) {
+	var req TenantQuotaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	for name, limit := range map[string]*int{
+		"max_pending":        req.MaxPending,
+		"enqueue_per_minute": req.EnqueuePerMinute,
+		"max_running":        req.MaxRunning,
+	} {
+		if limit != nil && *limit < 0 {
+			http.Error(w, name+" cannot be negative", http.StatusBadRequest)
+			return
+		}
+	}
+	if req.Weight != nil && *req.Weight < 1 {
+		http.Error(w, "weight must be at least 1", http.StatusBadRequest)
+		return
+	}
+
+	tenant := &models.Tenant{
+		ID:               mux.Vars(r)["id"],
+		MaxPending:       req.MaxPending,
+		EnqueuePerMinute: req.EnqueuePerMinute,
+		MaxRunning:       req.MaxRunning,
+		Weight:           req.Weight,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	current, err := h.tenantRepo.Get(ctx, tenant.ID)
+	if err != nil {
+		writeTenantError(w, err, "Failed to get tenant")
+		return
+	}
+
+	if err := h.tenantRepo.SetQuota(ctx, tenant); err != nil {
+		writeTenantError(w, err, "Failed to set tenant quota")
+		return
+	}
+	h.quotas.Invalidate(tenant.ID)
+
+	log.Printf("Quota of tenant %s changed by user %s", tenant.ID, actingUserID(r))
+	audit.SetChange(r.Context(), TenantQuotaRequest{
+		MaxPending:       current.MaxPending,
+		EnqueuePerMinute: current.EnqueuePerMinute,
+		MaxRunning:       current.MaxRunning,
+		Weight:           current.Weight,
+	}, req)
+	h.writeQuota(ctx, w, tenant)
+}
+
+// GetOwnQuota shows the caller's tenant its limits and how much of them it
+// is using.
+func (h *TenantHandler) GetOwnQuota(w http.ResponseWriter, r *http.Request) {
+	tenantID := models.DefaultTenantID
+	if claims, ok := r.Context().Value(middleware.UserContextkey).(*auth.Claims); ok {
+		tenantID = service.CallerTenant(claims)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tenant, err := h.tenantRepo.Get(ctx, tenantID)
+	if err != nil {
+		writeTenantError(w, err, "Failed to get tenant")
+		return
+	}
+
+	h.writeQuota(ctx, w, tenant)
+}
+
+func (h *TenantHandler) writeQuota(ctx context.Context, w http.ResponseWriter, tenant *models.Tenant) {
+	usage, err := h.usage(ctx, tenant.ID)
+	if err != nil {
+		log.Printf("ERROR: %v", err)
+		http.Error(w, "Failed to get tenant quota usage", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"tenant_id": tenant.ID,
+		"overrides": tenant,
+		"limits":    tenant.Quota(h.quotas.Defaults()),
+		"usage":     usage,
+	})
+}
+
+func (h *TenantHandler) usage(ctx context.Context, tenantID string) (map[string]int, error) {
+	pending, err := h.queue.PendingCount(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	running, err := h.queue.RunningCount(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	enqueued, err := h.quotas.EnqueuedThisMinute(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]int{
+		"pending":              pending,
+		"running":              running,
+		"enqueued_this_minute": enqueued,
+	}, nil
+}
+
+func writeTenantError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, repository.ErrUserNotFound), errors.Is(err, repository.ErrTenantNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, repository.ErrTenantExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("ERROR: %s: %v", message, err)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
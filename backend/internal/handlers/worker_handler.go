@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/rudraprasaaad/task-scheduler/internal/audit"
+	"github.com/rudraprasaaad/task-scheduler/internal/auth"
+	"github.com/rudraprasaaad/task-scheduler/internal/middleware"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+	"github.com/rudraprasaaad/task-scheduler/internal/repository"
+)
+
+type InstructionNotifier interface {
+	NotifyInstructions(ctx context.Context, workerID string)
+}
+
+type WorkerHandler struct {
+	workerRepo      *repository.WorkerRepository
+	instructionRepo *repository.WorkerInstructionRepository
+	credentialRepo  *repository.WorkerCredentialRepository
+	notifier        InstructionNotifier
+	drainGrace      time.Duration
+}
+
+func NewWorkerHandler(workerRepo *repository.WorkerRepository, instructionRepo *repository.WorkerInstructionRepository, credentialRepo *repository.WorkerCredentialRepository, notifier InstructionNotifier, drainGrace time.Duration) *WorkerHandler {
+	return &WorkerHandler{
+		workerRepo:      workerRepo,
+		instructionRepo: instructionRepo,
+		credentialRepo:  credentialRepo,
+		notifier:        notifier,
+		drainGrace:      drainGrace,
+	}
+}
+
+type CreateInstructionRequest struct {
+	Type   models.InstructionType `json:"type"`
+	Params map[string]string      `json:"params,omitempty"`
+}
+
+func (h *WorkerHandler) CreateInstruction(w http.ResponseWriter, r *http.Request) {
+	workerID := mux.Vars(r)["id"]
+
+	var req CreateInstructionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	instruction := models.NewWorkerInstruction(workerID, req.Type, req.Params)
+	if err := instruction.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !h.queueInstruction(w, r, instruction) {
+		return
+	}
+
+	audit.SetChange(r.Context(), nil, instruction)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(instruction)
+}
+
+func (h *WorkerHandler) ListInstructions(w http.ResponseWriter, r *http.Request) {
+	workerID := mux.Vars(r)["id"]
+
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	var statuses []string
+	if status := r.URL.Query().Get("status"); status != "" {
+		statuses = strings.Split(status, ",")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	instructions, err := h.instructionRepo.ListByWorker(ctx, workerID, statuses, limit)
+	if err != nil {
+		log.Printf("ERROR: Failed to list instructions for worker %s: %v", workerID, err)
+		http.Error(w, "Failed to list instructions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"worker_id":    workerID,
+		"instructions": instructions,
+		"count":        len(instructions),
+	})
+}
+
+type DrainWorkerRequest struct {
+	GracePeriod string `json:"grace_period,omitempty"`
+}
+
+func (h *WorkerHandler) DrainWorker(w http.ResponseWriter, r *http.Request) {
+	workerID := mux.Vars(r)["id"]
+
+	var req DrainWorkerRequest
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+	}
+
+	grace := h.drainGrace
+	if req.GracePeriod != "" {
+		parsed, err := time.ParseDuration(req.GracePeriod)
+		if err != nil || parsed < 0 {
+			http.Error(w, "Invalid grace_period", http.StatusBadRequest)
+			return
+		}
+		grace = parsed
+	}
+
+	instruction := models.NewWorkerInstruction(workerID, models.InstructionDrain, map[string]string{
+		"grace_period": grace.String(),
+	})
+
+	if !h.queueInstruction(w, r, instruction) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{
+		"message":        "Worker drain requested",
+		"worker_id":      workerID,
+		"grace_period":   grace.String(),
+		"instruction_id": instruction.ID,
+	})
+}
+
+func (h *WorkerHandler) queueInstruction(w http.ResponseWriter, r *http.Request, instruction *models.WorkerInstruction) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := h.workerRepo.GetByID(ctx, instruction.WorkerID); err != nil {
+		http.Error(w, "Worker not found", http.StatusNotFound)
+		return false
+	}
+
+	if claims, ok := r.Context().Value(middleware.UserContextkey).(*auth.Claims); ok {
+		instruction.CreatedBy = claims.UserID
+	}
+
+	if err := h.instructionRepo.Create(ctx, instruction); err != nil {
+		log.Printf("ERROR: Failed to queue instruction for worker %s: %v", instruction.WorkerID, err)
+		http.Error(w, "Failed to queue instruction", http.StatusInternalServerError)
+		return false
+	}
+
+	log.Printf("Queued %s instruction %s for worker %s", instruction.Type, instruction.ID, instruction.WorkerID)
+	h.notifier.NotifyInstructions(ctx, instruction.WorkerID)
+
+	return true
+}
+
+const defaultEnrollmentTTL = time.Hour
+
+type CreateEnrollmentTokenRequest struct {
+	WorkerID string `json:"worker_id,omitempty"`
+	TTL      string `json:"ttl,omitempty"`
+}
+
+func (h *WorkerHandler) CreateEnrollmentToken(w http.ResponseWriter, r *http.Request) {
+	var req CreateEnrollmentTokenRequest
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+	}
+
+	ttl := defaultEnrollmentTTL
+	if req.TTL != "" {
+		parsed, err := time.ParseDuration(req.TTL)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "Invalid ttl", http.StatusBadRequest)
+			return
+		}
+		ttl = parsed
+	}
+
+	secret, err := auth.GenerateEnrollmentToken()
+	if err != nil {
+		log.Printf("ERROR: Failed to generate enrollment token: %v", err)
+		http.Error(w, "Failed to create enrollment token", http.StatusInternalServerError)
+		return
+	}
+
+	token := models.NewEnrollmentToken(auth.HashSecret(secret), req.WorkerID, ttl)
+	if claims, ok := r.Context().Value(middleware.UserContextkey).(*auth.Claims); ok {
+		token.CreatedBy = claims.UserID
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.credentialRepo.CreateEnrollmentToken(ctx, token); err != nil {
+		log.Printf("ERROR: Failed to store enrollment token: %v", err)
+		http.Error(w, "Failed to create enrollment token", http.StatusInternalServerError)
+		return
+	}
+	audit.SetTarget(r.Context(), "enrollment_token", token.ID)
+	audit.SetChange(r.Context(), nil, token)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"token":      secret,
+		"worker_id":  token.WorkerID,
+		"expires_at": token.ExpiresAt,
+	})
+}
+
+func (h *WorkerHandler) RevokeCredentials(w http.ResponseWriter, r *http.Request) {
+	workerID := mux.Vars(r)["id"]
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	revoked, err := h.credentialRepo.RevokeWorker(ctx, workerID)
+	if err != nil {
+		log.Printf("ERROR: Failed to revoke credentials for worker %s: %v", workerID, err)
+		http.Error(w, "Failed to revoke credentials", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Revoked %d credentials for worker %s", revoked, workerID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"worker_id": workerID,
+		"revoked":   revoked,
+	})
+}
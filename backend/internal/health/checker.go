@@ -0,0 +1,143 @@
+package health
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/database"
+	taskpb "github.com/rudraprasaaad/task-scheduler/internal/grpc/generated/task"
+	workerpb "github.com/rudraprasaaad/task-scheduler/internal/grpc/generated/worker"
+	"github.com/rudraprasaaad/task-scheduler/internal/redis"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	ComponentDatabase = "database"
+	ComponentRedis    = "redis"
+)
+
+// serviceDependencies lists the components each gRPC service needs to do
+// useful work. The empty name is the overall server status.
+var serviceDependencies = map[string][]string{
+	"": {ComponentDatabase, ComponentRedis},
+	taskpb.TaskService_ServiceDesc.ServiceName:     {ComponentDatabase, ComponentRedis},
+	workerpb.WorkerService_ServiceDesc.ServiceName: {ComponentDatabase},
+}
+
+type Report struct {
+	Healthy    bool
+	CheckedAt  time.Time
+	Components map[string]error
+}
+
+// Checker polls the database and Redis and publishes the result to the
+// standard grpc.health.v1 service, so load balancers, the HTTP /health route
+// and the WorkerService.HealthCheck RPC all see the same status.
+type Checker struct {
+	db       *database.DB
+	redis    *redis.Client
+	interval time.Duration
+	server   *grpchealth.Server
+
+	mutex  sync.RWMutex
+	report *Report
+}
+
+func NewChecker(db *database.DB, redisClient *redis.Client, interval time.Duration) *Checker {
+	server := grpchealth.NewServer()
+	for service := range serviceDependencies {
+		server.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+
+	return &Checker{
+		db:       db,
+		redis:    redisClient,
+		interval: interval,
+		server:   server,
+	}
+}
+
+// Server is the grpc.health.v1 implementation to register on gRPC servers.
+func (c *Checker) Server() *grpchealth.Server {
+	return c.server
+}
+
+func (c *Checker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Check(ctx)
+		}
+	}
+}
+
+// Check probes every component now and updates the published statuses.
+func (c *Checker) Check(ctx context.Context) *Report {
+	report := &Report{
+		Healthy:   true,
+		CheckedAt: time.Now(),
+		Components: map[string]error{
+			ComponentDatabase: c.db.Health(),
+			ComponentRedis:    c.redis.Health(ctx),
+		},
+	}
+
+	for _, err := range report.Components {
+		if err != nil {
+			report.Healthy = false
+		}
+	}
+
+	c.mutex.Lock()
+	previous := c.report
+	c.report = report
+	c.mutex.Unlock()
+
+	for component, err := range report.Components {
+		var wasErr error
+		if previous != nil {
+			wasErr = previous.Components[component]
+		}
+
+		switch {
+		case err != nil && (previous == nil || wasErr == nil):
+			log.Printf("ERROR: %s health check failed: %v", component, err)
+		case err == nil && wasErr != nil:
+			log.Printf("%s health check recovered", component)
+		}
+	}
+
+	for service, components := range serviceDependencies {
+		status := healthpb.HealthCheckResponse_SERVING
+		for _, component := range components {
+			if report.Components[component] != nil {
+				status = healthpb.HealthCheckResponse_NOT_SERVING
+			}
+		}
+		c.server.SetServingStatus(service, status)
+	}
+
+	return report
+}
+
+// Report returns the latest check, or nil before the first one.
+func (c *Checker) Report() *Report {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.report
+}
+
+// Shutdown marks every service NOT_SERVING so load balancers stop routing new
+// calls while the server drains. Later checks no longer change the status.
+func (c *Checker) Shutdown() {
+	c.server.Shutdown()
+}
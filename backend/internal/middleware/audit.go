@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/rudraprasaaad/task-scheduler/internal/audit"
+	"github.com/rudraprasaaad/task-scheduler/internal/auth"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+)
+
+type auditedRoute struct {
+	action     string
+	targetType string
+}
+
+// routeActions names the action and target of each mutating route. A route
+// missing here is still recorded, under its method and path template.
+var routeActions = map[string]auditedRoute{
+	"POST /api/v1/tasks":                     {"task.create", "task"},
+	"POST /api/v1/tasks/bulk/create":         {"task.bulk_create", "bulk_job"},
+	"POST /api/v1/tasks/bulk/{operation}":    {"task.bulk_{operation}", "bulk_job"},
+	"PATCH /api/v1/tasks/{id}":               {"task.update", "task"},
+	"DELETE /api/v1/tasks/{id}":              {"task.delete", "task"},
+	"POST /api/v1/tasks/{id}/cancel":         {"task.cancel", "task"},
+	"POST /api/v1/tasks/{id}/retry":          {"task.retry", "task"},
+	"POST /api/v1/workers/{id}/drain":        {"worker.drain", "worker"},
+	"POST /api/v1/workers/{id}/instructions": {"worker.instruct", "worker"},
+
+	"POST /api/v1/admin/workers/resize":             {"worker.resize_pool", ""},
+	"POST /api/v1/admin/workers/enrollment-tokens":  {"worker.create_enrollment_token", "enrollment_token"},
+	"DELETE /api/v1/admin/workers/{id}/credentials": {"worker.revoke_credentials", "worker"},
+	"PUT /api/v1/admin/users/{id}/roles/{role}":     {"user.grant_role", "user"},
+	"DELETE /api/v1/admin/users/{id}/roles/{role}":  {"user.revoke_role", "user"},
+	"POST /api/v1/admin/tenants":                    {"tenant.create", "tenant"},
+	"PUT /api/v1/admin/tenants/{id}/quota":          {"tenant.set_quota", "tenant"},
+	"PUT /api/v1/admin/users/{id}/tenant":           {"user.set_tenant", "user"},
+	"DELETE /api/v1/admin/users/{id}/sessions":      {"user.revoke_sessions", "user"},
+	"POST /api/v1/admin/service-accounts":           {"service_account.create", "service_account"},
+	"POST /api/v1/admin/api-keys":                   {"api_key.create", "api_key"},
+	"DELETE /api/v1/admin/api-keys/{id}":            {"api_key.revoke", "api_key"},
+}
+
+// AuditMiddleware records every mutating call in the audit log once it has
+// been answered, including calls Authorize turns away. It runs after
+// AuthMiddleware so the caller is known. Handlers add the target and the
+// change they made through the event carried in the request context.
+func AuditMiddleware(recorder audit.Recorder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			event := newRouteEvent(r)
+			claims, _ := r.Context().Value(UserContextkey).(*auth.Claims)
+			ctx := audit.NewContext(r.Context(), event)
+			audit.SetActor(ctx, claims)
+
+			wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(wrapped, r.WithContext(ctx))
+
+			event.StatusCode = wrapped.statusCode
+			switch {
+			case wrapped.statusCode == http.StatusUnauthorized, wrapped.statusCode == http.StatusForbidden:
+				event.Outcome = models.AuditDenied
+			case wrapped.statusCode >= 400:
+				event.Outcome = models.AuditFailure
+			default:
+				event.Outcome = models.AuditSuccess
+			}
+
+			recorder.Record(ctx, event)
+		})
+	}
+}
+
+func newRouteEvent(r *http.Request) *models.AuditEvent {
+	var template string
+	vars := mux.Vars(r)
+	if route := mux.CurrentRoute(r); route != nil {
+		template, _ = route.GetPathTemplate()
+	}
+
+	key := r.Method + " " + template
+	route, ok := routeActions[key]
+	if !ok {
+		route.action = key
+	}
+
+	event := &models.AuditEvent{
+		Action:     strings.ReplaceAll(route.action, "{operation}", vars["operation"]),
+		TargetType: route.targetType,
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		IPAddress:  clientIP(r),
+		UserAgent:  r.UserAgent(),
+	}
+	if route.targetType != "" {
+		event.TargetID = vars["id"]
+	}
+
+	return event
+}
+
+func clientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return ip
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"strings"
 
 	"github.com/rudraprasaaad/task-scheduler/internal/auth"
 )
@@ -11,17 +12,19 @@ type contextKey string
 
 const UserContextkey = contextKey("user")
 
-func AuthMiddleware(jwtSecret string) func(http.Handler) http.Handler {
+// AuthMiddleware accepts the auth_token cookie set at login, or a JWT or API
+// key sent as "Authorization: Bearer <token>". API keys may also be sent in
+// the X-API-Key header. JWTs that were revoked by logout are rejected.
+func AuthMiddleware(jwtSecret string, apiKeys auth.APIKeyStore, revocations auth.RevocationChecker) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			cookie, err := r.Cookie("auth_token")
-			if err != nil {
+			tokenStr := RequestToken(r)
+			if tokenStr == "" {
 				http.Error(w, "Unauthorized: No token provided", http.StatusUnauthorized)
 				return
 			}
 
-			tokenStr := cookie.Value
-			claims, err := auth.ValidateToken(tokenStr, jwtSecret)
+			claims, err := authenticate(r.Context(), tokenStr, jwtSecret, apiKeys, revocations)
 			if err != nil {
 				http.Error(w, "Unauthorized: Invalid token", http.StatusUnauthorized)
 				return
@@ -32,3 +35,39 @@ func AuthMiddleware(jwtSecret string) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// RequestToken returns the credential the request authenticates with.
+func RequestToken(r *http.Request) string {
+	if token := headerToken(r); token != "" {
+		return token
+	}
+
+	if cookie, err := r.Cookie("auth_token"); err == nil {
+		return cookie.Value
+	}
+
+	return ""
+}
+
+// headerToken returns the API key or bearer token sent in a header, if any.
+// Other Authorization schemes are not credentials this API accepts.
+func headerToken(r *http.Request) string {
+	if key := strings.TrimSpace(r.Header.Get("X-API-Key")); key != "" {
+		return key
+	}
+
+	if header := r.Header.Get("Authorization"); header != "" {
+		if token, ok := strings.CutPrefix(header, "Bearer "); ok {
+			return strings.TrimSpace(token)
+		}
+	}
+
+	return ""
+}
+
+func authenticate(ctx context.Context, token, jwtSecret string, apiKeys auth.APIKeyStore, revocations auth.RevocationChecker) (*auth.Claims, error) {
+	if auth.IsAPIKey(token) {
+		return auth.AuthenticateAPIKey(ctx, apiKeys, token)
+	}
+	return auth.ValidateActiveToken(ctx, token, jwtSecret, revocations)
+}
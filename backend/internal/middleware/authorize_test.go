@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/rudraprasaaad/task-scheduler/internal/auth"
+)
+
+func TestAuthorize(t *testing.T) {
+	router := mux.NewRouter()
+	api := router.PathPrefix("/api/v1").Subrouter()
+	api.Use(Authorize)
+
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	api.HandleFunc("/tasks", ok).Methods("GET", "POST")
+	api.HandleFunc("/tasks/{id}", ok).Methods("DELETE")
+	api.HandleFunc("/workers/{id}/drain", ok).Methods("POST")
+	api.HandleFunc("/admin/users/{id}/roles/{role}", ok).Methods("PUT")
+	api.HandleFunc("/audit", ok).Methods("GET")
+	api.HandleFunc("/unlisted", ok).Methods("GET")
+
+	admin := &auth.Claims{Role: auth.RoleAdmin, Roles: []string{auth.RoleAdmin}}
+	user := &auth.Claims{Role: auth.RoleUser, Roles: []string{auth.RoleUser}}
+	worker := &auth.Claims{Role: auth.RoleWorker}
+	readKey := &auth.Claims{Role: auth.RoleService, Permissions: []auth.Permission{auth.PermTaskRead}}
+
+	tests := []struct {
+		method string
+		path   string
+		claims *auth.Claims
+		want   int
+	}{
+		{"GET", "/api/v1/tasks", nil, http.StatusUnauthorized},
+		{"GET", "/api/v1/tasks", user, http.StatusOK},
+		{"POST", "/api/v1/tasks", user, http.StatusOK},
+		{"GET", "/api/v1/tasks", worker, http.StatusForbidden},
+		{"GET", "/api/v1/tasks", readKey, http.StatusOK},
+		{"POST", "/api/v1/tasks", readKey, http.StatusForbidden},
+		{"DELETE", "/api/v1/tasks/t-1", user, http.StatusForbidden},
+		{"DELETE", "/api/v1/tasks/t-1", admin, http.StatusOK},
+		{"POST", "/api/v1/workers/w-1/drain", user, http.StatusForbidden},
+		{"POST", "/api/v1/workers/w-1/drain", admin, http.StatusOK},
+		{"PUT", "/api/v1/admin/users/7/roles/admin", user, http.StatusForbidden},
+		{"PUT", "/api/v1/admin/users/7/roles/admin", admin, http.StatusOK},
+		{"GET", "/api/v1/audit", user, http.StatusForbidden},
+		{"GET", "/api/v1/audit", admin, http.StatusOK},
+		{"GET", "/api/v1/unlisted", admin, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		role := "anonymous"
+		if tt.claims != nil {
+			role = tt.claims.Role
+		}
+
+		t.Run(tt.method+" "+tt.path+" as "+role, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.claims != nil {
+				req = req.WithContext(context.WithValue(req.Context(), UserContextkey, tt.claims))
+			}
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("got status %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoutePermissionsAreKnown(t *testing.T) {
+	for route, permission := range routePermissions {
+		if !auth.KnownPermission(permission) {
+			t.Errorf("%s needs %s, which no role grants", route, permission)
+		}
+	}
+}
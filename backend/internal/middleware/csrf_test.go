@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rudraprasaaad/task-scheduler/internal/auth"
+)
+
+const testSecret = "test-secret"
+
+type noRevocations struct{}
+
+func (noRevocations) IsRevoked(context.Context, *auth.Claims) (bool, error) { return false, nil }
+
+func TestCSRFMiddleware(t *testing.T) {
+	token, err := auth.GenrerateToken(&auth.Claims{
+		UserID: "user-1",
+		Role:   auth.RoleUser,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}, testSecret)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		cookie  bool
+		headers map[string]string
+		want    int
+	}{
+		{"safe method", http.MethodGet, true, nil, http.StatusOK},
+		{"no session cookie", http.MethodPost, false, nil, http.StatusOK},
+		{"cookie without token", http.MethodPost, true, nil, http.StatusForbidden},
+		{"cookie with matching token", http.MethodPost, true, map[string]string{CSRFHeaderName: "csrf"}, http.StatusOK},
+		{"cookie with wrong token", http.MethodPost, true, map[string]string{CSRFHeaderName: "other"}, http.StatusForbidden},
+		{"valid bearer token", http.MethodPost, true, map[string]string{"Authorization": "Bearer " + token}, http.StatusOK},
+		{"invalid bearer token", http.MethodPost, true, map[string]string{"Authorization": "Bearer nope"}, http.StatusForbidden},
+		{"basic authorization", http.MethodPost, true, map[string]string{"Authorization": "Basic x"}, http.StatusForbidden},
+		{"blank api key", http.MethodPost, true, map[string]string{"X-API-Key": " "}, http.StatusForbidden},
+	}
+
+	handler := CSRFMiddleware(testSecret, nil, noRevocations{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/tasks", nil)
+			if tt.cookie {
+				req.AddCookie(&http.Cookie{Name: "auth_token", Value: "session"})
+				req.AddCookie(&http.Cookie{Name: CSRFCookieName, Value: "csrf"})
+			}
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("got status %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// ServiceAccount is a non-human identity that other services use to call the
+// API. It belongs to one tenant and authenticates with its API keys.
+type ServiceAccount struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	TenantID    string    `json:"tenant_id"`
+	CreatedBy   string    `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type APIKey struct {
+	ID                 string     `json:"id"`
+	ServiceAccountID   string     `json:"service_account_id"`
+	ServiceAccountName string     `json:"service_account_name,omitempty"`
+	TenantID           string     `json:"tenant_id,omitempty"`
+	Name               string     `json:"name"`
+	SecretHash         string     `json:"-"`
+	Scopes             []string   `json:"scopes"`
+	CreatedBy          string     `json:"created_by,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	ExpiresAt          *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt         *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt          *time.Time `json:"revoked_at,omitempty"`
+}
+
+func NewServiceAccount(name, description, tenantID string) *ServiceAccount {
+	return &ServiceAccount{
+		ID:          generateID(),
+		Name:        name,
+		Description: description,
+		TenantID:    tenantID,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// NewAPIKey returns a key with no expiry when ttl is zero.
+func NewAPIKey(serviceAccountID, name, secretHash string, scopes []string, ttl time.Duration) *APIKey {
+	now := time.Now()
+
+	key := &APIKey{
+		ID:               generateID(),
+		ServiceAccountID: serviceAccountID,
+		Name:             name,
+		SecretHash:       secretHash,
+		Scopes:           slices.Compact(slices.Sorted(slices.Values(scopes))),
+		CreatedAt:        now,
+	}
+
+	if ttl > 0 {
+		expiresAt := now.Add(ttl)
+		key.ExpiresAt = &expiresAt
+	}
+
+	return key
+}
+
+// Active reports whether the key can still authenticate.
+func (k *APIKey) Active() bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || time.Now().Before(*k.ExpiresAt)
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	ActorUser      = "user"
+	ActorAPIKey    = "api_key"
+	ActorWorker    = "worker"
+	ActorCron      = "cron"
+	ActorAnonymous = "anonymous"
+
+	AuditSuccess = "success"
+	AuditFailure = "failure"
+	AuditDenied  = "denied"
+)
+
+// AuditEvent records who changed what, when and from where. Before and After
+// hold snapshots of the target when the change captured them, and Changes the
+// fields that differ between the two.
+type AuditEvent struct {
+	ID         int64                  `json:"id"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	ActorType  string                 `json:"actor_type"`
+	ActorID    string                 `json:"actor_id,omitempty"`
+	TenantID   string                 `json:"tenant_id,omitempty"`
+	Action     string                 `json:"action"`
+	TargetType string                 `json:"target_type,omitempty"`
+	TargetID   string                 `json:"target_id,omitempty"`
+	Outcome    string                 `json:"outcome"`
+	StatusCode int                    `json:"status_code,omitempty"`
+	Before     json.RawMessage        `json:"before,omitempty"`
+	After      json.RawMessage        `json:"after,omitempty"`
+	Changes    map[string]FieldChange `json:"changes,omitempty"`
+	Method     string                 `json:"method,omitempty"`
+	Path       string                 `json:"path,omitempty"`
+	IPAddress  string                 `json:"ip_address,omitempty"`
+	UserAgent  string                 `json:"user_agent,omitempty"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+}
+
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+type BulkOperation string
+
+const (
+	BulkCreate       BulkOperation = "create"
+	BulkCancel       BulkOperation = "cancel"
+	BulkRetry        BulkOperation = "retry"
+	BulkReprioritize BulkOperation = "reprioritize"
+	BulkReschedule   BulkOperation = "reschedule"
+	BulkDelete       BulkOperation = "delete"
+)
+
+type BulkJobStatus string
+
+const (
+	BulkJobPending   BulkJobStatus = "pending"
+	BulkJobRunning   BulkJobStatus = "running"
+	BulkJobCompleted BulkJobStatus = "completed"
+	BulkJobFailed    BulkJobStatus = "failed"
+)
+
+// BulkJob tracks a bulk task operation running in the background. Params
+// holds the operation's arguments, e.g. the new priority or the filter the
+// tasks were selected by.
+type BulkJob struct {
+	ID          string                 `json:"id"`
+	Operation   BulkOperation          `json:"operation"`
+	Status      BulkJobStatus          `json:"status"`
+	Params      map[string]interface{} `json:"params,omitempty"`
+	Total       int                    `json:"total"`
+	Processed   int                    `json:"processed"`
+	Succeeded   int                    `json:"succeeded"`
+	Failed      int                    `json:"failed"`
+	Error       string                 `json:"error,omitempty"`
+	CreatedBy   string                 `json:"created_by,omitempty"`
+	TenantID    string                 `json:"tenant_id,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+	StartedAt   *time.Time             `json:"started_at,omitempty"`
+	CompletedAt *time.Time             `json:"completed_at,omitempty"`
+}
+
+func NewBulkJob(operation BulkOperation, params map[string]interface{}) *BulkJob {
+	return &BulkJob{
+		ID:        generateID(),
+		Operation: operation,
+		Status:    BulkJobPending,
+		Params:    params,
+		CreatedAt: time.Now(),
+	}
+}
+
+// BulkJobItem is the outcome for one task in a bulk job. Index is the
+// position in the submitted list, so create items without a task ID can
+// still be matched to their input.
+type BulkJobItem struct {
+	JobID  string `json:"job_id"`
+	Index  int    `json:"index"`
+	TaskID string `json:"task_id,omitempty"`
+	OK     bool   `json:"ok"`
+	Error  string `json:"error,omitempty"`
+}
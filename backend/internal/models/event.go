@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// WorkflowPayloadKey is the payload field that groups related tasks into a
+// workflow for event filtering.
+const WorkflowPayloadKey = "workflow_id"
+
+type TaskEvent struct {
+	ID        string     `json:"id"`
+	TaskID    string     `json:"task_id"`
+	TaskType  string     `json:"task_type"`
+	TenantID  string     `json:"tenant_id,omitempty"`
+	Workflow  string     `json:"workflow,omitempty"`
+	OldStatus TaskStatus `json:"old_status,omitempty"`
+	NewStatus TaskStatus `json:"new_status"`
+	WorkerID  string     `json:"worker_id,omitempty"`
+	Error     string     `json:"error,omitempty"`
+	Timestamp time.Time  `json:"timestamp"`
+}
+
+func NewTaskEvent(task *Task, oldStatus TaskStatus) *TaskEvent {
+	return &TaskEvent{
+		TaskID:    task.ID,
+		TaskType:  task.Type,
+		TenantID:  task.TenantID,
+		Workflow:  task.Workflow(),
+		OldStatus: oldStatus,
+		NewStatus: task.Status,
+		WorkerID:  task.WorkerID,
+		Error:     task.Error,
+		Timestamp: time.Now(),
+	}
+}
+
+func (t *Task) Workflow() string {
+	workflow, _ := t.Payload[WorkflowPayloadKey].(string)
+	return workflow
+}
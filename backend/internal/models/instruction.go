@@ -0,0 +1,96 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type InstructionType string
+
+const (
+	InstructionPause           InstructionType = "pause"
+	InstructionResume          InstructionType = "resume"
+	InstructionDrain           InstructionType = "drain"
+	InstructionCancelTask      InstructionType = "cancel_task"
+	InstructionReloadExecutors InstructionType = "reload_executors"
+	InstructionSetConcurrency  InstructionType = "set_concurrency"
+)
+
+type InstructionStatus string
+
+const (
+	InstructionStatusPending      InstructionStatus = "pending"
+	InstructionStatusDelivered    InstructionStatus = "delivered"
+	InstructionStatusAcknowledged InstructionStatus = "acknowledged"
+	InstructionStatusApplied      InstructionStatus = "applied"
+	InstructionStatusFailed       InstructionStatus = "failed"
+)
+
+// TypeLimitParamPrefix prefixes set_concurrency params that limit a single
+// task type, e.g. "limit.report": "1".
+const TypeLimitParamPrefix = "limit."
+
+type WorkerInstruction struct {
+	ID             string            `json:"id"`
+	WorkerID       string            `json:"worker_id"`
+	Type           InstructionType   `json:"type"`
+	Params         map[string]string `json:"params,omitempty"`
+	Status         InstructionStatus `json:"status"`
+	Error          string            `json:"error,omitempty"`
+	CreatedBy      string            `json:"created_by,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	DeliveredAt    *time.Time        `json:"delivered_at,omitempty"`
+	AcknowledgedAt *time.Time        `json:"acknowledged_at,omitempty"`
+	AppliedAt      *time.Time        `json:"applied_at,omitempty"`
+}
+
+func NewWorkerInstruction(workerID string, instructionType InstructionType, params map[string]string) *WorkerInstruction {
+	if params == nil {
+		params = make(map[string]string)
+	}
+
+	return &WorkerInstruction{
+		ID:        generateID(),
+		WorkerID:  workerID,
+		Type:      instructionType,
+		Params:    params,
+		Status:    InstructionStatusPending,
+		CreatedAt: time.Now(),
+	}
+}
+
+func (i *WorkerInstruction) Validate() error {
+	switch i.Type {
+	case InstructionPause, InstructionResume, InstructionReloadExecutors:
+		return nil
+	case InstructionDrain:
+		if grace, ok := i.Params["grace_period"]; ok {
+			if _, err := time.ParseDuration(grace); err != nil {
+				return fmt.Errorf("invalid grace_period: %w", err)
+			}
+		}
+		return nil
+	case InstructionCancelTask:
+		if i.Params["task_id"] == "" {
+			return fmt.Errorf("cancel_task requires a task_id param")
+		}
+		return nil
+	case InstructionSetConcurrency:
+		if len(i.Params) == 0 {
+			return fmt.Errorf("set_concurrency requires a slots or %s<type> param", TypeLimitParamPrefix)
+		}
+		for key, value := range i.Params {
+			if key != "slots" && !strings.HasPrefix(key, TypeLimitParamPrefix) {
+				return fmt.Errorf("unknown set_concurrency param %q", key)
+			}
+			if n, err := strconv.Atoi(value); err != nil || n < 0 || (key == "slots" && n == 0) {
+				return fmt.Errorf("invalid value %q for %s", value, key)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown instruction type %q", i.Type)
+	}
+}
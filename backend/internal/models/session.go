@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Session is a login. It outlives the short-lived access tokens issued for it
+// and ends when it expires, is logged out or its refresh token is replayed.
+type Session struct {
+	ID           string     `json:"id"`
+	UserID       int64      `json:"user_id"`
+	RefreshHash  string     `json:"-"`
+	PreviousHash string     `json:"-"`
+	UserAgent    string     `json:"user_agent,omitempty"`
+	IPAddress    string     `json:"ip_address,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	RefreshedAt  *time.Time `json:"refreshed_at,omitempty"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+}
+
+func NewSession(userID int64, ttl time.Duration) *Session {
+	now := time.Now()
+
+	return &Session{
+		ID:        generateID(),
+		UserID:    userID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+}
+
+func (s *Session) Active() bool {
+	return s.RevokedAt == nil && time.Now().Before(s.ExpiresAt)
+}
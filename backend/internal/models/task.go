@@ -40,17 +40,47 @@ type Task struct {
 	CompletedAt *time.Time             `json:"completed_at,omitempty"`
 	Error       string                 `json:"error,omitempty"`
 	WorkerID    string                 `json:"worker_id,omitempty"`
+	Selector    map[string]string      `json:"selector,omitempty"`
+	TenantID    string                 `json:"tenant_id,omitempty"`
+	CreatedBy   string                 `json:"created_by,omitempty"`
 }
 
 type TaskExecution struct {
 	ID              string    `json:"id"`
 	TaskID          string    `json:"task_id"`
+	Attempt         int       `json:"attempt"`
 	WorkerID        string    `json:"worker_id"`
 	StartedAt       time.Time `json:"started_at"`
 	CompletedAt     time.Time `json:"completed_at"`
 	Status          string    `json:"status"`
 	Error           string    `json:"error,omitempty"`
-	ExecutionTimeMs string    `json:"execution_time_ms"`
+	ExecutionTimeMs int64     `json:"execution_time_ms"`
+}
+
+type TimelineEventType string
+
+const (
+	TimelineCreated         TimelineEventType = "created"
+	TimelineScheduled       TimelineEventType = "scheduled"
+	TimelineAttemptStarted  TimelineEventType = "attempt_started"
+	TimelineAttemptFinished TimelineEventType = "attempt_finished"
+	TimelineRetry           TimelineEventType = "retry"
+	TimelineRunning         TimelineEventType = "running"
+	TimelineCompleted       TimelineEventType = "completed"
+	TimelineFailed          TimelineEventType = "failed"
+	TimelineCancelled       TimelineEventType = "cancelled"
+)
+
+// TimelineEvent is one step in a task's life, from creation through each
+// attempt to its final state.
+type TimelineEvent struct {
+	Type       TimelineEventType `json:"type"`
+	At         time.Time         `json:"at"`
+	Attempt    int               `json:"attempt,omitempty"`
+	WorkerID   string            `json:"worker_id,omitempty"`
+	Status     string            `json:"status,omitempty"`
+	DurationMs int64             `json:"duration_ms,omitempty"`
+	Error      string            `json:"error,omitempty"`
 }
 
 func NewTask(name, taskType string, payload map[string]interface{}) *Task {
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type TaskLogLine struct {
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
+
+// TaskLog holds what an executor logged during one attempt of a task. Dropped
+// counts the lines past the per-attempt limit that were not kept, and Done is
+// false while the attempt is still running.
+type TaskLog struct {
+	TaskID  string        `json:"task_id"`
+	Attempt int           `json:"attempt"`
+	Lines   []TaskLogLine `json:"lines"`
+	Dropped int           `json:"dropped,omitempty"`
+	Done    bool          `json:"done"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+const (
+	DefaultTenantID = "default"
+
+	// SystemTenantID owns tasks the scheduler creates on its own, such as
+	// cron jobs.
+	SystemTenantID = "system"
+)
+
+// Tenant holds the tenant's own quota overrides. A nil limit falls back to
+// the server-wide default.
+type Tenant struct {
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	MaxPending       *int      `json:"max_pending,omitempty"`
+	EnqueuePerMinute *int      `json:"enqueue_per_minute,omitempty"`
+	MaxRunning       *int      `json:"max_running,omitempty"`
+	Weight           *int      `json:"weight,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// TenantQuota is the set of limits in force for a tenant. A zero limit means
+// no limit. Weight is the tenant's share of dequeues relative to other tenants
+// waiting at the same priority.
+type TenantQuota struct {
+	MaxPending       int `json:"max_pending"`
+	EnqueuePerMinute int `json:"enqueue_per_minute"`
+	MaxRunning       int `json:"max_running"`
+	Weight           int `json:"weight"`
+}
+
+// Quota applies the tenant's overrides to the defaults.
+func (t *Tenant) Quota(defaults TenantQuota) TenantQuota {
+	quota := defaults
+	if t.MaxPending != nil {
+		quota.MaxPending = *t.MaxPending
+	}
+	if t.EnqueuePerMinute != nil {
+		quota.EnqueuePerMinute = *t.EnqueuePerMinute
+	}
+	if t.MaxRunning != nil {
+		quota.MaxRunning = *t.MaxRunning
+	}
+	if t.Weight != nil {
+		quota.Weight = *t.Weight
+	}
+	if quota.Weight < 1 {
+		quota.Weight = 1
+	}
+	return quota
+}
+
+// TenantOf returns the tenant a task belongs to. Tasks queued before tenants
+// existed carry none and belong to the default tenant.
+func TenantOf(task *Task) string {
+	if task.TenantID == "" {
+		return DefaultTenantID
+	}
+	return task.TenantID
+}
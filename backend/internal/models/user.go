@@ -10,6 +10,8 @@ type User struct {
 	ID           int64     `json:"id"`
 	Email        string    `json:"email"`
 	PasswordHash string    `json:"-"`
+	Roles        []string  `json:"roles,omitempty"`
+	TenantID     string    `json:"tenant_id"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
@@ -1,18 +1,59 @@
 package models
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 type Worker struct {
-	ID       string
-	Status   WorkerStatus
-	LastSeen time.Time
-	TasksRun int
+	ID                 string
+	Status             WorkerStatus
+	LastSeen           time.Time
+	TasksRun           int
+	Labels             map[string]string
+	TaskTypes          []string
+	MaxConcurrentTasks int
+	CurrentTasks       int
+	TypeLimits         map[string]int
+	RunningByType      map[string]int
 }
 
 type WorkerStatus string
 
 const (
-	WorkerStatusIdle    WorkerStatus = "idle"
-	WorkerStatusRunning WorkerStatus = "running"
-	WorkerStatusStopped WorkerStatus = "stopped"
+	WorkerStatusIdle     WorkerStatus = "idle"
+	WorkerStatusRunning  WorkerStatus = "running"
+	WorkerStatusPaused   WorkerStatus = "paused"
+	WorkerStatusDraining WorkerStatus = "draining"
+	WorkerStatusStopped  WorkerStatus = "stopped"
 )
+
+// CanRun reports whether the worker supports the task's type and carries every
+// label in the task's selector. An empty TaskTypes list accepts any type.
+func (w *Worker) CanRun(task *Task) bool {
+	if len(w.TaskTypes) > 0 && !slices.Contains(w.TaskTypes, task.Type) {
+		return false
+	}
+
+	for key, value := range task.Selector {
+		if label, ok := w.Labels[key]; !ok || label != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// HasCapacityFor reports whether the worker has a free slot for the task,
+// taking both the overall and the per-type concurrency limits into account.
+func (w *Worker) HasCapacityFor(task *Task) bool {
+	if w.MaxConcurrentTasks > 0 && w.CurrentTasks >= w.MaxConcurrentTasks {
+		return false
+	}
+
+	if limit, ok := w.TypeLimits[task.Type]; ok && w.RunningByType[task.Type] >= limit {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+type EnrollmentToken struct {
+	ID        string     `json:"id"`
+	TokenHash string     `json:"-"`
+	WorkerID  string     `json:"worker_id,omitempty"`
+	CreatedBy string     `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	UsedBy    string     `json:"used_by,omitempty"`
+}
+
+type WorkerCredential struct {
+	ID         string     `json:"id"`
+	WorkerID   string     `json:"worker_id"`
+	SecretHash string     `json:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+func NewEnrollmentToken(tokenHash, workerID string, ttl time.Duration) *EnrollmentToken {
+	now := time.Now()
+
+	return &EnrollmentToken{
+		ID:        generateID(),
+		TokenHash: tokenHash,
+		WorkerID:  workerID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+}
@@ -5,27 +5,51 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math"
+	"slices"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/rudraprasaaad/task-scheduler/internal/events"
 	"github.com/rudraprasaaad/task-scheduler/internal/models"
 	redisClient "github.com/rudraprasaaad/task-scheduler/internal/redis"
 )
 
 const (
 	TaskQueueKey      = "task_scheduler:queue"
+	TaskReadyAtKey    = "task_scheduler:queue:ready_at"
 	TaskDataKeyPrefix = "task_scheduler:task:"
 	TaskLockKeyPrefix = "task_scheduler:lock:"
-	TaskUpdateChannel = "task_scheduler:updates"
+
+	// Every queued task is also in its tenant's queue, scored the same, and
+	// TenantSetKey lists the tenants with tasks queued. Leased tasks are kept
+	// per tenant in running sets scored by lease expiry.
+	TenantQueueKeyPrefix   = "task_scheduler:queue:tenant:"
+	TenantSetKey           = "task_scheduler:queue:tenants"
+	TenantRunningKeyPrefix = "task_scheduler:queue:running:"
+	FairShareKeyPrefix     = "task_scheduler:queue:fair_share:"
 )
 
+const taskLeaseTTL = 5 * time.Minute
+
+// TenantLimits supplies the limits Dequeue applies to each tenant: its weight
+// in the fair share and how many of its tasks may run at once.
+type TenantLimits interface {
+	Limits(ctx context.Context, tenantID string) models.TenantQuota
+}
+
 type RedisQueue struct {
 	client *redisClient.Client
+	limits TenantLimits
 }
 
-func NewRedisQueue(client *redisClient.Client) *RedisQueue {
+// NewRedisQueue returns a queue that shares dequeues fairly between tenants.
+// With nil limits every tenant has the same weight and no running limit.
+func NewRedisQueue(client *redisClient.Client, limits TenantLimits) *RedisQueue {
 	return &RedisQueue{
 		client: client,
+		limits: limits,
 	}
 }
 
@@ -38,75 +62,308 @@ func (rq *RedisQueue) Enqueue(task *models.Task) error {
 		return fmt.Errorf("failed to marshal task: %w", err)
 	}
 
+	oldStatus := rq.previousStatus(ctx, task.ID)
+
 	pipe := rq.client.TxPipeline()
 
 	taskKey := TaskDataKeyPrefix + task.ID
 	pipe.Set(ctx, taskKey, taskData, 24*time.Hour)
+	queueTask(ctx, pipe, task)
+
+	_, err = pipe.Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	rq.PublishTaskEvent(ctx, task, oldStatus)
 
+	log.Printf("Task %s enqueued with priority %d", task.ID, task.Priority)
+	return nil
+}
+
+// priorityScoreScale separates priority bands in the queue score. The age term
+// is in seconds, so it never carries a task into another band.
+const priorityScoreScale = 1e12
+
+func taskScore(task *models.Task) float64 {
 	priority := float64(task.Priority)
 	scheduledAtUnix := float64(task.ScheduledAt.Unix())
 
-	score := priority*1e12 + (float64(time.Now().UnixMilli()) - scheduledAtUnix)
+	return priority*priorityScoreScale + (float64(time.Now().Unix()) - scheduledAtUnix)
+}
 
-	pipe.ZAdd(ctx, TaskQueueKey, redis.Z{
-		Score:  score,
+func readyAtEntry(task *models.Task) redis.Z {
+	return redis.Z{
+		Score:  float64(task.ScheduledAt.UnixMilli()),
 		Member: task.ID,
-	})
+	}
+}
 
-	_, err = pipe.Exec(ctx)
+// scoreBand recovers the priority a queue score was computed from.
+func scoreBand(score float64) int {
+	return int(math.Round(score / priorityScoreScale))
+}
+
+// queueTask adds the commands that put a task in the global and tenant queues.
+// A task being queued is no longer running, so its running lease goes too.
+func queueTask(ctx context.Context, pipe redis.Pipeliner, task *models.Task) {
+	tenantID := models.TenantOf(task)
+	entry := redis.Z{
+		Score:  taskScore(task),
+		Member: task.ID,
+	}
+
+	pipe.ZAdd(ctx, TaskQueueKey, entry)
+	pipe.ZAdd(ctx, TaskReadyAtKey, readyAtEntry(task))
+	pipe.ZAdd(ctx, TenantQueueKeyPrefix+tenantID, entry)
+	pipe.SAdd(ctx, TenantSetKey, tenantID)
+	pipe.ZRem(ctx, TenantRunningKeyPrefix+tenantID, task.ID)
+}
+
+// Requeue puts a task that was claimed but not finished back on the queue and
+// releases its lease so another worker can pick it up straight away.
+func (rq *RedisQueue) Requeue(task *models.Task) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	taskData, err := json.Marshal(task)
 	if err != nil {
-		return fmt.Errorf("failed to enqueue task: %w", err)
+		return fmt.Errorf("failed to marshal task: %w", err)
 	}
 
-	rq.publishTaskUpdate(ctx, task.ID, "created")
+	oldStatus := rq.previousStatus(ctx, task.ID)
+
+	pipe := rq.client.TxPipeline()
+	pipe.Set(ctx, TaskDataKeyPrefix+task.ID, taskData, 24*time.Hour)
+	queueTask(ctx, pipe, task)
+	pipe.Del(ctx, TaskLockKeyPrefix+task.ID)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to requeue task: %w", err)
+	}
+
+	rq.PublishTaskEvent(ctx, task, oldStatus)
 
-	log.Printf("Task %s enqueued with priority %d", task.ID, task.Priority)
 	return nil
 }
 
-func (rq *RedisQueue) Dequeue(limit int) ([]*models.Task, error) {
+// dequeueScanWindow bounds how many of each tenant's highest scored tasks a
+// worker looks at per dequeue, so tasks it cannot run do not starve it of ones
+// it can.
+const dequeueScanWindow = 50
+
+// fairShareCursorField holds the tenant served last in a band's fair share
+// state. Tenant IDs cannot contain a colon, so it never clashes with one.
+const fairShareCursorField = ":cursor"
+
+// pruneTenantScript drops a tenant from the queued tenants once its queue is
+// empty, atomically so a task enqueued meanwhile keeps it listed.
+var pruneTenantScript = redis.NewScript(`
+if redis.call('ZCARD', KEYS[1]) == 0 then
+	redis.call('SREM', KEYS[2], ARGV[1])
+end
+return 0
+`)
+
+// Dequeue leases up to limit tasks the worker can run. Higher priorities are
+// served first. Within a priority, tenants share dequeues by deficit
+// round-robin in proportion to their weight, skipping tenants at their
+// running limit, so a tenant with a deep backlog cannot starve the others.
+// Each task leased counts against the worker's slots, so pass a copy of the
+// worker rather than one its owner keeps counting on.
+func (rq *RedisQueue) Dequeue(worker *models.Worker, limit int) ([]*models.Task, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	result, err := rq.client.ZRevRangeByScore(ctx, TaskQueueKey, &redis.ZRangeBy{
-		Min:   "-inf",
-		Max:   "+inf",
-		Count: int64(limit),
-	}).Result()
+	window := limit
+	if window < dequeueScanWindow {
+		window = dequeueScanWindow
+	}
 
+	tenants, err := rq.client.SMembers(ctx, TenantSetKey).Result()
 	if err != nil {
+		return nil, fmt.Errorf("failed to get queued tenants: %w", err)
+	}
+
+	pipe := rq.client.Pipeline()
+	heads := make([]*redis.ZSliceCmd, len(tenants))
+	for i, tenantID := range tenants {
+		heads[i] = pipe.ZRevRangeWithScores(ctx, TenantQueueKeyPrefix+tenantID, 0, int64(window-1))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
 		return nil, fmt.Errorf("failed to get tasks from queue:%w", err)
 	}
 
-	if len(result) == 0 {
-		return []*models.Task{}, nil
+	// Group the candidates by priority band, then by tenant, keeping each
+	// tenant's tasks in queue order.
+	bands := make(map[int]map[string][]string)
+	for i, tenantID := range tenants {
+		entries := heads[i].Val()
+		if len(entries) == 0 {
+			pruneTenantScript.Run(ctx, rq.client, []string{TenantQueueKeyPrefix + tenantID, TenantSetKey}, tenantID)
+			continue
+		}
+
+		for _, entry := range entries {
+			band := scoreBand(entry.Score)
+			if bands[band] == nil {
+				bands[band] = make(map[string][]string)
+			}
+			bands[band][tenantID] = append(bands[band][tenantID], entry.Member.(string))
+		}
 	}
 
-	var tasks []*models.Task
+	order := make([]int, 0, len(bands))
+	for band := range bands {
+		order = append(order, band)
+	}
+	slices.Sort(order)
+	slices.Reverse(order)
 
-	for _, taskID := range result {
-		lockKey := TaskLockKeyPrefix + taskID
-		locked, err := rq.client.SetNX(ctx, lockKey, "locked", 5*time.Minute).Result()
+	tasks := []*models.Task{}
+	for _, band := range order {
+		if len(tasks) >= limit {
+			break
+		}
+		tasks = append(tasks, rq.dequeueBand(ctx, worker, band, bands[band], limit-len(tasks))...)
+	}
 
-		if err != nil || !locked {
-			continue
+	return tasks, nil
+}
+
+// dequeueBand runs the deficit round-robin for one priority band. Each visit
+// adds the tenant's weight to its deficit and every task leased spends one.
+// Deficits and the last tenant served are kept in Redis between dequeues, so
+// the share holds across workers. Concurrent dequeues may interleave their
+// updates, which only makes the share approximate, never double-leases.
+func (rq *RedisQueue) dequeueBand(ctx context.Context, worker *models.Worker, band int, queued map[string][]string, limit int) []*models.Task {
+	stateKey := FairShareKeyPrefix + strconv.Itoa(band)
+	state, err := rq.client.HGetAll(ctx, stateKey).Result()
+	if err != nil {
+		log.Printf("Failed to read fair share state for priority %d: %v", band, err)
+	}
+
+	tenants := make([]string, 0, len(queued))
+	for tenantID := range queued {
+		tenants = append(tenants, tenantID)
+	}
+	slices.Sort(tenants)
+
+	deficits := make(map[string]int, len(tenants))
+	for _, tenantID := range tenants {
+		deficits[tenantID], _ = strconv.Atoi(state[tenantID])
+	}
+
+	// Pick up after the tenant served last: with it if it still has credit
+	// left from a dequeue that hit its limit, otherwise with the next one.
+	start, resume := 0, false
+	cursor := state[fairShareCursorField]
+	if cursor != "" {
+		i, found := slices.BinarySearch(tenants, cursor)
+		switch {
+		case found && deficits[cursor] >= 1:
+			start, resume = i, true
+		case found:
+			start = i + 1
+		default:
+			start = i
 		}
+	}
 
-		taskKey := TaskDataKeyPrefix + taskID
-		taskData, err := rq.client.Get(ctx, taskKey).Result()
+	active := make(map[string]bool, len(tenants))
+	for _, tenantID := range tenants {
+		active[tenantID] = true
+	}
+	running := make(map[string]int)
+
+	var tasks []*models.Task
+	for len(tasks) < limit && len(active) > 0 {
+		for k := 0; k < len(tenants) && len(tasks) < limit; k++ {
+			tenantID := tenants[(start+k)%len(tenants)]
+			if !active[tenantID] {
+				continue
+			}
+
+			quota := rq.tenantLimits(ctx, tenantID)
+			if resume {
+				resume = false
+			} else {
+				deficits[tenantID] += quota.Weight
+			}
+			cursor = tenantID
+
+			for deficits[tenantID] >= 1 && len(tasks) < limit {
+				if quota.MaxRunning > 0 {
+					if _, counted := running[tenantID]; !counted {
+						running[tenantID] = rq.runningCount(ctx, tenantID)
+					}
+					if running[tenantID] >= quota.MaxRunning {
+						delete(active, tenantID)
+						deficits[tenantID] = 0
+						break
+					}
+				}
+
+				ids := queued[tenantID]
+				task := rq.claimNext(ctx, worker, tenantID, &ids)
+				queued[tenantID] = ids
+				if task == nil {
+					delete(active, tenantID)
+					deficits[tenantID] = 0
+					break
+				}
+
+				tasks = append(tasks, task)
+				deficits[tenantID]--
+				running[tenantID]++
+			}
+		}
+	}
+
+	// Tenants that no longer have tasks at this priority lose their credit,
+	// as an emptied queue does in deficit round-robin.
+	pipe := rq.client.Pipeline()
+	for field := range state {
+		if _, queuedHere := queued[field]; !queuedHere && field != fairShareCursorField {
+			pipe.HDel(ctx, stateKey, field)
+		}
+	}
+	for tenantID, deficit := range deficits {
+		if deficit > 0 {
+			pipe.HSet(ctx, stateKey, tenantID, deficit)
+		} else {
+			pipe.HDel(ctx, stateKey, tenantID)
+		}
+	}
+	pipe.HSet(ctx, stateKey, fairShareCursorField, cursor)
+	pipe.Expire(ctx, stateKey, time.Hour)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("Failed to store fair share state for priority %d: %v", band, err)
+	}
+
+	return tasks
+}
+
+// claimNext leases the first of the tenant's candidate tasks the worker can
+// run, consuming the candidates it looks at.
+func (rq *RedisQueue) claimNext(ctx context.Context, worker *models.Worker, tenantID string, ids *[]string) *models.Task {
+	for len(*ids) > 0 {
+		taskID := (*ids)[0]
+		*ids = (*ids)[1:]
+
+		task, err := rq.storedTask(ctx, taskID)
 		if err != nil {
-			rq.client.Del(ctx, lockKey)
 			continue
 		}
 
-		var task models.Task
-		if err := json.Unmarshal([]byte(taskData), &task); err != nil {
-			rq.client.Del(ctx, lockKey)
+		if task.ScheduledAt.After(time.Now()) || !worker.CanRun(task) || !worker.HasCapacityFor(task) {
 			continue
 		}
 
-		if task.ScheduledAt.After(time.Now()) {
-			rq.client.Del(ctx, lockKey)
+		lockKey := TaskLockKeyPrefix + taskID
+		locked, err := rq.client.SetNX(ctx, lockKey, worker.ID, taskLeaseTTL).Result()
+
+		if err != nil || !locked {
 			continue
 		}
 
@@ -114,11 +371,108 @@ func (rq *RedisQueue) Dequeue(limit int) ([]*models.Task, error) {
 			rq.client.Del(ctx, lockKey)
 			continue
 		}
-		tasks = append(tasks, &task)
-		log.Printf("Task %s dequeueud by worker", taskID)
+
+		pipe := rq.client.Pipeline()
+		pipe.ZRem(ctx, TaskReadyAtKey, taskID)
+		pipe.ZRem(ctx, TenantQueueKeyPrefix+tenantID, taskID)
+		pipe.ZAdd(ctx, TenantRunningKeyPrefix+tenantID, redis.Z{
+			Score:  float64(time.Now().Add(taskLeaseTTL).UnixMilli()),
+			Member: taskID,
+		})
+		if _, err := pipe.Exec(ctx); err != nil {
+			log.Printf("Failed to record lease of task %s: %v", taskID, err)
+		}
+
+		worker.CurrentTasks++
+		if worker.RunningByType == nil {
+			worker.RunningByType = make(map[string]int)
+		}
+		worker.RunningByType[task.Type]++
+
+		log.Printf("Task %s dequeueud by worker %s", taskID, worker.ID)
+		return task
 	}
 
-	return tasks, nil
+	return nil
+}
+
+func (rq *RedisQueue) tenantLimits(ctx context.Context, tenantID string) models.TenantQuota {
+	if rq.limits == nil {
+		return models.TenantQuota{Weight: 1}
+	}
+
+	quota := rq.limits.Limits(ctx, tenantID)
+	if quota.Weight < 1 {
+		quota.Weight = 1
+	}
+	return quota
+}
+
+// runningCount counts the tenant's tasks whose lease has not expired, dropping
+// the expired ones. Leases outlive tasks that end without reporting back only
+// until they expire.
+func (rq *RedisQueue) runningCount(ctx context.Context, tenantID string) int {
+	count, err := rq.RunningCount(ctx, tenantID)
+	if err != nil {
+		log.Printf("Failed to count running tasks of tenant %s: %v", tenantID, err)
+	}
+	return count
+}
+
+// RunningCount returns how many of the tenant's tasks are leased to workers.
+func (rq *RedisQueue) RunningCount(ctx context.Context, tenantID string) (int, error) {
+	key := TenantRunningKeyPrefix + tenantID
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+
+	pipe := rq.client.Pipeline()
+	pipe.ZRemRangeByScore(ctx, key, "-inf", "("+now)
+	count := pipe.ZCard(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to count running tasks: %w", err)
+	}
+
+	return int(count.Val()), nil
+}
+
+// PendingCount returns how many of the tenant's tasks are waiting in the
+// queue, including those scheduled for later.
+func (rq *RedisQueue) PendingCount(ctx context.Context, tenantID string) (int, error) {
+	count, err := rq.client.ZCard(ctx, TenantQueueKeyPrefix+tenantID).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count pending tasks: %w", err)
+	}
+
+	return int(count), nil
+}
+
+// RebuildTenantQueues adds every queued task to its tenant's queue. Tasks
+// queued before tenant queues existed are only in the global queue and would
+// never be dequeued otherwise.
+func (rq *RedisQueue) RebuildTenantQueues(ctx context.Context) (int, error) {
+	entries, err := rq.client.ZRangeWithScores(ctx, TaskQueueKey, 0, -1).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read queue: %w", err)
+	}
+
+	pipe := rq.client.Pipeline()
+	for _, entry := range entries {
+		taskID := entry.Member.(string)
+		tenantID := models.DefaultTenantID
+		if task, err := rq.storedTask(ctx, taskID); err == nil {
+			tenantID = models.TenantOf(task)
+		}
+
+		pipe.ZAdd(ctx, TenantQueueKeyPrefix+tenantID, redis.Z{Score: entry.Score, Member: taskID})
+		pipe.SAdd(ctx, TenantSetKey, tenantID)
+	}
+
+	if len(entries) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return 0, fmt.Errorf("failed to rebuild tenant queues: %w", err)
+		}
+	}
+
+	return len(entries), nil
 }
 
 func (rq *RedisQueue) UpdateTask(task *models.Task) error {
@@ -130,6 +484,8 @@ func (rq *RedisQueue) UpdateTask(task *models.Task) error {
 		return fmt.Errorf("failed to marshal task: %w", err)
 	}
 
+	oldStatus := rq.previousStatus(ctx, task.ID)
+
 	taskKey := TaskDataKeyPrefix + task.ID
 	err = rq.client.Set(ctx, taskKey, taskData, 24*time.Hour).Err()
 
@@ -138,32 +494,64 @@ func (rq *RedisQueue) UpdateTask(task *models.Task) error {
 	}
 
 	if task.Status == models.TaskStatusPending && task.Retries > 0 {
-		priority := float64(task.Priority)
-		scheduledAtUnix := float64(task.ScheduledAt.Unix())
-		score := priority*1000000 + scheduledAtUnix
-
-		err = rq.client.ZAdd(ctx, TaskQueueKey, redis.Z{
-			Score:  score,
-			Member: task.ID,
-		}).Err()
+		rq.client.Del(ctx, TaskLockKeyPrefix+task.ID)
 
-		if err != nil {
+		pipe := rq.client.TxPipeline()
+		queueTask(ctx, pipe, task)
+		if _, err := pipe.Exec(ctx); err != nil {
 			return fmt.Errorf("failed to re-enqueue task:%w", err)
 		}
 
 		log.Printf("Task %s re-enqueued for retry %d%d", task.ID, task.Retries, task.MaxRetries)
 	}
 
-	if task.Status == models.TaskStatusCompleted || task.Status == models.TaskStatusFailed {
+	if task.Status == models.TaskStatusCompleted || task.Status == models.TaskStatusFailed || task.Status == models.TaskStatusCancelled {
 		lockKey := TaskLockKeyPrefix + task.ID
 		rq.client.Del(ctx, lockKey)
+		rq.client.ZRem(ctx, TenantRunningKeyPrefix+models.TenantOf(task), task.ID)
 	}
 
-	rq.publishTaskUpdate(ctx, task.ID, string(task.Status))
+	rq.PublishTaskEvent(ctx, task, oldStatus)
 
 	return nil
 }
 
+// rescoreScript moves a queued task to a new score only while it is still
+// waiting in the queue and unleased, so it cannot race a worker's dequeue.
+var rescoreScript = redis.NewScript(`
+if redis.call('ZSCORE', KEYS[1], ARGV[1]) == false or redis.call('EXISTS', KEYS[4]) == 1 then
+	return 0
+end
+redis.call('ZADD', KEYS[1], ARGV[2], ARGV[1])
+redis.call('ZADD', KEYS[2], ARGV[3], ARGV[1])
+redis.call('SET', KEYS[3], ARGV[4], 'KEEPTTL')
+redis.call('ZADD', KEYS[5], ARGV[2], ARGV[1])
+return 1
+`)
+
+// Rescore stores a pending task's new priority or schedule and moves it in the
+// queue. It reports false if the task is no longer queued, e.g. because a
+// worker claimed it in the meantime.
+func (rq *RedisQueue) Rescore(task *models.Task) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	taskData, err := json.Marshal(task)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal task: %w", err)
+	}
+
+	keys := []string{TaskQueueKey, TaskReadyAtKey, TaskDataKeyPrefix + task.ID, TaskLockKeyPrefix + task.ID, TenantQueueKeyPrefix + models.TenantOf(task)}
+	readyAt := readyAtEntry(task)
+
+	updated, err := rescoreScript.Run(ctx, rq.client, keys, task.ID, taskScore(task), readyAt.Score, taskData).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to rescore task: %w", err)
+	}
+
+	return updated == 1, nil
+}
+
 func (rq *RedisQueue) Size() (int, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
@@ -176,22 +564,97 @@ func (rq *RedisQueue) Size() (int, error) {
 	return int(count), nil
 }
 
-func (rq *RedisQueue) publishTaskUpdate(ctx context.Context, taskID, _ string) {
-	message := map[string]interface{}{
-		"task_id":   taskID,
-		"timestamp": time.Now().Unix(),
+// SizeByPriority counts queued tasks per priority, recovered from their scores.
+func (rq *RedisQueue) SizeByPriority() (map[int]int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	entries, err := rq.client.ZRangeWithScores(ctx, TaskQueueKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read queue scores: %w", err)
 	}
 
-	messageJSON, err := json.Marshal(message)
+	sizes := make(map[int]int)
+	for _, entry := range entries {
+		sizes[scoreBand(entry.Score)]++
+	}
+
+	return sizes, nil
+}
+
+// OldestReadyAge returns how long the longest-waiting task that is already due
+// has been sitting in the queue, or zero if nothing is ready.
+func (rq *RedisQueue) OldestReadyAge() (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	oldest, err := rq.client.ZRangeByScoreWithScores(ctx, TaskReadyAtKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.UnixMilli(), 10),
+		Count: 1,
+	}).Result()
 	if err != nil {
-		log.Printf("Failed to marshal task update message: %v", err)
-		return
+		return 0, fmt.Errorf("failed to get oldest ready task: %w", err)
 	}
 
-	err = rq.client.Publish(ctx, TaskUpdateChannel, messageJSON).Err()
+	if len(oldest) == 0 {
+		return 0, nil
+	}
 
+	return now.Sub(time.UnixMilli(int64(oldest[0].Score))), nil
+}
+
+// previousStatus reads the status of the copy of the task held in Redis before
+// it is overwritten, so events can report the transition. It is empty for
+// tasks the queue has not seen.
+func (rq *RedisQueue) previousStatus(ctx context.Context, taskID string) models.TaskStatus {
+	task, err := rq.storedTask(ctx, taskID)
+	if err != nil {
+		return ""
+	}
+
+	return task.Status
+}
+
+// LeaseHolder returns the ID of the worker whose lease on the task is still
+// live, or "" if no worker holds one.
+func (rq *RedisQueue) LeaseHolder(ctx context.Context, taskID string) (string, error) {
+	holder, err := rq.client.Get(ctx, TaskLockKeyPrefix+taskID).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read task lease: %w", err)
+	}
+
+	return holder, nil
+}
+
+// GetTask returns the copy of a task held in Redis, which is the only copy of
+// tasks enqueued without a database row.
+func (rq *RedisQueue) GetTask(ctx context.Context, taskID string) (*models.Task, error) {
+	return rq.storedTask(ctx, taskID)
+}
+
+// storedTask reads the copy of a task held in Redis.
+func (rq *RedisQueue) storedTask(ctx context.Context, taskID string) (*models.Task, error) {
+	taskData, err := rq.client.Get(ctx, TaskDataKeyPrefix+taskID).Result()
 	if err != nil {
-		log.Printf("Failed to publish task update %v", err)
+		return nil, err
+	}
+
+	var task models.Task
+	if err := json.Unmarshal([]byte(taskData), &task); err != nil {
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+func (rq *RedisQueue) PublishTaskEvent(ctx context.Context, task *models.Task, oldStatus models.TaskStatus) {
+	if err := events.Publish(ctx, rq.client, models.NewTaskEvent(task, oldStatus)); err != nil {
+		log.Printf("Failed to publish task event for task %s: %v", task.ID, err)
 	}
 }
 
@@ -226,10 +689,18 @@ func (rq *RedisQueue) CleanupExpiredTasks(ctx context.Context) error {
 func (rq *RedisQueue) Remove(taskID string) error {
 	ctx := context.Background()
 
+	tenantID := models.DefaultTenantID
+	if task, err := rq.storedTask(ctx, taskID); err == nil {
+		tenantID = models.TenantOf(task)
+	}
+
 	_, err := rq.client.ZRem(ctx, TaskQueueKey, taskID).Result()
 	if err != nil {
 		return fmt.Errorf("failed to remove task %s from sorted set queue: %w", taskID, err)
 	}
+	rq.client.ZRem(ctx, TaskReadyAtKey, taskID)
+	rq.client.ZRem(ctx, TenantQueueKeyPrefix+tenantID, taskID)
+	rq.client.ZRem(ctx, TenantRunningKeyPrefix+tenantID, taskID)
 
 	taskKey := TaskDataKeyPrefix + taskID
 	lockKey := TaskLockKeyPrefix + taskID
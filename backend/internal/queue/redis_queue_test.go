@@ -0,0 +1,144 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+	redisClient "github.com/rudraprasaaad/task-scheduler/internal/redis"
+)
+
+type fixedLimits map[string]models.TenantQuota
+
+func (l fixedLimits) Limits(_ context.Context, tenantID string) models.TenantQuota {
+	return l[tenantID]
+}
+
+func newTestQueue(t *testing.T, limits TenantLimits) *RedisQueue {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return NewRedisQueue(&redisClient.Client{Client: client}, limits)
+}
+
+func enqueueTasks(t *testing.T, rq *RedisQueue, tenantID string, priority models.TaskPriority, count int) {
+	t.Helper()
+
+	for i := 0; i < count; i++ {
+		task := models.NewTask(fmt.Sprintf("%s-%d", tenantID, i), "test", nil)
+		task.TenantID = tenantID
+		task.Priority = priority
+		task.ScheduledAt = time.Now().Add(-time.Minute)
+		if err := rq.Enqueue(task); err != nil {
+			t.Fatalf("failed to enqueue task: %v", err)
+		}
+	}
+}
+
+func dequeueByTenant(t *testing.T, rq *RedisQueue, limit int) map[string]int {
+	t.Helper()
+
+	tasks, err := rq.Dequeue(&models.Worker{ID: "worker-1"}, limit)
+	if err != nil {
+		t.Fatalf("failed to dequeue: %v", err)
+	}
+
+	counts := make(map[string]int)
+	for _, task := range tasks {
+		counts[task.TenantID]++
+	}
+	return counts
+}
+
+func TestDequeueSharesByWeight(t *testing.T) {
+	rq := newTestQueue(t, fixedLimits{
+		"heavy": {Weight: 3},
+		"light": {Weight: 1},
+	})
+	enqueueTasks(t, rq, "heavy", models.PriorityMedium, 20)
+	enqueueTasks(t, rq, "light", models.PriorityMedium, 20)
+
+	counts := dequeueByTenant(t, rq, 8)
+
+	if counts["heavy"] != 6 || counts["light"] != 2 {
+		t.Errorf("got %v, want 6 heavy and 2 light", counts)
+	}
+}
+
+func TestDequeueShareCarriesAcrossDequeues(t *testing.T) {
+	rq := newTestQueue(t, nil)
+	enqueueTasks(t, rq, "a", models.PriorityMedium, 5)
+	enqueueTasks(t, rq, "b", models.PriorityMedium, 5)
+
+	var served []string
+	for i := 0; i < 4; i++ {
+		for tenantID := range dequeueByTenant(t, rq, 1) {
+			served = append(served, tenantID)
+		}
+	}
+
+	want := []string{"a", "b", "a", "b"}
+	if fmt.Sprint(served) != fmt.Sprint(want) {
+		t.Errorf("got tenants served in order %v, want %v", served, want)
+	}
+}
+
+func TestDequeueSkipsTenantsAtRunningLimit(t *testing.T) {
+	rq := newTestQueue(t, fixedLimits{
+		"capped": {Weight: 1, MaxRunning: 1},
+		"open":   {Weight: 1},
+	})
+	enqueueTasks(t, rq, "capped", models.PriorityMedium, 5)
+	enqueueTasks(t, rq, "open", models.PriorityMedium, 5)
+
+	counts := dequeueByTenant(t, rq, 4)
+	if counts["capped"] != 1 || counts["open"] != 3 {
+		t.Errorf("got %v, want 1 capped and 3 open", counts)
+	}
+
+	counts = dequeueByTenant(t, rq, 2)
+	if counts["capped"] != 0 || counts["open"] != 2 {
+		t.Errorf("got %v on the second dequeue, want the capped tenant skipped", counts)
+	}
+}
+
+func TestDequeueServesHigherPriorityFirst(t *testing.T) {
+	rq := newTestQueue(t, nil)
+	enqueueTasks(t, rq, "a", models.PriorityLow, 3)
+	enqueueTasks(t, rq, "b", models.PriorityHigh, 2)
+
+	counts := dequeueByTenant(t, rq, 3)
+	if counts["b"] != 2 || counts["a"] != 1 {
+		t.Errorf("got %v, want both high priority tasks and one low", counts)
+	}
+}
+
+func TestDequeueCountsLeasedTasksAgainstWorkerSlots(t *testing.T) {
+	rq := newTestQueue(t, nil)
+	enqueueTasks(t, rq, "a", models.PriorityMedium, 5)
+
+	worker := &models.Worker{ID: "worker-1", MaxConcurrentTasks: 3, CurrentTasks: 1}
+	tasks, err := rq.Dequeue(worker, 5)
+	if err != nil {
+		t.Fatalf("failed to dequeue: %v", err)
+	}
+	if len(tasks) != 2 || worker.CurrentTasks != 3 {
+		t.Errorf("got %d tasks and %d current, want the 2 free slots filled", len(tasks), worker.CurrentTasks)
+	}
+
+	worker = &models.Worker{ID: "worker-2", TypeLimits: map[string]int{"test": 1}}
+	tasks, err = rq.Dequeue(worker, 3)
+	if err != nil {
+		t.Fatalf("failed to dequeue: %v", err)
+	}
+	if len(tasks) != 1 || worker.RunningByType["test"] != 1 {
+		t.Errorf("got %d tasks and %v running, want one task of the limited type", len(tasks), worker.RunningByType)
+	}
+}
@@ -0,0 +1,157 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rudraprasaaad/task-scheduler/internal/config"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+	redisClient "github.com/rudraprasaaad/task-scheduler/internal/redis"
+	"github.com/rudraprasaaad/task-scheduler/internal/repository"
+)
+
+const enqueueRateKeyPrefix = "task_scheduler:quota:enqueued:"
+
+// PendingRetryAfter is the retry hint for a tenant at its pending limit. How
+// soon room frees up depends on the workers, so it is only a suggestion.
+const PendingRetryAfter = 30 * time.Second
+
+var ErrExceeded = errors.New("tenant quota exceeded")
+
+// ExceededError reports the limit a tenant hit and how long to wait before
+// trying again.
+type ExceededError struct {
+	TenantID   string
+	Limit      string
+	Max        int
+	RetryAfter time.Duration
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("tenant %s has reached its %s limit of %d, retry in %s", e.TenantID, e.Limit, e.Max, e.RetryAfter)
+}
+
+func (e *ExceededError) Is(target error) bool {
+	return target == ErrExceeded
+}
+
+type cachedQuota struct {
+	quota   models.TenantQuota
+	expires time.Time
+}
+
+// Manager resolves each tenant's limits, caching them briefly, and counts
+// enqueues against the per-minute limit.
+type Manager struct {
+	client   *redisClient.Client
+	tenants  *repository.TenantRepository
+	defaults models.TenantQuota
+	ttl      time.Duration
+
+	mutex  sync.Mutex
+	cached map[string]cachedQuota
+}
+
+func NewManager(client *redisClient.Client, tenants *repository.TenantRepository, cfg config.QuotaConfig) *Manager {
+	return &Manager{
+		client:  client,
+		tenants: tenants,
+		defaults: models.TenantQuota{
+			MaxPending:       cfg.MaxPending,
+			EnqueuePerMinute: cfg.EnqueuePerMinute,
+			MaxRunning:       cfg.MaxRunning,
+			Weight:           max(cfg.Weight, 1),
+		},
+		ttl:    cfg.CacheTTL,
+		cached: make(map[string]cachedQuota),
+	}
+}
+
+func (m *Manager) Defaults() models.TenantQuota {
+	return m.defaults
+}
+
+// Limits returns the limits in force for a tenant. If they cannot be loaded
+// the defaults apply, so a database hiccup does not stop the queue.
+func (m *Manager) Limits(ctx context.Context, tenantID string) models.TenantQuota {
+	m.mutex.Lock()
+	cached, ok := m.cached[tenantID]
+	m.mutex.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.quota
+	}
+
+	quota := m.defaults
+	tenant, err := m.tenants.Get(ctx, tenantID)
+	switch {
+	case err == nil:
+		quota = tenant.Quota(m.defaults)
+	case !errors.Is(err, repository.ErrTenantNotFound):
+		log.Printf("Failed to load quota of tenant %s, using defaults: %v", tenantID, err)
+		return quota
+	}
+
+	m.mutex.Lock()
+	m.cached[tenantID] = cachedQuota{quota: quota, expires: time.Now().Add(m.ttl)}
+	m.mutex.Unlock()
+
+	return quota
+}
+
+// Invalidate drops the cached limits of a tenant after they change. Other
+// replicas pick the change up when their cache expires.
+func (m *Manager) Invalidate(tenantID string) {
+	m.mutex.Lock()
+	delete(m.cached, tenantID)
+	m.mutex.Unlock()
+}
+
+// TakeEnqueue counts one enqueue against the tenant's per-minute limit,
+// failing with an ExceededError once the current minute is used up.
+func (m *Manager) TakeEnqueue(ctx context.Context, tenantID string, quota models.TenantQuota) error {
+	if quota.EnqueuePerMinute <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	key := enqueueRateKey(tenantID, now)
+
+	pipe := m.client.TxPipeline()
+	count := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, 2*time.Minute)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to count enqueue: %w", err)
+	}
+
+	if int(count.Val()) > quota.EnqueuePerMinute {
+		return &ExceededError{
+			TenantID:   tenantID,
+			Limit:      "enqueue_per_minute",
+			Max:        quota.EnqueuePerMinute,
+			RetryAfter: now.Truncate(time.Minute).Add(time.Minute).Sub(now),
+		}
+	}
+
+	return nil
+}
+
+// EnqueuedThisMinute returns how many enqueues the tenant has made in the
+// current minute, rejected ones included.
+func (m *Manager) EnqueuedThisMinute(ctx context.Context, tenantID string) (int, error) {
+	count, err := m.client.Get(ctx, enqueueRateKey(tenantID, time.Now())).Int()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, fmt.Errorf("failed to read enqueue count: %w", err)
+	}
+
+	return count, nil
+}
+
+func enqueueRateKey(tenantID string, now time.Time) string {
+	return enqueueRateKeyPrefix + tenantID + ":" + strconv.FormatInt(now.Unix()/60, 10)
+}
@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/rudraprasaaad/task-scheduler/internal/database"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+)
+
+var (
+	ErrServiceAccountNotFound = errors.New("service account not found")
+	ErrServiceAccountExists   = errors.New("service account already exists")
+	ErrAPIKeyNotFound         = errors.New("API key not found")
+)
+
+type APIKeyRepository struct {
+	db *database.DB
+}
+
+func NewAPIKeyRepository(db *database.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+func (r *APIKeyRepository) CreateServiceAccount(ctx context.Context, account *models.ServiceAccount) error {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM tenants WHERE id = $1)`, account.TenantID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to look up tenant: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrTenantNotFound, account.TenantID)
+	}
+
+	query := `
+    INSERT INTO service_accounts (id, name, description, tenant_id, created_by, created_at)
+    VALUES ($1, $2, NULLIF($3, ''), $4, NULLIF($5, ''), $6)
+    ON CONFLICT (tenant_id, name) DO NOTHING`
+
+	result, err := r.db.ExecContext(ctx, query, account.ID, account.Name, account.Description, account.TenantID, account.CreatedBy, account.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create service account: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: %s", ErrServiceAccountExists, account.Name)
+	}
+
+	return nil
+}
+
+const serviceAccountColumns = `id, name, description, tenant_id, created_by, created_at`
+
+func scanServiceAccount(row rowScanner) (*models.ServiceAccount, error) {
+	var account models.ServiceAccount
+	var description, createdBy sql.NullString
+
+	if err := row.Scan(&account.ID, &account.Name, &description, &account.TenantID, &createdBy, &account.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	account.Description = description.String
+	account.CreatedBy = createdBy.String
+
+	return &account, nil
+}
+
+// GetServiceAccount looks the account up by ID or, within the tenant, by name.
+// An empty tenantID searches every tenant.
+func (r *APIKeyRepository) GetServiceAccount(ctx context.Context, idOrName, tenantID string) (*models.ServiceAccount, error) {
+	query := `
+    SELECT ` + serviceAccountColumns + `
+    FROM service_accounts
+    WHERE (id::text = $1 OR name = $1) AND ($2 = '' OR tenant_id = $2)
+    ORDER BY id::text = $1 DESC
+    LIMIT 1`
+
+	account, err := scanServiceAccount(r.db.QueryRowContext(ctx, query, idOrName, tenantID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", ErrServiceAccountNotFound, idOrName)
+		}
+		return nil, fmt.Errorf("failed to get service account: %w", err)
+	}
+
+	return account, nil
+}
+
+func (r *APIKeyRepository) ListServiceAccounts(ctx context.Context, tenantID string) ([]models.ServiceAccount, error) {
+	query := `
+    SELECT ` + serviceAccountColumns + `
+    FROM service_accounts
+    WHERE $1 = '' OR tenant_id = $1
+    ORDER BY tenant_id, name`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service accounts: %w", err)
+	}
+	defer rows.Close()
+
+	accounts := []models.ServiceAccount{}
+	for rows.Next() {
+		account, err := scanServiceAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan service account: %w", err)
+		}
+		accounts = append(accounts, *account)
+	}
+
+	return accounts, rows.Err()
+}
+
+func (r *APIKeyRepository) CreateKey(ctx context.Context, key *models.APIKey) error {
+	query := `
+    INSERT INTO api_keys (id, service_account_id, name, secret_hash, scopes, created_by, created_at, expires_at)
+    VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8)`
+
+	_, err := r.db.ExecContext(ctx, query, key.ID, key.ServiceAccountID, key.Name, key.SecretHash, pq.Array(key.Scopes), key.CreatedBy, key.CreatedAt, key.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	return nil
+}
+
+const apiKeyColumns = `k.id, k.service_account_id, sa.name, sa.tenant_id, k.name, k.secret_hash, k.scopes, k.created_by, k.created_at, k.expires_at, k.last_used_at, k.revoked_at`
+
+func scanAPIKey(row rowScanner) (*models.APIKey, error) {
+	var key models.APIKey
+	var createdBy sql.NullString
+	var expiresAt, lastUsedAt, revokedAt sql.NullTime
+
+	err := row.Scan(&key.ID, &key.ServiceAccountID, &key.ServiceAccountName, &key.TenantID, &key.Name, &key.SecretHash,
+		pq.Array(&key.Scopes), &createdBy, &key.CreatedAt, &expiresAt, &lastUsedAt, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	key.CreatedBy = createdBy.String
+	if expiresAt.Valid {
+		key.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+
+	return &key, nil
+}
+
+// GetKey loads a key with its service account's name and tenant, whether or
+// not it is still active.
+func (r *APIKeyRepository) GetKey(ctx context.Context, id string) (*models.APIKey, error) {
+	query := `
+    SELECT ` + apiKeyColumns + `
+    FROM api_keys k JOIN service_accounts sa ON sa.id = k.service_account_id
+    WHERE k.id::text = $1`
+
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", ErrAPIKeyNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	return key, nil
+}
+
+// MarkUsed records that the key authenticated a request. The timestamp is
+// only written once a minute so a busy key does not update its row on every
+// call.
+func (r *APIKeyRepository) MarkUsed(ctx context.Context, id string) error {
+	query := `
+    UPDATE api_keys SET last_used_at = NOW()
+    WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')`
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to record API key use: %w", err)
+	}
+
+	return nil
+}
+
+// ListKeys returns the keys in the tenant, optionally only those of one
+// service account. An empty tenantID lists every tenant's keys.
+func (r *APIKeyRepository) ListKeys(ctx context.Context, tenantID, serviceAccountID string) ([]models.APIKey, error) {
+	query := `
+    SELECT ` + apiKeyColumns + `
+    FROM api_keys k JOIN service_accounts sa ON sa.id = k.service_account_id
+    WHERE ($1 = '' OR sa.tenant_id = $1) AND ($2 = '' OR k.service_account_id::text = $2)
+    ORDER BY k.created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, serviceAccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan API key: %w", err)
+		}
+		keys = append(keys, *key)
+	}
+
+	return keys, rows.Err()
+}
+
+// RevokeKey revokes an active key in the tenant. Revoking takes effect on the
+// key's next request.
+func (r *APIKeyRepository) RevokeKey(ctx context.Context, id, tenantID string) error {
+	query := `
+    UPDATE api_keys k SET revoked_at = NOW()
+    FROM service_accounts sa
+    WHERE sa.id = k.service_account_id AND k.id::text = $1 AND k.revoked_at IS NULL
+      AND ($2 = '' OR sa.tenant_id = $2)`
+
+	result, err := r.db.ExecContext(ctx, query, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w or already revoked: %s", ErrAPIKeyNotFound, id)
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/database"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+)
+
+// AuditRepository stores the audit log. It only ever inserts; the table
+// rejects updates and deletes.
+type AuditRepository struct {
+	db *database.DB
+}
+
+func NewAuditRepository(db *database.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+const auditColumns = `id, occurred_at, actor_type, COALESCE(actor_id, ''), COALESCE(tenant_id, ''), action,
+    COALESCE(target_type, ''), COALESCE(target_id, ''), outcome, COALESCE(status_code, 0),
+    before, after, changes, COALESCE(method, ''), COALESCE(path, ''), COALESCE(ip_address, ''),
+    COALESCE(user_agent, ''), details`
+
+func (r *AuditRepository) Insert(ctx context.Context, event *models.AuditEvent) error {
+	changesJSON, err := marshalNullable(event.Changes, len(event.Changes) > 0)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit changes: %w", err)
+	}
+
+	detailsJSON, err := marshalNullable(event.Details, len(event.Details) > 0)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit details: %w", err)
+	}
+
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	query := `
+    INSERT INTO audit_events (occurred_at, actor_type, actor_id, tenant_id, action, target_type, target_id,
+        outcome, status_code, before, after, changes, method, path, ip_address, user_agent, details)
+    VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5, NULLIF($6, ''), NULLIF($7, ''), $8, NULLIF($9, 0),
+        $10, $11, $12, NULLIF($13, ''), NULLIF($14, ''), NULLIF($15, ''), NULLIF($16, ''), $17)
+    RETURNING id`
+
+	err = r.db.QueryRowContext(ctx, query,
+		event.OccurredAt, event.ActorType, event.ActorID, event.TenantID, event.Action, event.TargetType, event.TargetID,
+		event.Outcome, event.StatusCode, nullableJSON(event.Before), nullableJSON(event.After), changesJSON,
+		event.Method, event.Path, event.IPAddress, event.UserAgent, detailsJSON,
+	).Scan(&event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit event: %w", err)
+	}
+
+	return nil
+}
+
+type AuditFilter struct {
+	// TenantID limits the listing to one tenant's events when set.
+	TenantID string
+
+	ActorType  string
+	ActorID    string
+	TargetType string
+	TargetID   string
+	Outcome    string
+
+	// Action matches exactly, or every action under a prefix when it ends
+	// in a dot, e.g. "task.".
+	Action string
+
+	Since *time.Time
+	Until *time.Time
+
+	Limit int
+
+	// BeforeID continues a listing below the last event of the previous page.
+	BeforeID int64
+}
+
+// List returns events matching the filter, newest first.
+func (r *AuditRepository) List(ctx context.Context, filter AuditFilter) ([]*models.AuditEvent, error) {
+	conditions := []string{"TRUE"}
+	args := []interface{}{}
+
+	addCondition := func(format string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+
+	for column, value := range map[string]string{
+		"tenant_id":   filter.TenantID,
+		"actor_type":  filter.ActorType,
+		"actor_id":    filter.ActorID,
+		"target_type": filter.TargetType,
+		"target_id":   filter.TargetID,
+		"outcome":     filter.Outcome,
+	} {
+		if value != "" {
+			addCondition(column+" = $%d", value)
+		}
+	}
+
+	if strings.HasSuffix(filter.Action, ".") {
+		addCondition("action LIKE $%d", likePrefixEscaper.Replace(filter.Action)+"%")
+	} else if filter.Action != "" {
+		addCondition("action = $%d", filter.Action)
+	}
+	if filter.Since != nil {
+		addCondition("occurred_at >= $%d", *filter.Since)
+	}
+	if filter.Until != nil {
+		addCondition("occurred_at < $%d", *filter.Until)
+	}
+	if filter.BeforeID > 0 {
+		addCondition("id < $%d", filter.BeforeID)
+	}
+
+	args = append(args, filter.Limit)
+	query := `SELECT ` + auditColumns + ` FROM audit_events WHERE ` + strings.Join(conditions, " AND ") +
+		fmt.Sprintf(` ORDER BY id DESC LIMIT $%d`, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]*models.AuditEvent, 0)
+	for rows.Next() {
+		var event models.AuditEvent
+		var before, after, changesJSON, detailsJSON []byte
+
+		err := rows.Scan(&event.ID, &event.OccurredAt, &event.ActorType, &event.ActorID, &event.TenantID, &event.Action,
+			&event.TargetType, &event.TargetID, &event.Outcome, &event.StatusCode,
+			&before, &after, &changesJSON, &event.Method, &event.Path, &event.IPAddress,
+			&event.UserAgent, &detailsJSON)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit event: %w", err)
+		}
+
+		event.Before = before
+		event.After = after
+		if changesJSON != nil {
+			if err := json.Unmarshal(changesJSON, &event.Changes); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal audit changes: %w", err)
+			}
+		}
+		if detailsJSON != nil {
+			if err := json.Unmarshal(detailsJSON, &event.Details); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal audit details: %w", err)
+			}
+		}
+
+		events = append(events, &event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return events, nil
+}
+
+func marshalNullable(value interface{}, present bool) ([]byte, error) {
+	if !present {
+		return nil, nil
+	}
+	return json.Marshal(value)
+}
+
+func nullableJSON(raw json.RawMessage) interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	return []byte(raw)
+}
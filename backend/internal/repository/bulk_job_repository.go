@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/rudraprasaaad/task-scheduler/internal/database"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+)
+
+type BulkJobRepository struct {
+	db *database.DB
+}
+
+func NewBulkJobRepository(db *database.DB) *BulkJobRepository {
+	return &BulkJobRepository{db: db}
+}
+
+const bulkJobColumns = `id, operation, status, params, total, processed, succeeded, failed, error, created_by, tenant_id, created_at, started_at, completed_at`
+
+func scanBulkJob(row rowScanner) (*models.BulkJob, error) {
+	var job models.BulkJob
+	var paramsJSON []byte
+	var errorMsg, createdBy, tenantID sql.NullString
+	var startedAt, completedAt sql.NullTime
+
+	err := row.Scan(&job.ID, &job.Operation, &job.Status, &paramsJSON, &job.Total, &job.Processed, &job.Succeeded, &job.Failed, &errorMsg, &createdBy, &tenantID, &job.CreatedAt, &startedAt, &completedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(paramsJSON, &job.Params); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal bulk job params: %w", err)
+	}
+
+	job.Error = errorMsg.String
+	job.CreatedBy = createdBy.String
+	job.TenantID = tenantID.String
+
+	if startedAt.Valid {
+		job.StartedAt = &startedAt.Time
+	}
+	if completedAt.Valid {
+		job.CompletedAt = &completedAt.Time
+	}
+
+	return &job, nil
+}
+
+func (r *BulkJobRepository) Create(ctx context.Context, job *models.BulkJob) error {
+	paramsJSON, err := json.Marshal(job.Params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal bulk job params: %w", err)
+	}
+
+	query := `INSERT INTO bulk_jobs (id, operation, status, params, created_by, tenant_id, created_at) VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7)`
+
+	_, err = r.db.ExecContext(ctx, query, job.ID, job.Operation, job.Status, paramsJSON, job.CreatedBy, job.TenantID, job.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create bulk job: %w", err)
+	}
+
+	return nil
+}
+
+func (r *BulkJobRepository) GetByID(ctx context.Context, id string) (*models.BulkJob, error) {
+	query := `SELECT ` + bulkJobColumns + ` FROM bulk_jobs WHERE id = $1`
+
+	job, err := scanBulkJob(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("bulk job not found")
+		}
+		return nil, fmt.Errorf("failed to get bulk job: %w", err)
+	}
+
+	return job, nil
+}
+
+// List returns the newest jobs, only those submitted within tenantID unless
+// it is empty.
+func (r *BulkJobRepository) List(ctx context.Context, tenantID string, limit int) ([]*models.BulkJob, error) {
+	query := `SELECT ` + bulkJobColumns + ` FROM bulk_jobs WHERE $1 = '' OR tenant_id = $1 ORDER BY created_at DESC LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bulk jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := make([]*models.BulkJob, 0)
+	for rows.Next() {
+		job, err := scanBulkJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan bulk job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return jobs, nil
+}
+
+func (r *BulkJobRepository) Start(ctx context.Context, id string, total int) error {
+	query := `UPDATE bulk_jobs SET status = $2, total = $3, started_at = NOW() WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, id, models.BulkJobRunning, total); err != nil {
+		return fmt.Errorf("failed to start bulk job: %w", err)
+	}
+
+	return nil
+}
+
+// RecordItems stores a batch of item results and advances the job's progress
+// counters in the same transaction, so the counters always match the report.
+func (r *BulkJobRepository) RecordItems(ctx context.Context, jobID string, items []*models.BulkJobItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	indexes := make([]int64, len(items))
+	taskIDs := make([]string, len(items))
+	oks := make([]bool, len(items))
+	errs := make([]string, len(items))
+	succeeded := 0
+	for i, item := range items {
+		indexes[i] = int64(item.Index)
+		taskIDs[i] = item.TaskID
+		oks[i] = item.OK
+		errs[i] = item.Error
+		if item.OK {
+			succeeded++
+		}
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+    INSERT INTO bulk_job_items (job_id, item_index, task_id, ok, error)
+    SELECT $1, item_index, NULLIF(task_id, ''), ok, NULLIF(error, '')
+    FROM unnest($2::int[], $3::text[], $4::bool[], $5::text[]) AS items(item_index, task_id, ok, error)
+    ON CONFLICT (job_id, item_index) DO NOTHING`,
+		jobID, pq.Array(indexes), pq.Array(taskIDs), pq.Array(oks), pq.Array(errs))
+	if err != nil {
+		return fmt.Errorf("failed to record bulk job items: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+    UPDATE bulk_jobs SET processed = processed + $2, succeeded = succeeded + $3, failed = failed + $4
+    WHERE id = $1`, jobID, len(items), succeeded, len(items)-succeeded)
+	if err != nil {
+		return fmt.Errorf("failed to update bulk job progress: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (r *BulkJobRepository) Finish(ctx context.Context, id string, status models.BulkJobStatus, errorMsg string) error {
+	query := `UPDATE bulk_jobs SET status = $2, error = NULLIF($3, ''), completed_at = NOW() WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, id, status, errorMsg); err != nil {
+		return fmt.Errorf("failed to finish bulk job: %w", err)
+	}
+
+	return nil
+}
+
+// FailInterrupted marks jobs that were still pending or running when the
+// server stopped. Their goroutines are gone, so they would never finish.
+func (r *BulkJobRepository) FailInterrupted(ctx context.Context) (int64, error) {
+	query := `
+    UPDATE bulk_jobs SET status = $1, error = 'interrupted by server restart', completed_at = NOW()
+    WHERE status IN ($2, $3)`
+
+	result, err := r.db.ExecContext(ctx, query, models.BulkJobFailed, models.BulkJobPending, models.BulkJobRunning)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fail interrupted bulk jobs: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// ListItems returns a page of a job's per-item report in submission order.
+// With failedOnly set it returns just the items that did not succeed.
+func (r *BulkJobRepository) ListItems(ctx context.Context, jobID string, failedOnly bool, limit, offset int) ([]*models.BulkJobItem, error) {
+	query := `
+    SELECT job_id, item_index, COALESCE(task_id, ''), ok, COALESCE(error, '')
+    FROM bulk_job_items
+    WHERE job_id = $1 AND (NOT $2 OR NOT ok)
+    ORDER BY item_index
+    LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.QueryContext(ctx, query, jobID, failedOnly, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bulk job items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]*models.BulkJobItem, 0)
+	for rows.Next() {
+		var item models.BulkJobItem
+		if err := rows.Scan(&item.JobID, &item.Index, &item.TaskID, &item.OK, &item.Error); err != nil {
+			return nil, fmt.Errorf("failed to scan bulk job item: %w", err)
+		}
+		items = append(items, &item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/database"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+type SessionRepository struct {
+	db *database.DB
+}
+
+func NewSessionRepository(db *database.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+func (r *SessionRepository) Create(ctx context.Context, session *models.Session) error {
+	query := `
+    INSERT INTO auth_sessions (id, user_id, refresh_hash, user_agent, ip_address, created_at, expires_at)
+    VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, $7)`
+
+	_, err := r.db.ExecContext(ctx, query, session.ID, session.UserID, session.RefreshHash, session.UserAgent, session.IPAddress, session.CreatedAt, session.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) Get(ctx context.Context, id string) (*models.Session, error) {
+	query := `
+    SELECT id, user_id, refresh_hash, previous_hash, user_agent, ip_address, created_at, refreshed_at, expires_at, revoked_at
+    FROM auth_sessions
+    WHERE id::text = $1`
+
+	var session models.Session
+	var previousHash, userAgent, ipAddress sql.NullString
+	var refreshedAt, revokedAt sql.NullTime
+
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&session.ID, &session.UserID, &session.RefreshHash, &previousHash,
+		&userAgent, &ipAddress, &session.CreatedAt, &refreshedAt, &session.ExpiresAt, &revokedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	session.PreviousHash = previousHash.String
+	session.UserAgent = userAgent.String
+	session.IPAddress = ipAddress.String
+	if refreshedAt.Valid {
+		session.RefreshedAt = &refreshedAt.Time
+	}
+	if revokedAt.Valid {
+		session.RevokedAt = &revokedAt.Time
+	}
+
+	return &session, nil
+}
+
+// Rotate replaces the session's refresh token hash, but only if it is still
+// currentHash, so two refreshes racing with the same token cannot both win.
+func (r *SessionRepository) Rotate(ctx context.Context, id, currentHash, newHash string) error {
+	query := `
+    UPDATE auth_sessions SET previous_hash = refresh_hash, refresh_hash = $3, refreshed_at = NOW()
+    WHERE id = $1 AND refresh_hash = $2 AND revoked_at IS NULL AND expires_at > NOW()`
+
+	result, err := r.db.ExecContext(ctx, query, id, currentHash, newHash)
+	if err != nil {
+		return fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w or already refreshed: %s", ErrSessionNotFound, id)
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) Revoke(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE auth_sessions SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`, id); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) RevokeUser(ctx context.Context, userID int64) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `UPDATE auth_sessions SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -50,3 +50,47 @@ func (r *TaskExecutionRepository) Create(ctx context.Context, execution *models.
 
 	return nil
 }
+
+// ListByTaskID returns every recorded attempt of a task, oldest first, numbered
+// from 1 in the order they started.
+func (r *TaskExecutionRepository) ListByTaskID(ctx context.Context, taskID string) ([]*models.TaskExecution, error) {
+	query := `
+    SELECT id, task_id, ROW_NUMBER() OVER (ORDER BY started_at, id), COALESCE(worker_id, ''),
+           started_at, completed_at, status, COALESCE(error, ''), COALESCE(execution_time_ms, 0)
+    FROM task_executions
+    WHERE task_id = $1
+    ORDER BY started_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task executions: %w", err)
+	}
+	defer rows.Close()
+
+	executions := make([]*models.TaskExecution, 0)
+	for rows.Next() {
+		var execution models.TaskExecution
+		err := rows.Scan(&execution.ID, &execution.TaskID, &execution.Attempt, &execution.WorkerID,
+			&execution.StartedAt, &execution.CompletedAt, &execution.Status, &execution.Error, &execution.ExecutionTimeMs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task execution: %w", err)
+		}
+		executions = append(executions, &execution)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return executions, nil
+}
+
+func (r *TaskExecutionRepository) CountByTaskID(ctx context.Context, taskID string) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM task_executions WHERE task_id = $1`, taskID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count task executions: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/database"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+)
+
+var ErrTaskLogNotFound = errors.New("task log not found")
+
+type TaskLogRepository struct {
+	db *database.DB
+}
+
+func NewTaskLogRepository(db *database.DB) *TaskLogRepository {
+	return &TaskLogRepository{db: db}
+}
+
+// Save stores an attempt's log, replacing any earlier copy. An attempt that
+// was interrupted and requeued runs again under the same number.
+func (r *TaskLogRepository) Save(ctx context.Context, taskLog *models.TaskLog) error {
+	linesJSON, err := json.Marshal(taskLog.Lines)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task log lines: %w", err)
+	}
+
+	query := `
+    INSERT INTO task_logs (task_id, attempt, lines, dropped)
+    VALUES ($1, $2, $3, $4)
+    ON CONFLICT (task_id, attempt) DO UPDATE
+    SET lines = EXCLUDED.lines, dropped = EXCLUDED.dropped, created_at = NOW()`
+
+	_, err = r.db.ExecContext(ctx, query, taskLog.TaskID, taskLog.Attempt, linesJSON, taskLog.Dropped)
+	if err != nil {
+		return fmt.Errorf("failed to save task log: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TaskLogRepository) Get(ctx context.Context, taskID string, attempt int) (*models.TaskLog, error) {
+	query := `SELECT lines, dropped FROM task_logs WHERE task_id = $1 AND attempt = $2`
+
+	taskLog := &models.TaskLog{TaskID: taskID, Attempt: attempt, Done: true}
+	var linesJSON []byte
+
+	err := r.db.QueryRowContext(ctx, query, taskID, attempt).Scan(&linesJSON, &taskLog.Dropped)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrTaskLogNotFound
+		}
+		return nil, fmt.Errorf("failed to get task log: %w", err)
+	}
+
+	if err := json.Unmarshal(linesJSON, &taskLog.Lines); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task log lines: %w", err)
+	}
+
+	return taskLog, nil
+}
+
+// LatestAttempt returns the highest attempt with a stored log, or 0.
+func (r *TaskLogRepository) LatestAttempt(ctx context.Context, taskID string) (int, error) {
+	var attempt int
+	err := r.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(attempt), 0) FROM task_logs WHERE task_id = $1`, taskID).Scan(&attempt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest task log attempt: %w", err)
+	}
+
+	return attempt, nil
+}
@@ -5,7 +5,11 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/lib/pq"
 	"github.com/rudraprasaaad/task-scheduler/internal/database"
 	"github.com/rudraprasaaad/task-scheduler/internal/models"
 )
@@ -18,46 +22,33 @@ func NewTaskRepository(db *database.DB) *TaskRepository {
 	return &TaskRepository{db: db}
 }
 
-func (r *TaskRepository) Create(ctx context.Context, task *models.Task) error {
-	payloadJSON, err := json.Marshal(task.Payload)
-
-	if err != nil {
-		return fmt.Errorf("failed to marshal payload: %w", err)
-	}
-
-	query := `INSERT into tasks (id, name, type, payload, priority, status, retries, max_retries, created_at, updated_at, scheduled_at, error, worker_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
-
-	_, err = r.db.ExecContext(ctx, query, task.ID, task.Name, task.Type, payloadJSON, task.Priority, task.Status, task.Retries, task.MaxRetries, task.CreatedAt, task.UpdatedAt, task.ScheduledAt, task.Error, task.WorkerID)
-
-	if err != nil {
-		return fmt.Errorf("failed to create task: %w", err)
-	}
+const taskColumns = `id, name, type, payload, priority, status, retries, max_retries, created_at, updated_at, scheduled_at, started_at, completed_at, error, worker_id, selector, tenant_id, created_by`
 
-	return nil
+type rowScanner interface {
+	Scan(dest ...interface{}) error
 }
 
-func (r *TaskRepository) GetByID(ctx context.Context, id string) (*models.Task, error) {
-	query := `SELECT id, name, type, payload, priority, status, retries, max_retries, created_at, updated_at, scheduled_at, started_at, completed_at, error, worker_id FROM tasks WHERE id = $1`
-
+func scanTask(row rowScanner) (*models.Task, error) {
 	var task models.Task
-	var payloadJSON []byte
+	var payloadJSON, selectorJSON []byte
 	var startedAt, completedAt sql.NullTime
-	var workerID, errorMsg sql.NullString
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&task.ID, &task.Name, &task.Type, &payloadJSON, &task.Priority, &task.Status, &task.Retries, &task.MaxRetries, &task.CreatedAt, &task.UpdatedAt, &task.ScheduledAt, &startedAt, &completedAt, &errorMsg, &workerID)
+	var workerID, errorMsg, createdBy sql.NullString
 
+	err := row.Scan(&task.ID, &task.Name, &task.Type, &payloadJSON, &task.Priority, &task.Status, &task.Retries, &task.MaxRetries, &task.CreatedAt, &task.UpdatedAt, &task.ScheduledAt, &startedAt, &completedAt, &errorMsg, &workerID, &selectorJSON, &task.TenantID, &createdBy)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("task not found")
-		}
-
-		return nil, fmt.Errorf("failed to get task: %w", err)
+		return nil, err
 	}
 
 	if err := json.Unmarshal(payloadJSON, &task.Payload); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
 	}
 
+	if len(selectorJSON) > 0 {
+		if err := json.Unmarshal(selectorJSON, &task.Selector); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal selector: %w", err)
+		}
+	}
+
 	if startedAt.Valid {
 		task.StartedAt = &startedAt.Time
 	}
@@ -70,10 +61,89 @@ func (r *TaskRepository) GetByID(ctx context.Context, id string) (*models.Task,
 	if workerID.Valid {
 		task.WorkerID = workerID.String
 	}
+	task.CreatedBy = createdBy.String
 
 	return &task, nil
 }
 
+func marshalSelector(selector map[string]string) ([]byte, error) {
+	if len(selector) == 0 {
+		return nil, nil
+	}
+
+	return json.Marshal(selector)
+}
+
+func (r *TaskRepository) Create(ctx context.Context, task *models.Task) error {
+	payloadJSON, err := json.Marshal(task.Payload)
+
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	selectorJSON, err := marshalSelector(task.Selector)
+	if err != nil {
+		return fmt.Errorf("failed to marshal selector: %w", err)
+	}
+
+	query := `INSERT into tasks (id, name, type, payload, priority, status, retries, max_retries, created_at, updated_at, scheduled_at, error, worker_id, selector, tenant_id, created_by) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NULLIF($13, ''), $14, $15, NULLIF($16, ''))`
+
+	_, err = r.db.ExecContext(ctx, query, task.ID, task.Name, task.Type, payloadJSON, task.Priority, task.Status, task.Retries, task.MaxRetries, task.CreatedAt, task.UpdatedAt, task.ScheduledAt, task.Error, task.WorkerID, selectorJSON, models.TenantOf(task), task.CreatedBy)
+
+	if err != nil {
+		return fmt.Errorf("failed to create task: %w", err)
+	}
+
+	return nil
+}
+
+// Save inserts the task or overwrites the stored copy. Workers use it to
+// record state transitions, which also covers tasks that were enqueued
+// straight into Redis without a row, such as those created by cron jobs. The
+// owner of an existing row is never changed, whatever copy a worker reports.
+func (r *TaskRepository) Save(ctx context.Context, task *models.Task) error {
+	payloadJSON, err := json.Marshal(task.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	selectorJSON, err := marshalSelector(task.Selector)
+	if err != nil {
+		return fmt.Errorf("failed to marshal selector: %w", err)
+	}
+
+	query := `
+    INSERT INTO tasks (` + taskColumns + `)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NULLIF($15, ''), $16, $17, NULLIF($18, ''))
+    ON CONFLICT (id) DO UPDATE SET
+        name = EXCLUDED.name, type = EXCLUDED.type, payload = EXCLUDED.payload, priority = EXCLUDED.priority,
+        status = EXCLUDED.status, retries = EXCLUDED.retries, max_retries = EXCLUDED.max_retries,
+        updated_at = EXCLUDED.updated_at, scheduled_at = EXCLUDED.scheduled_at, started_at = EXCLUDED.started_at,
+        completed_at = EXCLUDED.completed_at, error = EXCLUDED.error, worker_id = EXCLUDED.worker_id, selector = EXCLUDED.selector`
+
+	_, err = r.db.ExecContext(ctx, query, task.ID, task.Name, task.Type, payloadJSON, task.Priority, task.Status, task.Retries, task.MaxRetries, task.CreatedAt, task.UpdatedAt, task.ScheduledAt, task.StartedAt, task.CompletedAt, task.Error, task.WorkerID, selectorJSON, models.TenantOf(task), task.CreatedBy)
+	if err != nil {
+		return fmt.Errorf("failed to save task: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) GetByID(ctx context.Context, id string) (*models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = $1`
+
+	task, err := scanTask(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("task not found")
+		}
+
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	return task, nil
+}
+
 func (r *TaskRepository) Update(ctx context.Context, task *models.Task) error {
 	payloadJSON, err := json.Marshal(task.Payload)
 
@@ -81,9 +151,14 @@ func (r *TaskRepository) Update(ctx context.Context, task *models.Task) error {
 		return fmt.Errorf("failed to marshal payload : %w", err)
 	}
 
-	query := `UPDATE tasks SET name = $2, type = $2,  payload = $4, priority = $5, status = $6, retries = $7, max_retries = $8, updated_at = $9, scheduled_at = $10, started_at = $11, completed_at = $12, error = $13, worker_id = $14 WHERE id = $1`
+	selectorJSON, err := marshalSelector(task.Selector)
+	if err != nil {
+		return fmt.Errorf("failed to marshal selector: %w", err)
+	}
 
-	_, err = r.db.ExecContext(ctx, query, task.ID, task.Name, task.Type, payloadJSON, task.Priority, task.Status, task.Retries, task.MaxRetries, task.UpdatedAt, task.ScheduledAt, task.StartedAt, task.CompletedAt, task.Error, task.WorkerID)
+	query := `UPDATE tasks SET name = $2, type = $3,  payload = $4, priority = $5, status = $6, retries = $7, max_retries = $8, updated_at = $9, scheduled_at = $10, started_at = $11, completed_at = $12, error = $13, worker_id = NULLIF($14, ''), selector = $15 WHERE id = $1`
+
+	_, err = r.db.ExecContext(ctx, query, task.ID, task.Name, task.Type, payloadJSON, task.Priority, task.Status, task.Retries, task.MaxRetries, task.UpdatedAt, task.ScheduledAt, task.StartedAt, task.CompletedAt, task.Error, task.WorkerID, selectorJSON)
 
 	if err != nil {
 		return fmt.Errorf("failed to update task :%w", err)
@@ -92,8 +167,22 @@ func (r *TaskRepository) Update(ctx context.Context, task *models.Task) error {
 	return nil
 }
 
+// UpdateScheduling writes only the fields a pending task can have changed, so
+// it cannot undo a status change made by a worker that claimed the task
+// concurrently.
+func (r *TaskRepository) UpdateScheduling(ctx context.Context, task *models.Task) error {
+	query := `UPDATE tasks SET priority = $2, scheduled_at = $3, max_retries = $4, updated_at = $5 WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, task.ID, task.Priority, task.ScheduledAt, task.MaxRetries, task.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update task scheduling: %w", err)
+	}
+
+	return nil
+}
+
 func (r *TaskRepository) List(ctx context.Context, limit, offset int) ([]*models.Task, error) {
-	query := `SELECT id, name, type, payload, priority, status, retries, max_retries, created_at, updated_at, scheduled_at, started_at, completed_at, error, worker_id FROM tasks ORDER BY created_at DESC LIMIT $1 OFFSET $2`
+	query := `SELECT ` + taskColumns + ` FROM tasks ORDER BY created_at DESC LIMIT $1 OFFSET $2`
 
 	rows, err := r.db.QueryContext(ctx, query, limit, offset)
 
@@ -106,40 +195,211 @@ func (r *TaskRepository) List(ctx context.Context, limit, offset int) ([]*models
 	var tasks []*models.Task
 
 	for rows.Next() {
-		var task models.Task
-		var payloadJSON []byte
-		var startedAt, completedAt sql.NullTime
-		var workerID, errorMsg sql.NullString
-
-		err := rows.Scan(
-			&task.ID, &task.Name, &task.Type, &payloadJSON, &task.Priority, &task.Status, &task.Retries, &task.MaxRetries, &task.CreatedAt, &task.UpdatedAt, &task.ScheduledAt, &startedAt, &completedAt, &errorMsg, &workerID,
-		)
-
+		task, err := scanTask(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan task: %w", err)
 		}
 
-		if err := json.Unmarshal(payloadJSON, &task.Payload); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
-		}
+		tasks = append(tasks, task)
+	}
 
-		if startedAt.Valid {
-			task.StartedAt = &startedAt.Time
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return tasks, nil
+}
+
+type TaskFilter struct {
+	// TenantID limits the listing to one tenant's tasks when set.
+	TenantID string
+
+	Statuses    []string
+	Types       []string
+	WorkerID    string
+	MinPriority *int
+	MaxPriority *int
+	NamePrefix  string
+
+	// Payload matches tasks whose payload contains this JSON object.
+	Payload map[string]interface{}
+
+	CreatedAfter    *time.Time
+	CreatedBefore   *time.Time
+	ScheduledAfter  *time.Time
+	ScheduledBefore *time.Time
+	CompletedAfter  *time.Time
+	CompletedBefore *time.Time
+
+	SortField      string
+	SortDescending bool
+	Limit          int
+	Offset         int
+
+	// After continues a listing after the last task of the previous page. It
+	// takes precedence over Offset and must come from the same sort.
+	After *TaskCursor
+}
+
+// TaskCursor is the position of a task in a sorted listing: the sort column
+// as text, which the query casts back, and the id that breaks ties.
+type TaskCursor struct {
+	Value string
+	ID    string
+}
+
+type taskSortColumn struct {
+	expr string
+	cast string
+}
+
+// taskSortColumns are the fields tasks can be sorted by. Nullable timestamps
+// sort as -infinity so keyset comparisons never meet a NULL.
+var taskSortColumns = map[string]taskSortColumn{
+	"created_at":   {expr: "created_at", cast: "timestamptz"},
+	"updated_at":   {expr: "updated_at", cast: "timestamptz"},
+	"scheduled_at": {expr: "COALESCE(scheduled_at, '-infinity')", cast: "timestamptz"},
+	"completed_at": {expr: "COALESCE(completed_at, '-infinity')", cast: "timestamptz"},
+	"priority":     {expr: "priority", cast: "integer"},
+	"name":         {expr: "name", cast: "text"},
+}
+
+const DefaultTaskSortField = "created_at"
+
+func IsTaskSortField(field string) bool {
+	_, ok := taskSortColumns[field]
+	return ok
+}
+
+// TaskCursorFor returns the cursor of task in a listing sorted by field.
+func TaskCursorFor(task *models.Task, field string) *TaskCursor {
+	cursor := &TaskCursor{ID: task.ID}
+
+	timestamp := func(t *time.Time) string {
+		if t == nil || t.IsZero() {
+			return "-infinity"
 		}
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+
+	switch field {
+	case "updated_at":
+		cursor.Value = timestamp(&task.UpdatedAt)
+	case "scheduled_at":
+		cursor.Value = timestamp(&task.ScheduledAt)
+	case "completed_at":
+		cursor.Value = timestamp(task.CompletedAt)
+	case "priority":
+		cursor.Value = strconv.Itoa(int(task.Priority))
+	case "name":
+		cursor.Value = task.Name
+	default:
+		cursor.Value = timestamp(&task.CreatedAt)
+	}
+
+	return cursor
+}
+
+// ListFiltered lists tasks matching the filter, newest first unless another
+// sort is given. Ties on the sort column are broken by id so keyset
+// pagination never skips or repeats a task.
+func (r *TaskRepository) ListFiltered(ctx context.Context, filter TaskFilter) ([]*models.Task, error) {
+	sortField := filter.SortField
+	if sortField == "" {
+		sortField = DefaultTaskSortField
+	}
+
+	sort, ok := taskSortColumns[sortField]
+	if !ok {
+		return nil, fmt.Errorf("unknown sort field %q", sortField)
+	}
+
+	conditions := []string{"TRUE"}
+	args := []interface{}{}
 
-		if completedAt.Valid {
-			task.CompletedAt = &completedAt.Time
+	addCondition := func(format string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+
+	if filter.TenantID != "" {
+		addCondition("tenant_id = $%d", filter.TenantID)
+	}
+	if len(filter.Statuses) > 0 {
+		addCondition("status::text = ANY($%d::text[])", pq.Array(filter.Statuses))
+	}
+	if len(filter.Types) > 0 {
+		addCondition("type = ANY($%d::text[])", pq.Array(filter.Types))
+	}
+	if filter.WorkerID != "" {
+		addCondition("worker_id = $%d", filter.WorkerID)
+	}
+	if filter.MinPriority != nil {
+		addCondition("priority >= $%d", *filter.MinPriority)
+	}
+	if filter.MaxPriority != nil {
+		addCondition("priority <= $%d", *filter.MaxPriority)
+	}
+	if filter.NamePrefix != "" {
+		addCondition("name LIKE $%d", likePrefixEscaper.Replace(filter.NamePrefix)+"%")
+	}
+	if len(filter.Payload) > 0 {
+		payloadJSON, err := json.Marshal(filter.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal payload filter: %w", err)
 		}
+		addCondition("payload @> $%d::jsonb", payloadJSON)
+	}
 
-		if errorMsg.Valid {
-			task.Error = errorMsg.String
+	timeRanges := []struct {
+		column string
+		op     string
+		value  *time.Time
+	}{
+		{"created_at", ">=", filter.CreatedAfter},
+		{"created_at", "<", filter.CreatedBefore},
+		{"scheduled_at", ">=", filter.ScheduledAfter},
+		{"scheduled_at", "<", filter.ScheduledBefore},
+		{"completed_at", ">=", filter.CompletedAfter},
+		{"completed_at", "<", filter.CompletedBefore},
+	}
+	for _, tr := range timeRanges {
+		if tr.value != nil {
+			addCondition(tr.column+" "+tr.op+" $%d", *tr.value)
 		}
+	}
+
+	direction, comparison := "ASC", ">"
+	if filter.SortDescending {
+		direction, comparison = "DESC", "<"
+	}
+
+	offset := filter.Offset
+	if filter.After != nil {
+		args = append(args, filter.After.Value, filter.After.ID)
+		conditions = append(conditions, fmt.Sprintf("(%s, id) %s ($%d::%s, $%d::uuid)",
+			sort.expr, comparison, len(args)-1, sort.cast, len(args)))
+		offset = 0
+	}
+
+	args = append(args, filter.Limit, offset)
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE ` + strings.Join(conditions, " AND ") +
+		fmt.Sprintf(` ORDER BY %s %s, id %s LIMIT $%d OFFSET $%d`, sort.expr, direction, direction, len(args)-1, len(args))
 
-		if workerID.Valid {
-			task.WorkerID = workerID.String
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := make([]*models.Task, 0)
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
 		}
 
-		tasks = append(tasks, &task)
+		tasks = append(tasks, task)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -149,17 +409,32 @@ func (r *TaskRepository) List(ctx context.Context, limit, offset int) ([]*models
 	return tasks, nil
 }
 
-func (r *TaskRepository) GetReadyTasks(ctx context.Context, limit int) ([]*models.Task, error) {
+var likePrefixEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// GetReadyTasks returns pending tasks that are due and that the given worker
+// is able to run, i.e. whose selector is contained in the worker's labels and
+// whose type the worker supports.
+func (r *TaskRepository) GetReadyTasks(ctx context.Context, worker *models.Worker, limit int) ([]*models.Task, error) {
+	labels := worker.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	labelsJSON, err := json.Marshal(labels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal worker labels: %w", err)
+	}
+
 	query := `
-    SELECT id, name, type, payload, priority, status, retries, max_retries,
-           created_at, updated_at, scheduled_at, started_at, completed_at,
-           error, worker_id
+    SELECT ` + taskColumns + `
     FROM tasks 
     WHERE status = 'pending' AND scheduled_at <= NOW()
+      AND (selector IS NULL OR selector <@ $2::jsonb)
+      AND (cardinality($3::text[]) = 0 OR type = ANY($3::text[]))
     ORDER BY priority DESC, scheduled_at ASC
     LIMIT $1`
 
-	rows, err := r.db.QueryContext(ctx, query, limit)
+	rows, err := r.db.QueryContext(ctx, query, limit, labelsJSON, pq.Array(worker.TaskTypes))
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get ready tasks: %w", err)
@@ -170,52 +445,26 @@ func (r *TaskRepository) GetReadyTasks(ctx context.Context, limit int) ([]*model
 	var tasks []*models.Task
 
 	for rows.Next() {
-		var task models.Task
-		var payloadJSON []byte
-		var startedAt, completedAt sql.NullTime
-		var workerID, errorMsg sql.NullString
-
-		err := rows.Scan(
-			&task.ID, &task.Name, &task.Type, &payloadJSON, &task.Priority, &task.Status, &task.Retries, &task.MaxRetries, &task.CreatedAt, &task.UpdatedAt, &task.ScheduledAt, &startedAt, &completedAt, &errorMsg, &workerID,
-		)
-
+		task, err := scanTask(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan ready taskL %w", err)
 		}
 
-		if err := json.Unmarshal(payloadJSON, &task.Payload); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
-		}
-
-		if startedAt.Valid {
-			task.StartedAt = &startedAt.Time
-		}
-
-		if completedAt.Valid {
-			task.CompletedAt = &completedAt.Time
-		}
-
-		if errorMsg.Valid {
-			task.Error = errorMsg.String
-		}
-
-		if workerID.Valid {
-			task.WorkerID = workerID.String
-		}
-
-		tasks = append(tasks, &task)
+		tasks = append(tasks, task)
 	}
 
 	return tasks, nil
 }
 
-func (r *TaskRepository) GetStats(ctx context.Context) (map[string]int, error) {
+// GetStats counts tasks by status, across every tenant when tenantID is empty.
+func (r *TaskRepository) GetStats(ctx context.Context, tenantID string) (map[string]int, error) {
 	query := `
     SELECT status, COUNT(*) as count
     FROM tasks
+    WHERE $1 = '' OR tenant_id = $1
     GROUP BY status`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get task stats: %w", err)
@@ -240,6 +489,36 @@ func (r *TaskRepository) GetStats(ctx context.Context) (map[string]int, error) {
 	return stats, nil
 }
 
+// CountRunningByWorker counts the tasks a worker has claimed and not yet
+// finished, by task type.
+func (r *TaskRepository) CountRunningByWorker(ctx context.Context, workerID string) (map[string]int, error) {
+	query := `
+    SELECT type, COUNT(*) as count
+    FROM tasks
+    WHERE worker_id = $1 AND status = 'running'
+    GROUP BY type`
+
+	rows, err := r.db.QueryContext(ctx, query, workerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count running tasks: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var taskType string
+		var count int
+
+		if err := rows.Scan(&taskType, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan running tasks: %w", err)
+		}
+
+		counts[taskType] = count
+	}
+
+	return counts, rows.Err()
+}
+
 func (r *TaskRepository) Delete(ctx context.Context, id string) error {
 	query := "DELETE FROM tasks WHERE id = $1"
 
@@ -270,7 +549,7 @@ func (r *TaskRepository) GetStatus(ctx context.Context, taskID string) (models.T
 }
 
 func (r *TaskRepository) UpdateStatus(ctx context.Context, taskID string, status models.TaskStatus) error {
-	query := `UPDATE tasks SET tasks = $1, updated_at = NOW() WHERE id = $2`
+	query := `UPDATE tasks SET status = $1, updated_at = NOW() WHERE id = $2`
 
 	result, err := r.db.ExecContext(ctx, query, status, taskID)
 	if err != nil {
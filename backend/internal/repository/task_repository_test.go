@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/database"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+)
+
+func newMockDB(t *testing.T) (*database.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return &database.DB{DB: db}, mock
+}
+
+// An unassigned task has no worker_id, which tasks.worker_id references, so
+// every write must store NULL rather than an empty string.
+func TestTaskWritesStoreUnassignedWorkerAsNull(t *testing.T) {
+	task := models.NewTask("report", "generate-report", map[string]interface{}{"day": "monday"})
+
+	tests := []struct {
+		name   string
+		expect string
+		write  func(*TaskRepository) error
+	}{
+		{"create", `INSERT into tasks .* NULLIF\(\$13, ''\)`, func(r *TaskRepository) error { return r.Create(context.Background(), task) }},
+		{"save", `INSERT INTO tasks .* NULLIF\(\$15, ''\)`, func(r *TaskRepository) error { return r.Save(context.Background(), task) }},
+		{"update", `UPDATE tasks SET .* worker_id = NULLIF\(\$14, ''\)`, func(r *TaskRepository) error { return r.Update(context.Background(), task) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			mock.ExpectExec(tt.expect).WillReturnResult(sqlmock.NewResult(0, 1))
+
+			if err := tt.write(NewTaskRepository(db)); err != nil {
+				t.Fatalf("write failed: %v", err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func taskRows(tasks ...*models.Task) *sqlmock.Rows {
+	rows := sqlmock.NewRows(strings.Split(strings.ReplaceAll(taskColumns, " ", ""), ","))
+	for _, task := range tasks {
+		rows.AddRow(task.ID, task.Name, task.Type, []byte(`{}`), task.Priority, task.Status, task.Retries, task.MaxRetries,
+			task.CreatedAt, task.UpdatedAt, task.ScheduledAt, nil, nil, nil, nil, nil, models.TenantOf(task), nil)
+	}
+	return rows
+}
+
+// Each page continues strictly after the last task of the one before, on the
+// sort column and then id, so tasks sharing a timestamp are neither skipped
+// nor repeated.
+func TestListFilteredContinuesAfterCursor(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewTaskRepository(db)
+
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	first := models.NewTask("first", "email", nil)
+	second := models.NewTask("second", "email", nil)
+	first.CreatedAt, second.CreatedAt = createdAt.Add(time.Second), createdAt
+
+	filter := TaskFilter{TenantID: "acme", SortDescending: true, Limit: 2}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE TRUE AND tenant_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3`)).
+		WithArgs("acme", 2, 0).
+		WillReturnRows(taskRows(first, second))
+
+	page, err := repo.ListFiltered(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("first page failed: %v", err)
+	}
+	if len(page) != 2 || page[1].ID != second.ID {
+		t.Fatalf("got %d tasks on the first page, want first and second", len(page))
+	}
+
+	filter.After = TaskCursorFor(page[len(page)-1], "created_at")
+	filter.Offset = 40
+
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE TRUE AND tenant_id = $1 AND (created_at, id) < ($2::timestamptz, $3::uuid) ORDER BY created_at DESC, id DESC LIMIT $4 OFFSET $5`)).
+		WithArgs("acme", "2026-03-01T12:00:00Z", second.ID, 2, 0).
+		WillReturnRows(taskRows())
+
+	if _, err := repo.ListFiltered(context.Background(), filter); err != nil {
+		t.Fatalf("second page failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestListFilteredCursorOnNullableColumn(t *testing.T) {
+	db, mock := newMockDB(t)
+
+	pending := models.NewTask("pending", "email", nil)
+	cursor := TaskCursorFor(pending, "completed_at")
+	if cursor.Value != "-infinity" {
+		t.Fatalf("got cursor value %q for a task that never completed, want -infinity", cursor.Value)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`(COALESCE(completed_at, '-infinity'), id) > ($1::timestamptz, $2::uuid) ORDER BY COALESCE(completed_at, '-infinity') ASC, id ASC`)).
+		WithArgs("-infinity", pending.ID, 10, 0).
+		WillReturnRows(taskRows())
+
+	_, err := NewTaskRepository(db).ListFiltered(context.Background(), TaskFilter{SortField: "completed_at", Limit: 10, After: cursor})
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestListFilteredRejectsUnknownSort(t *testing.T) {
+	db, _ := newMockDB(t)
+
+	_, err := NewTaskRepository(db).ListFiltered(context.Background(), TaskFilter{SortField: "payload", Limit: 10})
+	if err == nil {
+		t.Fatal("expected an error for an unknown sort field")
+	}
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/database"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+)
+
+var (
+	ErrTenantNotFound = errors.New("tenant not found")
+	ErrTenantExists   = errors.New("tenant already exists")
+)
+
+type TenantRepository struct {
+	db *database.DB
+}
+
+func NewTenantRepository(db *database.DB) *TenantRepository {
+	return &TenantRepository{db: db}
+}
+
+const tenantColumns = `id, name, max_pending, enqueue_per_minute, max_running, weight, created_at`
+
+func scanTenant(row rowScanner) (*models.Tenant, error) {
+	var tenant models.Tenant
+	var maxPending, enqueuePerMinute, maxRunning, weight sql.NullInt64
+
+	if err := row.Scan(&tenant.ID, &tenant.Name, &maxPending, &enqueuePerMinute, &maxRunning, &weight, &tenant.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	tenant.MaxPending = nullableInt(maxPending)
+	tenant.EnqueuePerMinute = nullableInt(enqueuePerMinute)
+	tenant.MaxRunning = nullableInt(maxRunning)
+	tenant.Weight = nullableInt(weight)
+
+	return &tenant, nil
+}
+
+func nullableInt(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+	n := int(value.Int64)
+	return &n
+}
+
+func (r *TenantRepository) Create(ctx context.Context, tenant *models.Tenant) error {
+	query := `INSERT INTO tenants (id, name) VALUES ($1, $2) ON CONFLICT (id) DO NOTHING RETURNING created_at`
+
+	err := r.db.QueryRowContext(ctx, query, tenant.ID, tenant.Name).Scan(&tenant.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: %s", ErrTenantExists, tenant.ID)
+		}
+		return fmt.Errorf("failed to create tenant: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TenantRepository) List(ctx context.Context) ([]*models.Tenant, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+tenantColumns+` FROM tenants ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+	defer rows.Close()
+
+	tenants := make([]*models.Tenant, 0)
+	for rows.Next() {
+		tenant, err := scanTenant(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tenant: %w", err)
+		}
+		tenants = append(tenants, tenant)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return tenants, nil
+}
+
+func (r *TenantRepository) Get(ctx context.Context, id string) (*models.Tenant, error) {
+	tenant, err := scanTenant(r.db.QueryRowContext(ctx, `SELECT `+tenantColumns+` FROM tenants WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", ErrTenantNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	return tenant, nil
+}
+
+// SetQuota replaces the tenant's quota overrides. Nil limits go back to the
+// server-wide defaults.
+func (r *TenantRepository) SetQuota(ctx context.Context, tenant *models.Tenant) error {
+	query := `
+    UPDATE tenants SET max_pending = $2, enqueue_per_minute = $3, max_running = $4, weight = $5
+    WHERE id = $1
+    RETURNING name, created_at`
+
+	err := r.db.QueryRowContext(ctx, query, tenant.ID, tenant.MaxPending, tenant.EnqueuePerMinute, tenant.MaxRunning, tenant.Weight).Scan(&tenant.Name, &tenant.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: %s", ErrTenantNotFound, tenant.ID)
+		}
+		return fmt.Errorf("failed to set tenant quota: %w", err)
+	}
+
+	return nil
+}
@@ -3,12 +3,21 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
+	"github.com/lib/pq"
+
 	"github.com/rudraprasaaad/task-scheduler/internal/database"
 	"github.com/rudraprasaaad/task-scheduler/internal/models"
 )
 
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrRoleNotFound = errors.New("role not found")
+	ErrLastAdmin    = errors.New("cannot revoke the admin role from the last admin")
+)
+
 type UserRepository struct {
 	db *database.DB
 }
@@ -17,17 +26,25 @@ func NewUserRepository(db *database.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
+// Create inserts the user with the default user role.
 func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
-	query := `INSERT INTO users (email, password_hash) VALUES ($1, $2) RETURNING id, created_at, updated_at`
+	query := `
+    WITH new_user AS (
+        INSERT INTO users (email, password_hash) VALUES ($1, $2) RETURNING id, tenant_id, created_at, updated_at
+    ), default_role AS (
+        INSERT INTO user_roles (user_id, role_id)
+        SELECT new_user.id, roles.id FROM new_user, roles WHERE roles.name = 'user'
+    )
+    SELECT id, tenant_id, created_at, updated_at FROM new_user`
 
-	return r.db.QueryRowContext(ctx, query, user.Email, user.PasswordHash).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	return r.db.QueryRowContext(ctx, query, user.Email, user.PasswordHash).Scan(&user.ID, &user.TenantID, &user.CreatedAt, &user.UpdatedAt)
 }
 
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
-	query := `SELECT id, email, password_hash, created_at, updated_at FROM users WHERE email = $1`
+	query := `SELECT id, email, password_hash, tenant_id, created_at, updated_at FROM users WHERE email = $1`
 
 	var user models.User
-	err := r.db.QueryRowContext(ctx, query, email).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
+	err := r.db.QueryRowContext(ctx, query, email).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.TenantID, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -39,3 +56,247 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.
 
 	return &user, nil
 }
+
+func (r *UserRepository) GetByID(ctx context.Context, id int64) (*models.User, error) {
+	query := `SELECT id, email, password_hash, tenant_id, created_at, updated_at FROM users WHERE id = $1`
+
+	var user models.User
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.TenantID, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("error getting user by id: %w", err)
+	}
+
+	return &user, nil
+}
+
+// GetRoles returns the names of the roles granted to a user.
+func (r *UserRepository) GetRoles(ctx context.Context, userID int64) ([]string, error) {
+	query := `
+    SELECT roles.name FROM user_roles
+    JOIN roles ON roles.id = user_roles.role_id
+    WHERE user_roles.user_id = $1
+    ORDER BY roles.name`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user roles: %w", err)
+	}
+	defer rows.Close()
+
+	roles := make([]string, 0)
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return nil, fmt.Errorf("failed to scan user role: %w", err)
+		}
+		roles = append(roles, role)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return roles, nil
+}
+
+func (r *UserRepository) ListRoles(ctx context.Context) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT name FROM roles ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+	defer rows.Close()
+
+	roles := make([]string, 0)
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return nil, fmt.Errorf("failed to scan role: %w", err)
+		}
+		roles = append(roles, role)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return roles, nil
+}
+
+// List returns users with their roles, oldest first.
+func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*models.User, error) {
+	query := `
+    SELECT users.id, users.email, users.tenant_id, users.created_at, users.updated_at,
+           COALESCE(array_agg(roles.name ORDER BY roles.name) FILTER (WHERE roles.name IS NOT NULL), '{}')
+    FROM users
+    LEFT JOIN user_roles ON user_roles.user_id = users.id
+    LEFT JOIN roles ON roles.id = user_roles.role_id
+    GROUP BY users.id
+    ORDER BY users.id
+    LIMIT $1 OFFSET $2`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]*models.User, 0)
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.ID, &user.Email, &user.TenantID, &user.CreatedAt, &user.UpdatedAt, pq.Array(&user.Roles)); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, &user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return users, nil
+}
+
+// GrantRole gives a user a role. Granting a role the user already has is not
+// an error.
+func (r *UserRepository) GrantRole(ctx context.Context, userID int64, role string) error {
+	if _, err := r.GetByID(ctx, userID); err != nil {
+		return err
+	}
+
+	var roleID int64
+	err := r.db.QueryRowContext(ctx, `SELECT id FROM roles WHERE name = $1`, role).Scan(&roleID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: %s", ErrRoleNotFound, role)
+		}
+		return fmt.Errorf("failed to look up role: %w", err)
+	}
+
+	query := `INSERT INTO user_roles (user_id, role_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	if _, err := r.db.ExecContext(ctx, query, userID, roleID); err != nil {
+		return fmt.Errorf("failed to grant role: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeRole takes a role from a user and reports whether they had it. The
+// last admin cannot lose the admin role, or nobody could grant it back.
+func (r *UserRepository) RevokeRole(ctx context.Context, userID int64, role string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the admin grants so two concurrent revokes cannot both see
+	// another admin left.
+	var admins int
+	err = tx.QueryRowContext(ctx, `
+    SELECT COUNT(*) FROM (
+        SELECT 1 FROM user_roles
+        JOIN roles ON roles.id = user_roles.role_id
+        WHERE roles.name = 'admin'
+        FOR UPDATE OF user_roles
+    ) AS admin_grants`).Scan(&admins)
+	if err != nil {
+		return false, fmt.Errorf("failed to count admins: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `
+    DELETE FROM user_roles
+    USING roles
+    WHERE roles.id = user_roles.role_id AND user_roles.user_id = $1 AND roles.name = $2`, userID, role)
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke role: %w", err)
+	}
+
+	revoked, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if role == "admin" && revoked > 0 && admins <= 1 {
+		return false, ErrLastAdmin
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit role revocation: %w", err)
+	}
+
+	return revoked > 0, nil
+}
+
+// BootstrapAdmin grants the admin role to the user with exactly this email,
+// but only while nobody holds it, so it can neither add a second admin nor
+// hand back a role an operator took away. It reports whether it granted it.
+func (r *UserRepository) BootstrapAdmin(ctx context.Context, email string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// With no admin grants there is no row to lock, so hold off other role
+	// writes until this one commits.
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE user_roles IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return false, fmt.Errorf("failed to lock user roles: %w", err)
+	}
+
+	var admins int
+	err = tx.QueryRowContext(ctx, `
+    SELECT COUNT(*) FROM user_roles
+    JOIN roles ON roles.id = user_roles.role_id
+    WHERE roles.name = 'admin'`).Scan(&admins)
+	if err != nil {
+		return false, fmt.Errorf("failed to count admins: %w", err)
+	}
+	if admins > 0 {
+		return false, nil
+	}
+
+	result, err := tx.ExecContext(ctx, `
+    INSERT INTO user_roles (user_id, role_id)
+    SELECT users.id, roles.id FROM users, roles
+    WHERE users.email = $1 AND roles.name = 'admin'`, email)
+	if err != nil {
+		return false, fmt.Errorf("failed to grant admin role: %w", err)
+	}
+
+	granted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit admin bootstrap: %w", err)
+	}
+
+	return granted > 0, nil
+}
+
+// SetTenant moves a user to another tenant. Tasks the user already created
+// stay with their old tenant.
+func (r *UserRepository) SetTenant(ctx context.Context, userID int64, tenantID string) error {
+	if _, err := r.GetByID(ctx, userID); err != nil {
+		return err
+	}
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM tenants WHERE id = $1)`, tenantID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to look up tenant: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrTenantNotFound, tenantID)
+	}
+
+	if _, err := r.db.ExecContext(ctx, `UPDATE users SET tenant_id = $2, updated_at = NOW() WHERE id = $1`, userID, tenantID); err != nil {
+		return fmt.Errorf("failed to set user tenant: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestBootstrapAdminOnlyWhileNoAdminExists(t *testing.T) {
+	db, mock := newMockDB(t)
+	users := NewUserRepository(db)
+
+	// Once anyone is an admin, including after the bootstrap admin's role
+	// was revoked and given to someone else, nothing is granted.
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`LOCK TABLE user_roles`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM user_roles`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	granted, err := users.BootstrapAdmin(context.Background(), "admin@example.com")
+	if err != nil || granted {
+		t.Fatalf("got %v, %v with an admin present, want nothing granted", granted, err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`LOCK TABLE user_roles`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM user_roles`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(regexp.QuoteMeta(`WHERE users.email = $1 AND roles.name = 'admin'`)).
+		WithArgs("admin@example.com").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	granted, err = users.BootstrapAdmin(context.Background(), "admin@example.com")
+	if err != nil || !granted {
+		t.Fatalf("got %v, %v with no admin, want the role granted", granted, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/database"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+)
+
+type WorkerCredentialRepository struct {
+	db *database.DB
+}
+
+func NewWorkerCredentialRepository(db *database.DB) *WorkerCredentialRepository {
+	return &WorkerCredentialRepository{db: db}
+}
+
+func (r *WorkerCredentialRepository) CreateEnrollmentToken(ctx context.Context, token *models.EnrollmentToken) error {
+	query := `INSERT INTO worker_enrollment_tokens (id, token_hash, worker_id, created_by, created_at, expires_at) VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5, $6)`
+
+	_, err := r.db.ExecContext(ctx, query, token.ID, token.TokenHash, token.WorkerID, token.CreatedBy, token.CreatedAt, token.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create enrollment token: %w", err)
+	}
+
+	return nil
+}
+
+// GetUsableEnrollmentToken returns an unused, unexpired token by hash.
+func (r *WorkerCredentialRepository) GetUsableEnrollmentToken(ctx context.Context, tokenHash string) (*models.EnrollmentToken, error) {
+	query := `
+    SELECT id, token_hash, worker_id, created_by, created_at, expires_at
+    FROM worker_enrollment_tokens
+    WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()`
+
+	var token models.EnrollmentToken
+	var workerID, createdBy sql.NullString
+
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(&token.ID, &token.TokenHash, &workerID, &createdBy, &token.CreatedAt, &token.ExpiresAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("enrollment token not found or already used")
+		}
+		return nil, fmt.Errorf("failed to get enrollment token: %w", err)
+	}
+
+	token.WorkerID = workerID.String
+	token.CreatedBy = createdBy.String
+
+	return &token, nil
+}
+
+// Enroll consumes the enrollment token, revokes any credentials the worker
+// already holds and stores the new one, all in one transaction so a token can
+// only ever be exchanged once.
+func (r *WorkerCredentialRepository) Enroll(ctx context.Context, tokenID string, credential *models.WorkerCredential) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+    UPDATE worker_enrollment_tokens SET used_at = NOW(), used_by = $2
+    WHERE id = $1 AND used_at IS NULL AND expires_at > NOW()`, tokenID, credential.WorkerID)
+	if err != nil {
+		return fmt.Errorf("failed to consume enrollment token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("enrollment token not found or already used")
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE worker_credentials SET revoked_at = NOW() WHERE worker_id = $1 AND revoked_at IS NULL`, credential.WorkerID); err != nil {
+		return fmt.Errorf("failed to revoke previous credentials: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO worker_credentials (id, worker_id, secret_hash, created_at) VALUES ($1, $2, $3, $4)`,
+		credential.ID, credential.WorkerID, credential.SecretHash, credential.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create worker credential: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (r *WorkerCredentialRepository) HasActiveCredential(ctx context.Context, workerID string) (bool, error) {
+	var exists bool
+
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM worker_credentials WHERE worker_id = $1 AND revoked_at IS NULL)`, workerID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check worker credentials: %w", err)
+	}
+
+	return exists, nil
+}
+
+// GetActiveCredential loads a credential that has not been revoked.
+func (r *WorkerCredentialRepository) GetActiveCredential(ctx context.Context, id string) (*models.WorkerCredential, error) {
+	query := `
+    SELECT id, worker_id, secret_hash, created_at, last_used_at
+    FROM worker_credentials
+    WHERE id = $1 AND revoked_at IS NULL`
+
+	var credential models.WorkerCredential
+	var lastUsedAt sql.NullTime
+
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&credential.ID, &credential.WorkerID, &credential.SecretHash, &credential.CreatedAt, &lastUsedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("worker credential not found or revoked")
+		}
+		return nil, fmt.Errorf("failed to load worker credential: %w", err)
+	}
+
+	if lastUsedAt.Valid {
+		credential.LastUsedAt = &lastUsedAt.Time
+	}
+
+	return &credential, nil
+}
+
+// MarkUsed records that the credential authenticated a call. Like API keys,
+// the timestamp is only written once a minute.
+func (r *WorkerCredentialRepository) MarkUsed(ctx context.Context, id string) error {
+	query := `
+    UPDATE worker_credentials SET last_used_at = NOW()
+    WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')`
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to record worker credential use: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WorkerCredentialRepository) RevokeWorker(ctx context.Context, workerID string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `UPDATE worker_credentials SET revoked_at = NOW() WHERE worker_id = $1 AND revoked_at IS NULL`, workerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke worker credentials: %w", err)
+	}
+
+	return result.RowsAffected()
+}
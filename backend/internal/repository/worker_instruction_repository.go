@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rudraprasaaad/task-scheduler/internal/database"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+)
+
+type WorkerInstructionRepository struct {
+	db *database.DB
+}
+
+func NewWorkerInstructionRepository(db *database.DB) *WorkerInstructionRepository {
+	return &WorkerInstructionRepository{db: db}
+}
+
+const instructionColumns = `id, worker_id, type, params, status, error, created_by, created_at, delivered_at, acknowledged_at, applied_at`
+
+func scanInstruction(row rowScanner) (*models.WorkerInstruction, error) {
+	var instruction models.WorkerInstruction
+	var paramsJSON []byte
+	var errorMsg, createdBy sql.NullString
+	var deliveredAt, acknowledgedAt, appliedAt sql.NullTime
+
+	err := row.Scan(&instruction.ID, &instruction.WorkerID, &instruction.Type, &paramsJSON, &instruction.Status, &errorMsg, &createdBy, &instruction.CreatedAt, &deliveredAt, &acknowledgedAt, &appliedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(paramsJSON, &instruction.Params); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal instruction params: %w", err)
+	}
+
+	instruction.Error = errorMsg.String
+	instruction.CreatedBy = createdBy.String
+
+	if deliveredAt.Valid {
+		instruction.DeliveredAt = &deliveredAt.Time
+	}
+	if acknowledgedAt.Valid {
+		instruction.AcknowledgedAt = &acknowledgedAt.Time
+	}
+	if appliedAt.Valid {
+		instruction.AppliedAt = &appliedAt.Time
+	}
+
+	return &instruction, nil
+}
+
+func (r *WorkerInstructionRepository) Create(ctx context.Context, instruction *models.WorkerInstruction) error {
+	paramsJSON, err := json.Marshal(instruction.Params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal instruction params: %w", err)
+	}
+
+	query := `INSERT INTO worker_instructions (id, worker_id, type, params, status, created_by, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err = r.db.ExecContext(ctx, query, instruction.ID, instruction.WorkerID, instruction.Type, paramsJSON, instruction.Status, instruction.CreatedBy, instruction.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create worker instruction: %w", err)
+	}
+
+	return nil
+}
+
+// instructionRedeliveryDelay is how long a delivered instruction may go
+// unacknowledged before it is handed out again, in case the send was lost.
+const instructionRedeliveryDelay = 2 * time.Minute
+
+// TakeUndelivered returns the instructions a worker has not been sent yet,
+// oldest first, and marks them as delivered. Each is handed out once; only if
+// the worker has not acknowledged it within instructionRedeliveryDelay is it
+// handed out again, so workers must still treat instruction IDs idempotently.
+func (r *WorkerInstructionRepository) TakeUndelivered(ctx context.Context, workerID string) ([]*models.WorkerInstruction, error) {
+	query := `
+    WITH taken AS (
+        UPDATE worker_instructions
+        SET status = 'delivered', delivered_at = NOW()
+        WHERE worker_id = $1
+          AND (status = 'pending' OR (status = 'delivered' AND delivered_at < NOW() - make_interval(secs => $2)))
+        RETURNING ` + instructionColumns + `
+    )
+    SELECT ` + instructionColumns + ` FROM taken ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, workerID, instructionRedeliveryDelay.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to take worker instructions: %w", err)
+	}
+	defer rows.Close()
+
+	var instructions []*models.WorkerInstruction
+	for rows.Next() {
+		instruction, err := scanInstruction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan worker instruction: %w", err)
+		}
+		instructions = append(instructions, instruction)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return instructions, nil
+}
+
+// Acknowledge records a worker's report on an instruction. Only the worker the
+// instruction was addressed to can move it forward, and applied or failed
+// instructions are final.
+func (r *WorkerInstructionRepository) Acknowledge(ctx context.Context, id, workerID string, status models.InstructionStatus, errorMsg string) error {
+	now := time.Now()
+	var appliedAt *time.Time
+	if status == models.InstructionStatusApplied || status == models.InstructionStatusFailed {
+		appliedAt = &now
+	}
+
+	query := `
+    UPDATE worker_instructions
+    SET status = $3, error = NULLIF($4, ''), acknowledged_at = COALESCE(acknowledged_at, $5), applied_at = $6
+    WHERE id = $1 AND worker_id = $2 AND status NOT IN ('applied', 'failed')`
+
+	result, err := r.db.ExecContext(ctx, query, id, workerID, status, errorMsg, now, appliedAt)
+	if err != nil {
+		return fmt.Errorf("failed to acknowledge worker instruction: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("no open instruction %s for worker %s", id, workerID)
+	}
+
+	return nil
+}
+
+func (r *WorkerInstructionRepository) ListByWorker(ctx context.Context, workerID string, statuses []string, limit int) ([]*models.WorkerInstruction, error) {
+	query := `
+    SELECT ` + instructionColumns + `
+    FROM worker_instructions
+    WHERE worker_id = $1 AND (cardinality($2::text[]) = 0 OR status = ANY($2::text[]))
+    ORDER BY created_at DESC
+    LIMIT $3`
+
+	rows, err := r.db.QueryContext(ctx, query, workerID, pq.Array(statuses), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worker instructions: %w", err)
+	}
+	defer rows.Close()
+
+	instructions := make([]*models.WorkerInstruction, 0)
+	for rows.Next() {
+		instruction, err := scanInstruction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan worker instruction: %w", err)
+		}
+		instructions = append(instructions, instruction)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return instructions, nil
+}
@@ -3,10 +3,12 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/rudraprasaaad/task-scheduler/internal/database"
 	"github.com/rudraprasaaad/task-scheduler/internal/models"
 )
@@ -19,15 +21,61 @@ func NewWorkerRepository(db *database.DB) *WorkerRepository {
 	return &WorkerRepository{db: db}
 }
 
+const workerColumns = `id, status, last_seen, tasks_run, labels, task_types, max_concurrent_tasks, current_tasks`
+
+func scanWorker(row rowScanner) (*models.Worker, error) {
+	var worker models.Worker
+	var labelsJSON []byte
+
+	err := row.Scan(
+		&worker.ID,
+		&worker.Status,
+		&worker.LastSeen,
+		&worker.TasksRun,
+		&labelsJSON,
+		pq.Array(&worker.TaskTypes),
+		&worker.MaxConcurrentTasks,
+		&worker.CurrentTasks,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(labelsJSON, &worker.Labels); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal worker labels: %w", err)
+	}
+
+	return &worker, nil
+}
+
 func (r *WorkerRepository) Register(ctx context.Context, worker *models.Worker) error {
+	labels := worker.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	labelsJSON, err := json.Marshal(labels)
+	if err != nil {
+		return fmt.Errorf("failed to marshal worker labels: %w", err)
+	}
+
 	query := `
-    INSERT INTO workers (id, status, last_seen, tasks_run, created_at)
-    VALUES ($1, $2, $3, $4, $5)
+    INSERT INTO workers (id, status, last_seen, tasks_run, created_at, labels, task_types, max_concurrent_tasks, current_tasks)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
     ON CONFLICT (id) DO UPDATE SET
         status = EXCLUDED.status,
-        last_seen = EXCLUDED.last_seen`
+        last_seen = EXCLUDED.last_seen,
+        labels = EXCLUDED.labels,
+        task_types = EXCLUDED.task_types,
+        max_concurrent_tasks = EXCLUDED.max_concurrent_tasks,
+        current_tasks = EXCLUDED.current_tasks`
+
+	maxConcurrent := worker.MaxConcurrentTasks
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
 
-	_, err := r.db.ExecContext(ctx, query, worker.ID, worker.Status, worker.LastSeen, worker.TasksRun, time.Now())
+	_, err = r.db.ExecContext(ctx, query, worker.ID, worker.Status, worker.LastSeen, worker.TasksRun, time.Now(), labelsJSON, pq.Array(worker.TaskTypes), maxConcurrent, worker.CurrentTasks)
 
 	if err != nil {
 		return fmt.Errorf("failed to register worker: %w", err)
@@ -51,8 +99,23 @@ func (r *WorkerRepository) UpdateStatus(ctx context.Context, workerID string, st
 	return nil
 }
 
+func (r *WorkerRepository) UpdateLoad(ctx context.Context, workerID string, status models.WorkerStatus, currentTasks int) error {
+	query := `
+    UPDATE workers 
+    SET status = $2, current_tasks = $3, last_seen = $4
+    WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, workerID, status, currentTasks, time.Now())
+
+	if err != nil {
+		return fmt.Errorf("failed to update worker load: %w", err)
+	}
+
+	return nil
+}
+
 func (r *WorkerRepository) IncrementTaskCount(ctx context.Context, workerID string) error {
-	query := `UDPATE workers SET tasks_run = tasks_run + 1, last_seen = $2 WHERE id = $1`
+	query := `UPDATE workers SET tasks_run = tasks_run + 1, last_seen = $2 WHERE id = $1`
 
 	_, err := r.db.ExecContext(ctx, query, workerID, time.Now())
 
@@ -64,19 +127,9 @@ func (r *WorkerRepository) IncrementTaskCount(ctx context.Context, workerID stri
 }
 
 func (r *WorkerRepository) GetByID(ctx context.Context, id string) (*models.Worker, error) {
-	query := `SELECT id, status, last_seen, tasks_run FROM workers WHERE id = $1`
+	query := `SELECT ` + workerColumns + ` FROM workers WHERE id = $1`
 
-	row := r.db.QueryRowContext(ctx, query, id)
-
-	var worker models.Worker
-
-	err := row.Scan(
-		&worker.ID,
-		&worker.Status,
-		&worker.LastSeen,
-		&worker.LastSeen,
-		&worker.TasksRun,
-	)
+	worker, err := scanWorker(r.db.QueryRowContext(ctx, query, id))
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -86,12 +139,12 @@ func (r *WorkerRepository) GetByID(ctx context.Context, id string) (*models.Work
 		return nil, fmt.Errorf("error scanning worker data %w", err)
 	}
 
-	return &worker, nil
+	return worker, nil
 }
 
 func (r *WorkerRepository) GetAll(ctx context.Context) ([]*models.Worker, error) {
 	query := `
-    SELECT id, status, last_seen, tasks_run
+    SELECT ` + workerColumns + `
     FROM workers
     ORDER BY created_at`
 
@@ -104,14 +157,12 @@ func (r *WorkerRepository) GetAll(ctx context.Context) ([]*models.Worker, error)
 	var workers []*models.Worker
 
 	for rows.Next() {
-		var w models.Worker
-
-		err := rows.Scan(&w.ID, &w.Status, &w.LastSeen, &w.TasksRun)
+		w, err := scanWorker(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan worker: %w", err)
 		}
 
-		workers = append(workers, &w)
+		workers = append(workers, w)
 	}
 
 	return workers, nil
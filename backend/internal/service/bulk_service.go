@@ -0,0 +1,369 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/audit"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+	"github.com/rudraprasaaad/task-scheduler/internal/repository"
+)
+
+var (
+	ErrBulkJobNotFound = errors.New("bulk job not found")
+	ErrBulkQueueFull   = errors.New("too many bulk jobs waiting, try again later")
+)
+
+const (
+	maxBulkItems       = 100000
+	maxItemsPageSize   = 5000
+	bulkBatchSize      = 100
+	bulkQueueSize      = 100
+	bulkItemTimeout    = 5 * time.Second
+	bulkResolveTimeout = time.Minute
+)
+
+type BulkRequest struct {
+	Operation models.BulkOperation
+
+	// Tasks are the tasks to create. Every other operation selects existing
+	// tasks by TaskIDs or by Filter, exactly one of which must be set.
+	Tasks   []CreateTaskInput
+	TaskIDs []string
+	Filter  *ListTasksInput
+
+	// FilterQuery is the filter as the caller wrote it, kept on the job so
+	// the report shows what was selected.
+	FilterQuery string
+
+	Priority    *models.TaskPriority
+	ScheduledAt *time.Time
+	CreatedBy   string
+}
+
+type bulkRun struct {
+	job *models.BulkJob
+	req *BulkRequest
+
+	// actorType and actorID are who submitted the job, so each task it
+	// touches is recorded against them.
+	actorType string
+	actorID   string
+}
+
+// bulkItemActions are the audit actions recorded for each task a bulk
+// operation touches, the same ones as for the single-task calls.
+var bulkItemActions = map[models.BulkOperation]string{
+	models.BulkCreate:       "task.create",
+	models.BulkCancel:       "task.cancel",
+	models.BulkRetry:        "task.retry",
+	models.BulkReprioritize: "task.update",
+	models.BulkReschedule:   "task.update",
+	models.BulkDelete:       "task.delete",
+}
+
+// BulkService runs bulk task operations as background jobs, one at a time,
+// recording progress and a per-item report as it goes.
+type BulkService struct {
+	tasks   *TaskService
+	jobRepo *repository.BulkJobRepository
+	audit   audit.Recorder
+	pending chan *bulkRun
+}
+
+func NewBulkService(tasks *TaskService, jobRepo *repository.BulkJobRepository, recorder audit.Recorder) *BulkService {
+	return &BulkService{
+		tasks:   tasks,
+		jobRepo: jobRepo,
+		audit:   recorder,
+		pending: make(chan *bulkRun, bulkQueueSize),
+	}
+}
+
+// Run executes submitted jobs until ctx is done. Jobs left over from a
+// previous process are marked failed first, since nothing will resume them.
+func (s *BulkService) Run(ctx context.Context) {
+	if n, err := s.jobRepo.FailInterrupted(ctx); err != nil {
+		log.Printf("ERROR: %v", err)
+	} else if n > 0 {
+		log.Printf("Marked %d interrupted bulk jobs as failed", n)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case run := <-s.pending:
+			s.execute(ctx, run)
+		}
+	}
+}
+
+func (s *BulkService) Submit(ctx context.Context, req BulkRequest) (*models.BulkJob, error) {
+	params, err := validateBulkRequest(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	job := models.NewBulkJob(req.Operation, params)
+	job.CreatedBy = req.CreatedBy
+	job.TenantID = tenantFrom(ctx)
+
+	if err := s.jobRepo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	run := &bulkRun{job: job, req: &req, actorType: models.ActorUser, actorID: req.CreatedBy}
+	if event := audit.FromContext(ctx); event != nil {
+		run.actorType, run.actorID = event.ActorType, event.ActorID
+	}
+
+	select {
+	case s.pending <- run:
+	default:
+		if err := s.jobRepo.Finish(ctx, job.ID, models.BulkJobFailed, ErrBulkQueueFull.Error()); err != nil {
+			log.Printf("ERROR: %v", err)
+		}
+		return nil, ErrBulkQueueFull
+	}
+
+	audit.SetTarget(ctx, "bulk_job", job.ID)
+
+	log.Printf("Bulk %s job %s submitted", job.Operation, job.ID)
+	return job, nil
+}
+
+func validateBulkRequest(req *BulkRequest) (map[string]interface{}, error) {
+	params := make(map[string]interface{})
+
+	if req.Operation == models.BulkCreate {
+		if len(req.Tasks) == 0 {
+			return nil, fmt.Errorf("%w: create needs at least one task", ErrInvalidTask)
+		}
+		if len(req.TaskIDs) > 0 || req.Filter != nil {
+			return nil, fmt.Errorf("%w: create does not take task_ids or a filter", ErrInvalidTask)
+		}
+		if len(req.Tasks) > maxBulkItems {
+			return nil, fmt.Errorf("%w: at most %d tasks per bulk job", ErrInvalidTask, maxBulkItems)
+		}
+		params["count"] = len(req.Tasks)
+		return params, nil
+	}
+
+	switch req.Operation {
+	case models.BulkCancel, models.BulkRetry, models.BulkDelete:
+	case models.BulkReprioritize:
+		if req.Priority == nil || *req.Priority < minTaskPriority || *req.Priority > maxTaskPriority {
+			return nil, fmt.Errorf("%w: reprioritize needs a priority between %d and %d", ErrInvalidTask, minTaskPriority, maxTaskPriority)
+		}
+		params["priority"] = *req.Priority
+	case models.BulkReschedule:
+		if req.ScheduledAt == nil || req.ScheduledAt.IsZero() {
+			return nil, fmt.Errorf("%w: reschedule needs scheduled_at", ErrInvalidTask)
+		}
+		params["scheduled_at"] = req.ScheduledAt
+	default:
+		return nil, fmt.Errorf("%w: unknown bulk operation %q", ErrInvalidTask, req.Operation)
+	}
+
+	if (len(req.TaskIDs) > 0) == (req.Filter != nil) {
+		return nil, fmt.Errorf("%w: select tasks with either task_ids or a filter", ErrInvalidTask)
+	}
+	if len(req.TaskIDs) > maxBulkItems {
+		return nil, fmt.Errorf("%w: at most %d tasks per bulk job", ErrInvalidTask, maxBulkItems)
+	}
+
+	if req.Filter != nil {
+		params["filter"] = req.FilterQuery
+	} else {
+		params["count"] = len(req.TaskIDs)
+	}
+
+	return params, nil
+}
+
+// execute runs a job in the tenant scope it was submitted with, so a job can
+// only touch the tasks its submitter could.
+func (s *BulkService) execute(ctx context.Context, run *bulkRun) {
+	job, req := run.job, run.req
+	ctx = WithTenant(ctx, job.TenantID)
+
+	// Progress writes use their own context so a shutdown can still record
+	// how far the job got.
+	record := func(fn func(context.Context) error) {
+		recordCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := fn(recordCtx); err != nil {
+			log.Printf("ERROR: bulk job %s: %v", job.ID, err)
+		}
+	}
+
+	taskIDs := req.TaskIDs
+	if req.Filter != nil {
+		var err error
+		taskIDs, err = s.resolveFilter(ctx, *req.Filter)
+		if err != nil {
+			record(func(c context.Context) error { return s.jobRepo.Finish(c, job.ID, models.BulkJobFailed, err.Error()) })
+			return
+		}
+	}
+
+	total := len(taskIDs)
+	if job.Operation == models.BulkCreate {
+		total = len(req.Tasks)
+	}
+	record(func(c context.Context) error { return s.jobRepo.Start(c, job.ID, total) })
+
+	batch := make([]*models.BulkJobItem, 0, bulkBatchSize)
+	flush := func() {
+		record(func(c context.Context) error { return s.jobRepo.RecordItems(c, job.ID, batch) })
+		batch = batch[:0]
+	}
+
+	for i := 0; i < total; i++ {
+		if ctx.Err() != nil {
+			flush()
+			record(func(c context.Context) error {
+				return s.jobRepo.Finish(c, job.ID, models.BulkJobFailed, "interrupted by server shutdown")
+			})
+			return
+		}
+
+		item := &models.BulkJobItem{JobID: job.ID, Index: i}
+		if job.Operation != models.BulkCreate {
+			item.TaskID = taskIDs[i]
+		}
+
+		event := &models.AuditEvent{
+			ActorType:  run.actorType,
+			ActorID:    run.actorID,
+			TenantID:   job.TenantID,
+			Action:     bulkItemActions[job.Operation],
+			TargetType: "task",
+			Details:    map[string]interface{}{"bulk_job_id": job.ID, "index": i},
+		}
+
+		itemCtx, cancel := context.WithTimeout(audit.NewContext(ctx, event), bulkItemTimeout)
+		taskID, err := s.apply(itemCtx, req, i, item.TaskID)
+		cancel()
+
+		event.TargetID = taskID
+		event.Outcome = models.AuditSuccess
+		if err != nil {
+			event.Outcome = models.AuditFailure
+			event.Details["error"] = err.Error()
+		}
+		s.audit.Record(ctx, event)
+
+		item.TaskID = taskID
+		item.OK = err == nil
+		if err != nil {
+			item.Error = err.Error()
+		}
+
+		batch = append(batch, item)
+		if len(batch) == bulkBatchSize {
+			flush()
+		}
+	}
+
+	flush()
+	record(func(c context.Context) error { return s.jobRepo.Finish(c, job.ID, models.BulkJobCompleted, "") })
+	log.Printf("Bulk %s job %s finished with %d items", job.Operation, job.ID, total)
+}
+
+// apply runs the job's operation on one item and returns the task it touched.
+func (s *BulkService) apply(ctx context.Context, req *BulkRequest, index int, taskID string) (string, error) {
+	var err error
+
+	switch req.Operation {
+	case models.BulkCreate:
+		var task *models.Task
+		task, err = s.tasks.Create(ctx, req.Tasks[index])
+		if task != nil {
+			taskID = task.ID
+		}
+	case models.BulkCancel:
+		_, err = s.tasks.Cancel(ctx, taskID)
+	case models.BulkRetry:
+		_, err = s.tasks.Retry(ctx, taskID, RetryOptions{ResetRetries: true})
+	case models.BulkReprioritize:
+		_, err = s.tasks.Update(ctx, taskID, TaskUpdate{Priority: req.Priority})
+	case models.BulkReschedule:
+		_, err = s.tasks.Update(ctx, taskID, TaskUpdate{ScheduledAt: req.ScheduledAt})
+	case models.BulkDelete:
+		err = s.tasks.Delete(ctx, taskID)
+	}
+
+	return taskID, err
+}
+
+// resolveFilter snapshots the IDs of every task matching the filter before
+// any of them are changed, so operations that move tasks out of the filter do
+// not disturb the paging.
+func (s *BulkService) resolveFilter(ctx context.Context, filter ListTasksInput) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, bulkResolveTimeout)
+	defer cancel()
+
+	filter.Limit = maxListLimit
+	filter.Offset = 0
+	filter.PageToken = ""
+
+	var ids []string
+	for {
+		tasks, nextPageToken, err := s.tasks.List(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve filter: %w", err)
+		}
+
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+
+		if len(ids) > maxBulkItems {
+			return nil, fmt.Errorf("filter matches more than %d tasks", maxBulkItems)
+		}
+
+		if nextPageToken == "" {
+			return ids, nil
+		}
+		filter.PageToken = nextPageToken
+	}
+}
+
+func (s *BulkService) GetJob(ctx context.Context, id string) (*models.BulkJob, error) {
+	job, err := s.jobRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBulkJobNotFound, id)
+	}
+
+	if scope := tenantFrom(ctx); scope != "" && job.TenantID != scope {
+		return nil, fmt.Errorf("%w: %s", ErrBulkJobNotFound, id)
+	}
+
+	return job, nil
+}
+
+func (s *BulkService) ListJobs(ctx context.Context, limit int) ([]*models.BulkJob, error) {
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	return s.jobRepo.List(ctx, tenantFrom(ctx), min(limit, maxListLimit))
+}
+
+// Items returns a page of the job's per-item report.
+func (s *BulkService) Items(ctx context.Context, jobID string, failedOnly bool, limit, offset int) ([]*models.BulkJobItem, error) {
+	if _, err := s.GetJob(ctx, jobID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	return s.jobRepo.ListItems(ctx, jobID, failedOnly, min(limit, maxItemsPageSize), max(offset, 0))
+}
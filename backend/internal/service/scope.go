@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/auth"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+)
+
+type tenantKey struct{}
+
+// WithTenant limits the task operations run with ctx to one tenant's tasks.
+// Contexts without a tenant, as used by workers, cron and admins, see every
+// task.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	if tenantID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+func tenantFrom(ctx context.Context) string {
+	tenantID, _ := ctx.Value(tenantKey{}).(string)
+	return tenantID
+}
+
+// TenantScope returns the tenant a caller is limited to, or "" for callers
+// allowed to see every tenant.
+func TenantScope(claims *auth.Claims) string {
+	if claims.Can(auth.PermTaskAllTenants) {
+		return ""
+	}
+	return CallerTenant(claims)
+}
+
+// CallerTenant returns the tenant the caller belongs to, which owns the tasks
+// they create. Tokens issued before tenants existed belong to the default one.
+func CallerTenant(claims *auth.Claims) string {
+	if claims.TenantID == "" {
+		return models.DefaultTenantID
+	}
+	return claims.TenantID
+}
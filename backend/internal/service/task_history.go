@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+)
+
+type TaskHistory struct {
+	Task       *models.Task            `json:"task"`
+	Executions []*models.TaskExecution `json:"executions"`
+	Timeline   []*models.TimelineEvent `json:"timeline"`
+}
+
+// Executions returns every recorded attempt of a task, oldest first.
+func (s *TaskService) Executions(ctx context.Context, id string) ([]*models.TaskExecution, error) {
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.execRepo.ListByTaskID(ctx, id)
+}
+
+// History returns a task with its attempts and a timeline merging the two.
+func (s *TaskService) History(ctx context.Context, id string) (*TaskHistory, error) {
+	task, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	executions, err := s.execRepo.ListByTaskID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TaskHistory{
+		Task:       task,
+		Executions: executions,
+		Timeline:   buildTimeline(task, executions),
+	}, nil
+}
+
+// buildTimeline orders the task's recorded events. Executions are only
+// written once an attempt ends, so a running attempt and the next scheduled
+// run come from the task row itself.
+func buildTimeline(task *models.Task, executions []*models.TaskExecution) []*models.TimelineEvent {
+	timeline := []*models.TimelineEvent{{Type: models.TimelineCreated, At: task.CreatedAt}}
+
+	for i, execution := range executions {
+		timeline = append(timeline,
+			&models.TimelineEvent{
+				Type:     models.TimelineAttemptStarted,
+				At:       execution.StartedAt,
+				Attempt:  execution.Attempt,
+				WorkerID: execution.WorkerID,
+			},
+			&models.TimelineEvent{
+				Type:       models.TimelineAttemptFinished,
+				At:         execution.CompletedAt,
+				Attempt:    execution.Attempt,
+				WorkerID:   execution.WorkerID,
+				Status:     execution.Status,
+				DurationMs: execution.ExecutionTimeMs,
+				Error:      execution.Error,
+			})
+
+		// A failed attempt followed by another attempt, or by a task still
+		// waiting to run, was retried.
+		retried := i < len(executions)-1 || task.Status == models.TaskStatusPending || task.Status == models.TaskStatusRunning
+		if execution.Status == string(models.TaskStatusFailed) && retried {
+			timeline = append(timeline, &models.TimelineEvent{
+				Type:    models.TimelineRetry,
+				At:      execution.CompletedAt,
+				Attempt: execution.Attempt,
+			})
+		}
+	}
+
+	nextAttempt := len(executions) + 1
+
+	switch task.Status {
+	case models.TaskStatusPending:
+		timeline = append(timeline, &models.TimelineEvent{
+			Type:    models.TimelineScheduled,
+			At:      task.ScheduledAt,
+			Attempt: nextAttempt,
+		})
+	case models.TaskStatusRunning:
+		at := task.UpdatedAt
+		if task.StartedAt != nil {
+			at = *task.StartedAt
+		}
+		timeline = append(timeline, &models.TimelineEvent{
+			Type:     models.TimelineRunning,
+			At:       at,
+			Attempt:  nextAttempt,
+			WorkerID: task.WorkerID,
+		})
+	case models.TaskStatusCompleted, models.TaskStatusFailed, models.TaskStatusCancelled:
+		at := task.UpdatedAt
+		if task.CompletedAt != nil {
+			at = *task.CompletedAt
+		}
+		timeline = append(timeline, &models.TimelineEvent{
+			Type:  models.TimelineEventType(task.Status),
+			At:    at,
+			Error: task.Error,
+		})
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].At.Before(timeline[j].At)
+	})
+
+	return timeline
+}
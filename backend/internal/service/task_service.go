@@ -0,0 +1,680 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/audit"
+	"github.com/rudraprasaaad/task-scheduler/internal/cache"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+	"github.com/rudraprasaaad/task-scheduler/internal/queue"
+	"github.com/rudraprasaaad/task-scheduler/internal/quota"
+	"github.com/rudraprasaaad/task-scheduler/internal/repository"
+)
+
+var (
+	ErrTaskNotFound   = errors.New("task not found")
+	ErrInvalidTask    = errors.New("invalid task")
+	ErrInvalidState   = errors.New("invalid task state")
+	ErrNotLeaseHolder = errors.New("task is not leased to this worker")
+)
+
+// TaskService holds the task operations shared by the REST handlers and the
+// gRPC server, so both transports apply the same validation and keep the
+// database and the Redis queue in step.
+type TaskService struct {
+	taskRepo *repository.TaskRepository
+	execRepo *repository.TaskExecutionRepository
+	queue    *queue.RedisQueue
+	cache    *cache.RedisCache
+	quotas   *quota.Manager
+}
+
+func NewTaskService(taskRepo *repository.TaskRepository, execRepo *repository.TaskExecutionRepository, queue *queue.RedisQueue, cache *cache.RedisCache, quotas *quota.Manager) *TaskService {
+	return &TaskService{
+		taskRepo: taskRepo,
+		execRepo: execRepo,
+		queue:    queue,
+		cache:    cache,
+		quotas:   quotas,
+	}
+}
+
+type CreateTaskInput struct {
+	// TenantID owns the task and CreatedBy is the user who created it. A
+	// context limited to a tenant always creates in that tenant.
+	TenantID  string
+	CreatedBy string
+
+	Name       string
+	Type       string
+	Payload    map[string]interface{}
+	Priority   models.TaskPriority
+	ScheduleAt *time.Time
+	MaxRetries int
+	Selector   map[string]string
+}
+
+func (s *TaskService) Create(ctx context.Context, input CreateTaskInput) (*models.Task, error) {
+	if input.Name == "" || input.Type == "" {
+		return nil, fmt.Errorf("%w: name and type are required", ErrInvalidTask)
+	}
+
+	if input.Priority < 0 || input.MaxRetries < 0 {
+		return nil, fmt.Errorf("%w: priority and max_retries cannot be negative", ErrInvalidTask)
+	}
+
+	task := models.NewTask(input.Name, input.Type, input.Payload)
+
+	if input.Priority > 0 {
+		task.Priority = input.Priority
+	}
+
+	if input.ScheduleAt != nil && !input.ScheduleAt.IsZero() {
+		task.ScheduledAt = *input.ScheduleAt
+	}
+
+	if input.MaxRetries > 0 {
+		task.MaxRetries = input.MaxRetries
+	}
+
+	task.Selector = input.Selector
+	task.CreatedBy = input.CreatedBy
+	task.TenantID = input.TenantID
+	if scope := tenantFrom(ctx); scope != "" {
+		task.TenantID = scope
+	}
+	if task.TenantID == "" {
+		task.TenantID = models.DefaultTenantID
+	}
+
+	if err := s.admit(ctx, task.TenantID); err != nil {
+		return nil, err
+	}
+
+	if err := s.taskRepo.Create(ctx, task); err != nil {
+		return nil, err
+	}
+
+	if err := s.queue.Enqueue(task); err != nil {
+		if delErr := s.taskRepo.Delete(ctx, task.ID); delErr != nil {
+			log.Printf("ERROR: Failed to roll back task %s after enqueue failure: %v", task.ID, delErr)
+		}
+		return nil, err
+	}
+
+	audit.SetTarget(ctx, "task", task.ID)
+	audit.SetChange(ctx, nil, task)
+
+	return task, nil
+}
+
+// admit checks a new task against its tenant's pending and enqueue rate
+// limits. Failures are a *quota.ExceededError carrying a retry hint.
+func (s *TaskService) admit(ctx context.Context, tenantID string) error {
+	limits := s.quotas.Limits(ctx, tenantID)
+
+	if limits.MaxPending > 0 {
+		pending, err := s.queue.PendingCount(ctx, tenantID)
+		if err != nil {
+			return err
+		}
+		if pending >= limits.MaxPending {
+			return &quota.ExceededError{
+				TenantID:   tenantID,
+				Limit:      "max_pending",
+				Max:        limits.MaxPending,
+				RetryAfter: quota.PendingRetryAfter,
+			}
+		}
+	}
+
+	return s.quotas.TakeEnqueue(ctx, tenantID, limits)
+}
+
+// Get returns a task. Tasks of another tenant than the context is limited to
+// are reported as not found, so every operation built on Get is isolated too.
+func (s *TaskService) Get(ctx context.Context, id string) (*models.Task, error) {
+	task, err := s.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrTaskNotFound, id)
+	}
+
+	if scope := tenantFrom(ctx); scope != "" && task.TenantID != scope {
+		return nil, fmt.Errorf("%w: %s", ErrTaskNotFound, id)
+	}
+
+	return task, nil
+}
+
+type ListTasksInput struct {
+	// TenantID narrows an unscoped listing to one tenant. It is ignored for
+	// contexts limited to a tenant.
+	TenantID string
+
+	Statuses    []string
+	Types       []string
+	WorkerID    string
+	MinPriority *int
+	MaxPriority *int
+	NamePrefix  string
+	Payload     map[string]interface{}
+
+	CreatedAfter    *time.Time
+	CreatedBefore   *time.Time
+	ScheduledAfter  *time.Time
+	ScheduledBefore *time.Time
+	CompletedAfter  *time.Time
+	CompletedBefore *time.Time
+
+	// Sort is a task field name; Order is "asc" or "desc". The default is
+	// newest first.
+	Sort  string
+	Order string
+
+	Limit     int
+	Offset    int
+	PageToken string
+}
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 500
+)
+
+var taskStatuses = []models.TaskStatus{
+	models.TaskStatusPending,
+	models.TaskStatusRunning,
+	models.TaskStatusCompleted,
+	models.TaskStatusFailed,
+	models.TaskStatusCancelled,
+}
+
+// List returns a page of tasks and the token for the next page, which is
+// empty once there are no more tasks.
+func (s *TaskService) List(ctx context.Context, input ListTasksInput) ([]*models.Task, string, error) {
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	limit = min(limit, maxListLimit)
+
+	for _, status := range input.Statuses {
+		if !slices.Contains(taskStatuses, models.TaskStatus(status)) {
+			return nil, "", fmt.Errorf("%w: unknown status %q", ErrInvalidTask, status)
+		}
+	}
+
+	if input.MinPriority != nil && input.MaxPriority != nil && *input.MinPriority > *input.MaxPriority {
+		return nil, "", fmt.Errorf("%w: min_priority is greater than max_priority", ErrInvalidTask)
+	}
+
+	sortField := input.Sort
+	if sortField == "" {
+		sortField = repository.DefaultTaskSortField
+	}
+	if !repository.IsTaskSortField(sortField) {
+		return nil, "", fmt.Errorf("%w: cannot sort by %q", ErrInvalidTask, sortField)
+	}
+
+	var descending bool
+	switch input.Order {
+	case "", "desc":
+		descending = true
+	case "asc":
+	default:
+		return nil, "", fmt.Errorf("%w: order must be asc or desc", ErrInvalidTask)
+	}
+
+	tenantID := input.TenantID
+	if scope := tenantFrom(ctx); scope != "" {
+		tenantID = scope
+	}
+
+	filter := repository.TaskFilter{
+		TenantID:        tenantID,
+		Statuses:        input.Statuses,
+		Types:           input.Types,
+		WorkerID:        input.WorkerID,
+		MinPriority:     input.MinPriority,
+		MaxPriority:     input.MaxPriority,
+		NamePrefix:      input.NamePrefix,
+		Payload:         input.Payload,
+		CreatedAfter:    input.CreatedAfter,
+		CreatedBefore:   input.CreatedBefore,
+		ScheduledAfter:  input.ScheduledAfter,
+		ScheduledBefore: input.ScheduledBefore,
+		CompletedAfter:  input.CompletedAfter,
+		CompletedBefore: input.CompletedBefore,
+		SortField:       sortField,
+		SortDescending:  descending,
+		Limit:           limit + 1,
+		Offset:          max(input.Offset, 0),
+	}
+
+	if input.PageToken != "" {
+		token, err := decodePageToken(input.PageToken)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: %v", ErrInvalidTask, err)
+		}
+		if token.Sort != sortField || token.Descending != descending {
+			return nil, "", fmt.Errorf("%w: page token belongs to a different sort order", ErrInvalidTask)
+		}
+		filter.After = &repository.TaskCursor{Value: token.Value, ID: token.ID}
+	}
+
+	tasks, err := s.taskRepo.ListFiltered(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextPageToken := ""
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+		cursor := repository.TaskCursorFor(tasks[len(tasks)-1], sortField)
+		nextPageToken = encodePageToken(pageToken{
+			Sort:       sortField,
+			Descending: descending,
+			Value:      cursor.Value,
+			ID:         cursor.ID,
+		})
+	}
+
+	return tasks, nextPageToken, nil
+}
+
+// pageToken is serialized into the opaque next_page_token. It records the
+// sort it was issued for so it cannot be replayed against another order.
+type pageToken struct {
+	Sort       string `json:"s"`
+	Descending bool   `json:"d,omitempty"`
+	Value      string `json:"v"`
+	ID         string `json:"i"`
+}
+
+func encodePageToken(token pageToken) string {
+	raw, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodePageToken(encoded string) (*pageToken, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("malformed page token")
+	}
+
+	var token pageToken
+	if err := json.Unmarshal(raw, &token); err != nil || token.ID == "" {
+		return nil, fmt.Errorf("malformed page token")
+	}
+
+	return &token, nil
+}
+
+// Cancel stops a task that has not started yet. Running tasks are cancelled
+// through a worker instruction instead.
+func (s *TaskService) Cancel(ctx context.Context, id string) (*models.Task, error) {
+	task, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.Status != models.TaskStatusPending {
+		return nil, fmt.Errorf("%w: cannot cancel task that is %s", ErrInvalidState, task.Status)
+	}
+	before := *task
+
+	if err := s.queue.Remove(id); err != nil {
+		return nil, err
+	}
+
+	task.Status = models.TaskStatusCancelled
+	task.UpdatedAt = time.Now()
+	if err := s.taskRepo.UpdateStatus(ctx, id, task.Status); err != nil {
+		return nil, err
+	}
+
+	s.queue.PublishTaskEvent(ctx, task, models.TaskStatusPending)
+	audit.SetChange(ctx, &before, task)
+
+	return task, nil
+}
+
+type RetryOptions struct {
+	// ResetRetries gives the task a fresh retry budget. Without it the task
+	// keeps its retry count, so it gets one more attempt if it had run out.
+	ResetRetries bool
+
+	// Payload replaces the task's payload when set.
+	Payload map[string]interface{}
+}
+
+// Retry puts a failed or cancelled task back on the queue to run now.
+func (s *TaskService) Retry(ctx context.Context, id string, opts RetryOptions) (*models.Task, error) {
+	task, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.Status != models.TaskStatusFailed && task.Status != models.TaskStatusCancelled {
+		return nil, fmt.Errorf("%w: only failed or cancelled tasks can be retried, task is %s", ErrInvalidState, task.Status)
+	}
+	before := *task
+
+	now := time.Now()
+	task.Status = models.TaskStatusPending
+	if opts.ResetRetries {
+		task.Retries = 0
+	} else if task.Retries >= task.MaxRetries {
+		task.Retries = max(task.MaxRetries-1, 0)
+	}
+	if opts.Payload != nil {
+		task.Payload = opts.Payload
+	}
+	task.Error = ""
+	task.WorkerID = ""
+	task.StartedAt = nil
+	task.CompletedAt = nil
+	task.ScheduledAt = now
+	task.UpdatedAt = now
+
+	if err := s.taskRepo.Update(ctx, task); err != nil {
+		return nil, err
+	}
+
+	if err := s.queue.Enqueue(task); err != nil {
+		return nil, err
+	}
+	audit.SetChange(ctx, &before, task)
+
+	return task, nil
+}
+
+type TaskUpdate struct {
+	Priority    *models.TaskPriority
+	ScheduledAt *time.Time
+	MaxRetries  *int
+}
+
+// Priorities run from 1 to 10. Create reads 0 as "use the default", so it is
+// not a priority a task can be given.
+const (
+	minTaskPriority = 1
+	maxTaskPriority = 10
+)
+
+// Update changes the priority, schedule or retry budget of a pending task and
+// moves it in the queue to match. Tasks a worker has already claimed cannot be
+// changed.
+func (s *TaskService) Update(ctx context.Context, id string, update TaskUpdate) (*models.Task, error) {
+	if update.Priority == nil && update.ScheduledAt == nil && update.MaxRetries == nil {
+		return nil, fmt.Errorf("%w: nothing to update", ErrInvalidTask)
+	}
+
+	if update.Priority != nil && (*update.Priority < minTaskPriority || *update.Priority > maxTaskPriority) {
+		return nil, fmt.Errorf("%w: priority must be between %d and %d", ErrInvalidTask, minTaskPriority, maxTaskPriority)
+	}
+
+	if update.MaxRetries != nil && *update.MaxRetries < 0 {
+		return nil, fmt.Errorf("%w: max_retries cannot be negative", ErrInvalidTask)
+	}
+
+	if update.ScheduledAt != nil && update.ScheduledAt.IsZero() {
+		return nil, fmt.Errorf("%w: scheduled_at cannot be empty", ErrInvalidTask)
+	}
+
+	task, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.Status != models.TaskStatusPending {
+		return nil, fmt.Errorf("%w: only pending tasks can be updated, task is %s", ErrInvalidState, task.Status)
+	}
+	before := *task
+
+	if update.Priority != nil {
+		task.Priority = *update.Priority
+	}
+	if update.ScheduledAt != nil {
+		task.ScheduledAt = *update.ScheduledAt
+	}
+	if update.MaxRetries != nil {
+		task.MaxRetries = *update.MaxRetries
+	}
+	task.UpdatedAt = time.Now()
+
+	queued, err := s.queue.Rescore(task)
+	if err != nil {
+		return nil, err
+	}
+	if !queued {
+		return nil, fmt.Errorf("%w: task was claimed by a worker", ErrInvalidState)
+	}
+
+	if err := s.taskRepo.UpdateScheduling(ctx, task); err != nil {
+		return nil, err
+	}
+	audit.SetChange(ctx, &before, task)
+
+	return task, nil
+}
+
+func (s *TaskService) Delete(ctx context.Context, id string) error {
+	task, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if task.Status == models.TaskStatusRunning {
+		return fmt.Errorf("%w: cannot delete a running task", ErrInvalidState)
+	}
+
+	if task.Status == models.TaskStatusPending {
+		if err := s.queue.Remove(id); err != nil {
+			return err
+		}
+	}
+
+	if err := s.taskRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	audit.SetChange(ctx, task, nil)
+
+	return nil
+}
+
+// Claim leases up to limit tasks the worker can run from the queue and marks
+// them running. The queue lease is what makes a claim exclusive, so remote and
+// local workers never pick up the same task. The counts a worker reports lag
+// a heartbeat behind, so tasks claimed for it and not yet finished take up
+// its slots too, and it is never handed more than it has free.
+func (s *TaskService) Claim(ctx context.Context, worker *models.Worker, limit int) ([]*models.Task, error) {
+	running, err := s.taskRepo.CountRunningByWorker(ctx, worker.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := 0
+	for taskType, count := range running {
+		claimed += count
+		if count > worker.RunningByType[taskType] {
+			if worker.RunningByType == nil {
+				worker.RunningByType = make(map[string]int)
+			}
+			worker.RunningByType[taskType] = count
+		}
+	}
+	worker.CurrentTasks = max(worker.CurrentTasks, claimed)
+
+	if worker.MaxConcurrentTasks > 0 {
+		limit = min(limit, worker.MaxConcurrentTasks-worker.CurrentTasks)
+	}
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	tasks, err := s.queue.Dequeue(worker, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, task := range tasks {
+		task.Status = models.TaskStatusRunning
+		task.WorkerID = worker.ID
+		task.StartedAt = &now
+		task.UpdatedAt = now
+
+		if err := s.taskRepo.Save(ctx, task); err != nil {
+			log.Printf("Failed to record claim of task %s by worker %s: %v", task.ID, worker.ID, err)
+		}
+		if err := s.queue.UpdateTask(task); err != nil {
+			log.Printf("Failed to update queued copy of task %s: %v", task.ID, err)
+		}
+	}
+
+	return tasks, nil
+}
+
+// TaskReport is what a worker may change about a task it is running.
+type TaskReport struct {
+	TaskID      string
+	WorkerID    string
+	Status      models.TaskStatus
+	Error       string
+	CompletedAt *time.Time
+}
+
+// Report records a state change sent by the worker running the task. Only the
+// worker holding the task's lease may report on it, and only the status,
+// error and completion time are taken from the report; everything else stays
+// as the server has it. Pending reports put the task back on the queue for
+// another attempt, or fail it once its retries are used up.
+func (s *TaskService) Report(ctx context.Context, report TaskReport) (*models.Task, error) {
+	task, err := s.reportedTask(ctx, report.TaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	holder, err := s.queue.LeaseHolder(ctx, report.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	// A lease can lapse while its worker is still finishing, in which case
+	// the worker the task was last claimed by may still report on it.
+	if holder == "" && task.Status == models.TaskStatusRunning {
+		holder = task.WorkerID
+	}
+	if report.WorkerID == "" || holder != report.WorkerID {
+		return nil, fmt.Errorf("%w: task %s is not leased to worker %s", ErrNotLeaseHolder, report.TaskID, report.WorkerID)
+	}
+
+	before := *task
+	now := time.Now()
+
+	switch report.Status {
+	case models.TaskStatusRunning:
+		task.WorkerID = report.WorkerID
+	case models.TaskStatusCompleted, models.TaskStatusFailed:
+		task.Error = report.Error
+		task.CompletedAt = report.CompletedAt
+		if task.CompletedAt == nil {
+			task.CompletedAt = &now
+		}
+	case models.TaskStatusPending:
+		task.Error = report.Error
+		task.Retries++
+		if task.Retries >= task.MaxRetries {
+			report.Status = models.TaskStatusFailed
+			task.CompletedAt = &now
+			break
+		}
+		task.WorkerID = ""
+		task.StartedAt = nil
+		task.ScheduledAt = now.Add(time.Duration(task.Retries*task.Retries) * time.Second)
+	default:
+		return nil, fmt.Errorf("%w: workers cannot report status %q", ErrInvalidTask, report.Status)
+	}
+	task.Status = report.Status
+	task.UpdatedAt = now
+
+	if err := s.taskRepo.Save(ctx, task); err != nil {
+		return nil, err
+	}
+	audit.SetChange(ctx, &before, task)
+
+	return task, s.queue.UpdateTask(task)
+}
+
+// reportedTask loads a task for a worker report. Workers are not limited to a
+// tenant, and tasks enqueued straight into Redis only exist there.
+func (s *TaskService) reportedTask(ctx context.Context, id string) (*models.Task, error) {
+	task, err := s.taskRepo.GetByID(ctx, id)
+	if err == nil {
+		return task, nil
+	}
+
+	task, qErr := s.queue.GetTask(ctx, id)
+	if qErr != nil {
+		return nil, fmt.Errorf("%w: %s", ErrTaskNotFound, id)
+	}
+	return task, nil
+}
+
+// QueueSize counts the tasks waiting in the queue. A context limited to a
+// tenant counts that tenant's queue only.
+func (s *TaskService) QueueSize(ctx context.Context) (int, error) {
+	if scope := tenantFrom(ctx); scope != "" {
+		return s.queue.PendingCount(ctx, scope)
+	}
+	return s.queue.Size()
+}
+
+type TaskStats struct {
+	ByStatus  map[string]int
+	Total     int
+	QueueSize int
+}
+
+// Stats counts tasks by status. The cached counts and queue size cover every
+// tenant, so a context limited to a tenant counts its own tasks directly and
+// reports its pending tasks as its queue.
+func (s *TaskService) Stats(ctx context.Context) (*TaskStats, error) {
+	if scope := tenantFrom(ctx); scope != "" {
+		byStatus, err := s.taskRepo.GetStats(ctx, scope)
+		if err != nil {
+			return nil, err
+		}
+
+		stats := &TaskStats{ByStatus: byStatus, QueueSize: byStatus[string(models.TaskStatusPending)]}
+		for _, count := range byStatus {
+			stats.Total += count
+		}
+		return stats, nil
+	}
+
+	byStatus, err := s.cache.GetCachedTaskStats(ctx)
+	if err != nil || byStatus == nil {
+		byStatus, err = s.taskRepo.GetStats(ctx, "")
+		if err != nil {
+			return nil, err
+		}
+		s.cache.CacheTaskStats(ctx, byStatus)
+	}
+
+	stats := &TaskStats{ByStatus: byStatus}
+	for _, count := range byStatus {
+		stats.Total += count
+	}
+
+	stats.QueueSize, _ = s.queue.Size()
+
+	return stats, nil
+}
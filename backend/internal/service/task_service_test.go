@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/database"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+	"github.com/rudraprasaaad/task-scheduler/internal/queue"
+	redisClient "github.com/rudraprasaaad/task-scheduler/internal/redis"
+	"github.com/rudraprasaaad/task-scheduler/internal/repository"
+)
+
+var taskColumns = strings.Split("id,name,type,payload,priority,status,retries,max_retries,created_at,updated_at,scheduled_at,started_at,completed_at,error,worker_id,selector,tenant_id,created_by", ",")
+
+func newReportService(t *testing.T) (*TaskService, sqlmock.Sqlmock, *redis.Client) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	store := &database.DB{DB: db}
+	tasks := NewTaskService(repository.NewTaskRepository(store), repository.NewTaskExecutionRepository(store),
+		queue.NewRedisQueue(&redisClient.Client{Client: client}, nil), nil, nil)
+
+	return tasks, mock, client
+}
+
+// expectRunningTask returns a task that worker-1 claimed, as the database has it.
+func expectRunningTask(mock sqlmock.Sqlmock, retries int) *models.Task {
+	task := models.NewTask("report", "generate-report", nil)
+	started := time.Now().Add(-time.Minute)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM tasks WHERE id = $1`)).
+		WithArgs(task.ID).
+		WillReturnRows(sqlmock.NewRows(taskColumns).AddRow(
+			task.ID, task.Name, task.Type, []byte(`{"day":"monday"}`), task.Priority, models.TaskStatusRunning, retries, task.MaxRetries,
+			task.CreatedAt, task.UpdatedAt, task.ScheduledAt, started, nil, nil, "worker-1", nil, models.DefaultTenantID, nil))
+
+	return task
+}
+
+func TestReportRequiresTheLease(t *testing.T) {
+	tasks, mock, client := newReportService(t)
+	ctx := context.Background()
+
+	task := expectRunningTask(mock, 0)
+	client.Set(ctx, queue.TaskLockKeyPrefix+task.ID, "worker-1", time.Minute)
+
+	_, err := tasks.Report(ctx, TaskReport{TaskID: task.ID, WorkerID: "worker-2", Status: models.TaskStatusCompleted})
+	if !errors.Is(err, ErrNotLeaseHolder) {
+		t.Fatalf("got %v reporting on another worker's task, want ErrNotLeaseHolder", err)
+	}
+
+	// Without a live lease, only the worker the task was claimed by may
+	// still report on it.
+	task = expectRunningTask(mock, 0)
+	_, err = tasks.Report(ctx, TaskReport{TaskID: task.ID, WorkerID: "worker-2", Status: models.TaskStatusCompleted})
+	if !errors.Is(err, ErrNotLeaseHolder) {
+		t.Fatalf("got %v after the lease lapsed, want ErrNotLeaseHolder", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReportTakesOnlyStatusFields(t *testing.T) {
+	tasks, mock, client := newReportService(t)
+	ctx := context.Background()
+
+	task := expectRunningTask(mock, 0)
+	client.Set(ctx, queue.TaskLockKeyPrefix+task.ID, "worker-1", time.Minute)
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO tasks`)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	reported, err := tasks.Report(ctx, TaskReport{TaskID: task.ID, WorkerID: "worker-1", Status: models.TaskStatusFailed, Error: "boom"})
+	if err != nil {
+		t.Fatalf("report failed: %v", err)
+	}
+
+	if reported.Status != models.TaskStatusFailed || reported.Error != "boom" || reported.CompletedAt == nil {
+		t.Errorf("got status %s, error %q, completed %v; want the reported failure", reported.Status, reported.Error, reported.CompletedAt)
+	}
+	if reported.Name != task.Name || reported.Payload["day"] != "monday" || reported.WorkerID != "worker-1" {
+		t.Errorf("got %+v, want the stored task otherwise unchanged", reported)
+	}
+
+	if held, _ := client.Exists(ctx, queue.TaskLockKeyPrefix+task.ID).Result(); held != 0 {
+		t.Error("finishing the task did not release its lease")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReportPendingRetriesOnTheServer(t *testing.T) {
+	tasks, mock, client := newReportService(t)
+	ctx := context.Background()
+
+	task := expectRunningTask(mock, 0)
+	client.Set(ctx, queue.TaskLockKeyPrefix+task.ID, "worker-1", time.Minute)
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO tasks`)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	reported, err := tasks.Report(ctx, TaskReport{TaskID: task.ID, WorkerID: "worker-1", Status: models.TaskStatusPending, Error: "timeout"})
+	if err != nil {
+		t.Fatalf("report failed: %v", err)
+	}
+	if reported.Status != models.TaskStatusPending || reported.Retries != 1 || reported.WorkerID != "" {
+		t.Errorf("got status %s, retries %d, worker %q; want a first retry with no worker", reported.Status, reported.Retries, reported.WorkerID)
+	}
+	if queued, _ := client.ZScore(ctx, queue.TaskQueueKey, task.ID).Result(); queued == 0 {
+		t.Error("the retry was not put back on the queue")
+	}
+
+	// The last allowed attempt fails the task instead.
+	task = expectRunningTask(mock, task.MaxRetries-1)
+	client.Set(ctx, queue.TaskLockKeyPrefix+task.ID, "worker-1", time.Minute)
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO tasks`)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	reported, err = tasks.Report(ctx, TaskReport{TaskID: task.ID, WorkerID: "worker-1", Status: models.TaskStatusPending, Error: "timeout"})
+	if err != nil {
+		t.Fatalf("report failed: %v", err)
+	}
+	if reported.Status != models.TaskStatusFailed {
+		t.Errorf("got status %s after the last retry, want failed", reported.Status)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,86 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rudraprasaaad/task-scheduler/internal/auth"
+	redisClient "github.com/rudraprasaaad/task-scheduler/internal/redis"
+)
+
+const (
+	revokedTokenKeyPrefix   = "task_scheduler:auth:revoked:jti:"
+	revokedUserKeyPrefix    = "task_scheduler:auth:revoked:user:"
+	revokedSessionKeyPrefix = "task_scheduler:auth:revoked:session:"
+)
+
+// Denylist records revoked access tokens in Redis until they would have
+// expired anyway. Logout revokes one token by its jti, ending a session
+// revokes every token issued for it, and logging out everywhere records a
+// cutoff that rejects every token the user was issued before it.
+type Denylist struct {
+	client   *redisClient.Client
+	tokenTTL time.Duration
+}
+
+func NewDenylist(client *redisClient.Client, tokenTTL time.Duration) *Denylist {
+	return &Denylist{client: client, tokenTTL: tokenTTL}
+}
+
+func (d *Denylist) RevokeToken(ctx context.Context, claims *auth.Claims) error {
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return nil
+	}
+
+	ttl := time.Until(claims.ExpiresAt.Time)
+	if ttl <= 0 {
+		return nil
+	}
+
+	return d.client.Set(ctx, revokedTokenKeyPrefix+claims.ID, 1, ttl).Err()
+}
+
+// RevokeSession rejects every access token issued for the session. A revoked
+// session issues no more, so no cutoff is needed.
+func (d *Denylist) RevokeSession(ctx context.Context, sessionID string) error {
+	return d.client.Set(ctx, revokedSessionKeyPrefix+sessionID, 1, d.tokenTTL).Err()
+}
+
+// RevokeUser records the cutoff in microseconds, the precision token issue
+// times are kept in.
+func (d *Denylist) RevokeUser(ctx context.Context, userID string) error {
+	return d.client.Set(ctx, revokedUserKeyPrefix+userID, time.Now().UnixMicro(), d.tokenTTL).Err()
+}
+
+// IsRevoked also rejects tokens without a jti. They were issued before
+// sessions existed and could never be revoked, so their holders log in again.
+func (d *Denylist) IsRevoked(ctx context.Context, claims *auth.Claims) (bool, error) {
+	if claims.ID == "" {
+		return true, nil
+	}
+
+	pipe := d.client.Pipeline()
+	revokedToken := pipe.Exists(ctx, revokedTokenKeyPrefix+claims.ID)
+	var revokedSession *redis.IntCmd
+	if claims.SessionID != "" {
+		revokedSession = pipe.Exists(ctx, revokedSessionKeyPrefix+claims.SessionID)
+	}
+	userCutoff := pipe.Get(ctx, revokedUserKeyPrefix+claims.UserID)
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return false, err
+	}
+
+	if revokedToken.Val() > 0 || (revokedSession != nil && revokedSession.Val() > 0) {
+		return true, nil
+	}
+
+	cutoff, err := strconv.ParseInt(userCutoff.Val(), 10, 64)
+	if err != nil {
+		return false, nil
+	}
+
+	return claims.IssuedAt == nil || claims.IssuedAt.UnixMicro() < cutoff, nil
+}
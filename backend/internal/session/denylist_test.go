@@ -0,0 +1,87 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rudraprasaaad/task-scheduler/internal/auth"
+)
+
+func issuedAt(t *testing.T, at time.Time) *auth.Claims {
+	t.Helper()
+
+	claims := &auth.Claims{
+		UserID: "7",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "jti-" + at.Format(time.RFC3339Nano),
+			IssuedAt:  jwt.NewNumericDate(at),
+			ExpiresAt: jwt.NewNumericDate(at.Add(time.Hour)),
+		},
+	}
+
+	// Go through a signed token, so the test sees the issue time at the
+	// precision it is stored in.
+	token, err := auth.GenrerateToken(claims, testAuthConfig.JWTSecret)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	parsed, err := auth.ValidateToken(token, testAuthConfig.JWTSecret)
+	if err != nil {
+		t.Fatalf("failed to parse token: %v", err)
+	}
+	return parsed
+}
+
+func TestRevokeUserCutsOffWithinTheSameSecond(t *testing.T) {
+	denylist := NewDenylist(newTestRedis(t), time.Hour)
+	ctx := context.Background()
+
+	before := issuedAt(t, time.Now())
+	time.Sleep(time.Millisecond)
+	if err := denylist.RevokeUser(ctx, "7"); err != nil {
+		t.Fatalf("revoke failed: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	after := issuedAt(t, time.Now())
+
+	if revoked, _ := denylist.IsRevoked(ctx, before); !revoked {
+		t.Error("token issued before logging out everywhere is still accepted")
+	}
+	if revoked, _ := denylist.IsRevoked(ctx, after); revoked {
+		t.Error("token issued after logging out everywhere is rejected")
+	}
+}
+
+func TestRevokeTokenAndSession(t *testing.T) {
+	denylist := NewDenylist(newTestRedis(t), time.Hour)
+	ctx := context.Background()
+
+	first := issuedAt(t, time.Now())
+	first.SessionID = "session-1"
+	second := issuedAt(t, time.Now().Add(time.Second))
+	second.SessionID = "session-1"
+	other := issuedAt(t, time.Now().Add(2*time.Second))
+	other.SessionID = "session-2"
+
+	if err := denylist.RevokeToken(ctx, first); err != nil {
+		t.Fatalf("revoke token failed: %v", err)
+	}
+	if revoked, _ := denylist.IsRevoked(ctx, first); !revoked {
+		t.Error("revoked token is still accepted")
+	}
+	if revoked, _ := denylist.IsRevoked(ctx, second); revoked {
+		t.Error("revoking one token rejected another of the same session")
+	}
+
+	if err := denylist.RevokeSession(ctx, "session-1"); err != nil {
+		t.Fatalf("revoke session failed: %v", err)
+	}
+	if revoked, _ := denylist.IsRevoked(ctx, second); !revoked {
+		t.Error("token of a revoked session is still accepted")
+	}
+	if revoked, _ := denylist.IsRevoked(ctx, other); revoked {
+		t.Error("revoking a session rejected a token of another session")
+	}
+}
@@ -0,0 +1,235 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/rudraprasaaad/task-scheduler/internal/auth"
+	"github.com/rudraprasaaad/task-scheduler/internal/config"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+	"github.com/rudraprasaaad/task-scheduler/internal/repository"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+
+	// ErrRefreshTokenReused means a refresh token was presented after it had
+	// already been exchanged. Someone else may hold a copy, so the session
+	// is ended.
+	ErrRefreshTokenReused = errors.New("refresh token was already used, session revoked")
+)
+
+// Tokens is what a login or refresh hands back to the client.
+type Tokens struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+	CSRFToken        string
+	Claims           *auth.Claims
+}
+
+// Client describes where a login came from, for the session record.
+type Client struct {
+	UserAgent string
+	IPAddress string
+}
+
+// Manager issues short-lived access tokens for server-side sessions and
+// rotates the session's refresh token each time it is used.
+type Manager struct {
+	sessions *repository.SessionRepository
+	users    *repository.UserRepository
+	denylist *Denylist
+	cfg      config.AuthConfig
+}
+
+func NewManager(sessions *repository.SessionRepository, users *repository.UserRepository, denylist *Denylist, cfg config.AuthConfig) *Manager {
+	return &Manager{sessions: sessions, users: users, denylist: denylist, cfg: cfg}
+}
+
+func (m *Manager) Start(ctx context.Context, user *models.User, roles []string, client Client) (*Tokens, error) {
+	session := models.NewSession(user.ID, m.cfg.RefreshTokenExpiration)
+	session.UserAgent = client.UserAgent
+	session.IPAddress = client.IPAddress
+
+	secret, refreshToken, err := auth.GenerateRefreshToken(session.ID)
+	if err != nil {
+		return nil, err
+	}
+	session.RefreshHash = auth.HashSecret(secret)
+
+	if err := m.sessions.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return m.issue(user, roles, session, refreshToken)
+}
+
+// Refresh exchanges a refresh token for a new access token and a new refresh
+// token. Roles and tenant are reloaded, so changes apply from the next refresh
+// rather than the next login.
+func (m *Manager) Refresh(ctx context.Context, refreshToken string) (*Tokens, error) {
+	session, secret, err := m.lookup(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.PreviousHash != "" && auth.VerifySecret(secret, session.PreviousHash) {
+		if err := m.revokeSession(ctx, session.ID); err != nil {
+			return nil, err
+		}
+		log.Printf("WARNING: Refresh token for session %s of user %d was reused, session revoked", session.ID, session.UserID)
+		return nil, ErrRefreshTokenReused
+	}
+
+	if !auth.VerifySecret(secret, session.RefreshHash) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := m.users.GetByID(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := m.users.GetRoles(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	newSecret, newToken, err := auth.GenerateRefreshToken(session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.sessions.Rotate(ctx, session.ID, session.RefreshHash, auth.HashSecret(newSecret)); err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	return m.issue(user, roles, session, newToken)
+}
+
+func (m *Manager) lookup(ctx context.Context, refreshToken string) (*models.Session, string, error) {
+	sessionID, secret, err := auth.ParseRefreshToken(refreshToken)
+	if err != nil {
+		return nil, "", ErrInvalidRefreshToken
+	}
+
+	session, err := m.sessions.Get(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return nil, "", ErrInvalidRefreshToken
+		}
+		return nil, "", err
+	}
+
+	if !session.Active() {
+		return nil, "", ErrInvalidRefreshToken
+	}
+
+	return session, secret, nil
+}
+
+func (m *Manager) issue(user *models.User, roles []string, session *models.Session, refreshToken string) (*Tokens, error) {
+	now := time.Now()
+	expiresAt := now.Add(m.cfg.TokenExpiration)
+
+	claims := &auth.Claims{
+		UserID: strconv.FormatInt(user.ID, 10),
+		Role:   auth.PrimaryRole(roles),
+		Roles:  roles,
+
+		TenantID:  user.TenantID,
+		SessionID: session.ID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	accessToken, err := auth.GenrerateToken(claims, m.cfg.JWTSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	csrfToken, err := auth.GenerateCSRFToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tokens{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  expiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: session.ExpiresAt,
+		CSRFToken:        csrfToken,
+		Claims:           claims,
+	}, nil
+}
+
+// Logout ends the session an access token belongs to and revokes the token.
+// Either argument may be empty; a refresh token alone is enough to end the
+// session when the access token has already expired.
+func (m *Manager) Logout(ctx context.Context, claims *auth.Claims, refreshToken string) error {
+	sessionID := ""
+	if claims != nil {
+		sessionID = claims.SessionID
+		if err := m.denylist.RevokeToken(ctx, claims); err != nil {
+			return fmt.Errorf("failed to revoke access token: %w", err)
+		}
+	}
+
+	if sessionID == "" && refreshToken != "" {
+		session, secret, err := m.lookup(ctx, refreshToken)
+		if err == nil && auth.VerifySecret(secret, session.RefreshHash) {
+			sessionID = session.ID
+		} else if err != nil && !errors.Is(err, ErrInvalidRefreshToken) {
+			return err
+		}
+	}
+
+	if sessionID == "" {
+		return nil
+	}
+
+	return m.revokeSession(ctx, sessionID)
+}
+
+// revokeSession ends the session and rejects the access tokens it has issued
+// that have not expired yet.
+func (m *Manager) revokeSession(ctx context.Context, sessionID string) error {
+	if err := m.sessions.Revoke(ctx, sessionID); err != nil {
+		return err
+	}
+
+	if err := m.denylist.RevokeSession(ctx, sessionID); err != nil {
+		return fmt.Errorf("failed to revoke access tokens: %w", err)
+	}
+
+	return nil
+}
+
+// LogoutAll ends every session of the user and revokes every access token
+// issued to them so far.
+func (m *Manager) LogoutAll(ctx context.Context, userID int64) (int64, error) {
+	revoked, err := m.sessions.RevokeUser(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := m.denylist.RevokeUser(ctx, strconv.FormatInt(userID, 10)); err != nil {
+		return revoked, fmt.Errorf("failed to revoke access tokens: %w", err)
+	}
+
+	return revoked, nil
+}
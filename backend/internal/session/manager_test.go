@@ -0,0 +1,182 @@
+package session
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/auth"
+	"github.com/rudraprasaaad/task-scheduler/internal/config"
+	"github.com/rudraprasaaad/task-scheduler/internal/database"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+	redisClient "github.com/rudraprasaaad/task-scheduler/internal/redis"
+	"github.com/rudraprasaaad/task-scheduler/internal/repository"
+)
+
+var testAuthConfig = config.AuthConfig{
+	JWTSecret:              "test-secret",
+	TokenExpiration:        15 * time.Minute,
+	RefreshTokenExpiration: 24 * time.Hour,
+}
+
+func newTestRedis(t *testing.T) *redisClient.Client {
+	t.Helper()
+
+	client, _ := newTestRedisServer(t)
+	return client
+}
+
+// newTestRedisServer also returns the server, for tests that move its clock.
+func newTestRedisServer(t *testing.T) (*redisClient.Client, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return &redisClient.Client{Client: client}, server
+}
+
+func newTestManager(t *testing.T) (*Manager, *Denylist, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	store := &database.DB{DB: db}
+	denylist := NewDenylist(newTestRedis(t), testAuthConfig.TokenExpiration)
+	manager := NewManager(repository.NewSessionRepository(store), repository.NewUserRepository(store), denylist, testAuthConfig)
+
+	return manager, denylist, mock
+}
+
+// capture matches any string argument and keeps it, so a test can hand a
+// hash the manager wrote back to it on the next read.
+type capture struct {
+	value *string
+}
+
+func (c capture) Match(v driver.Value) bool {
+	s, ok := v.(string)
+	if ok {
+		*c.value = s
+	}
+	return ok
+}
+
+var sessionColumns = []string{"id", "user_id", "refresh_hash", "previous_hash", "user_agent", "ip_address", "created_at", "refreshed_at", "expires_at", "revoked_at"}
+
+func expectSession(mock sqlmock.Sqlmock, id, refreshHash, previousHash string, revokedAt interface{}) {
+	var previous interface{}
+	if previousHash != "" {
+		previous = previousHash
+	}
+
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM auth_sessions`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(sessionColumns).
+			AddRow(id, int64(7), refreshHash, previous, nil, nil, now, nil, now.Add(time.Hour), revokedAt))
+}
+
+func expectUser(mock sqlmock.Sqlmock) {
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM users WHERE id = $1`)).
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password_hash", "tenant_id", "created_at", "updated_at"}).
+			AddRow(int64(7), "ada@example.com", "", "acme", now, now))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM user_roles`)).
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow(auth.RoleUser))
+}
+
+func TestRefreshRotatesAndDetectsReuse(t *testing.T) {
+	manager, denylist, mock := newTestManager(t)
+	ctx := context.Background()
+
+	var firstHash, secondHash string
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO auth_sessions`)).
+		WithArgs(sqlmock.AnyArg(), int64(7), capture{&firstHash}, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	login, err := manager.Start(ctx, &models.User{ID: 7, TenantID: "acme"}, []string{auth.RoleUser}, Client{})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	sessionID := login.Claims.SessionID
+
+	// The first refresh rotates the token: the old hash must still be the
+	// current one for the update to apply.
+	expectSession(mock, sessionID, firstHash, "", nil)
+	expectUser(mock)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE auth_sessions SET previous_hash = refresh_hash`)).
+		WithArgs(sessionID, firstHash, capture{&secondHash}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	refreshed, err := manager.Refresh(ctx, login.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+	if refreshed.RefreshToken == login.RefreshToken || secondHash == firstHash {
+		t.Fatal("refresh did not rotate the refresh token")
+	}
+	if refreshed.Claims.SessionID != sessionID || refreshed.Claims.TenantID != "acme" {
+		t.Errorf("got claims %+v, want the same session and tenant", refreshed.Claims)
+	}
+
+	// Presenting the first token again means someone else has a copy: the
+	// session ends and the access tokens it issued stop working.
+	expectSession(mock, sessionID, secondHash, firstHash, nil)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE auth_sessions SET revoked_at = NOW() WHERE id = $1`)).
+		WithArgs(sessionID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if _, err := manager.Refresh(ctx, login.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("got %v reusing a refresh token, want ErrRefreshTokenReused", err)
+	}
+
+	for _, claims := range []*auth.Claims{login.Claims, refreshed.Claims} {
+		revoked, err := denylist.IsRevoked(ctx, claims)
+		if err != nil {
+			t.Fatalf("revocation check failed: %v", err)
+		}
+		if !revoked {
+			t.Errorf("access token %s of the revoked session is still accepted", claims.ID)
+		}
+	}
+
+	// Nor does the latest refresh token work once the session is revoked.
+	expectSession(mock, sessionID, secondHash, firstHash, time.Now())
+
+	if _, err := manager.Refresh(ctx, refreshed.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("got %v refreshing a revoked session, want ErrInvalidRefreshToken", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRefreshRejectsUnknownSecret(t *testing.T) {
+	manager, _, mock := newTestManager(t)
+
+	_, token, err := auth.GenerateRefreshToken("0b9f2a52-6a8e-4a57-9a55-0d3c1d1a6f10")
+	if err != nil {
+		t.Fatalf("failed to generate refresh token: %v", err)
+	}
+	expectSession(mock, "0b9f2a52-6a8e-4a57-9a55-0d3c1d1a6f10", auth.HashSecret("other"), "", nil)
+
+	if _, err := manager.Refresh(context.Background(), token); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("got %v, want ErrInvalidRefreshToken", err)
+	}
+}
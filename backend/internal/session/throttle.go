@@ -0,0 +1,131 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/config"
+	redisClient "github.com/rudraprasaaad/task-scheduler/internal/redis"
+)
+
+const (
+	loginFailuresKeyPrefix = "task_scheduler:auth:login_failures:"
+	loginLockoutKeyPrefix  = "task_scheduler:auth:lockout:"
+)
+
+const (
+	LockoutAccount = "account"
+	LockoutIP      = "ip"
+)
+
+var ErrLockedOut = errors.New("too many failed login attempts")
+
+// LockedOutError says which limit was hit and when logging in is allowed again.
+// Started is set on the failure that began the lockout.
+type LockedOutError struct {
+	Scope      string
+	RetryAfter time.Duration
+	Started    bool
+}
+
+func (e *LockedOutError) Error() string {
+	return fmt.Sprintf("too many failed login attempts for this %s, try again in %s", e.Scope, e.RetryAfter.Round(time.Second))
+}
+
+func (e *LockedOutError) Is(target error) bool {
+	return target == ErrLockedOut
+}
+
+// LoginThrottle counts failed logins per account and per client IP in Redis
+// and locks either out for a while once it fails too often. Unknown emails are
+// counted like real ones, so lockouts do not reveal which accounts exist.
+type LoginThrottle struct {
+	client *redisClient.Client
+	cfg    config.LoginThrottleConfig
+}
+
+func NewLoginThrottle(client *redisClient.Client, cfg config.LoginThrottleConfig) *LoginThrottle {
+	return &LoginThrottle{client: client, cfg: cfg}
+}
+
+func accountKey(email string) string {
+	return LockoutAccount + ":" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return LockoutIP + ":" + ip
+}
+
+// Check returns a LockedOutError while the account or the IP is locked out.
+func (t *LoginThrottle) Check(ctx context.Context, email, ip string) error {
+	pipe := t.client.Pipeline()
+	accountTTL := pipe.PTTL(ctx, loginLockoutKeyPrefix+accountKey(email))
+	ipTTL := pipe.PTTL(ctx, loginLockoutKeyPrefix+ipKey(ip))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to check login lockout: %w", err)
+	}
+
+	if ttl := accountTTL.Val(); ttl > 0 {
+		return &LockedOutError{Scope: LockoutAccount, RetryAfter: ttl}
+	}
+	if ttl := ipTTL.Val(); ttl > 0 {
+		return &LockedOutError{Scope: LockoutIP, RetryAfter: ttl}
+	}
+
+	return nil
+}
+
+// Fail records a failed login. It returns a LockedOutError when this failure
+// starts a lockout.
+func (t *LoginThrottle) Fail(ctx context.Context, email, ip string) error {
+	accountErr := t.fail(ctx, accountKey(email), t.cfg.MaxAccountFailures)
+	ipErr := t.fail(ctx, ipKey(ip), t.cfg.MaxIPFailures)
+
+	if accountErr != nil {
+		return accountErr
+	}
+	return ipErr
+}
+
+func (t *LoginThrottle) fail(ctx context.Context, key string, max int) error {
+	if max <= 0 {
+		return nil
+	}
+
+	failuresKey := loginFailuresKeyPrefix + key
+
+	failures, err := t.client.Incr(ctx, failuresKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to record failed login: %w", err)
+	}
+
+	// The window starts at the first failure.
+	if failures == 1 {
+		if err := t.client.Expire(ctx, failuresKey, t.cfg.FailureWindow).Err(); err != nil {
+			return fmt.Errorf("failed to record failed login: %w", err)
+		}
+	}
+
+	if failures < int64(max) {
+		return nil
+	}
+
+	pipe := t.client.TxPipeline()
+	pipe.Set(ctx, loginLockoutKeyPrefix+key, failures, t.cfg.LockoutDuration)
+	pipe.Del(ctx, failuresKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to lock out login: %w", err)
+	}
+
+	scope, _, _ := strings.Cut(key, ":")
+	return &LockedOutError{Scope: scope, RetryAfter: t.cfg.LockoutDuration, Started: true}
+}
+
+// Succeed clears the account's failures. The IP's are kept, so one valid
+// login cannot reset a guessing run against other accounts.
+func (t *LoginThrottle) Succeed(ctx context.Context, email string) error {
+	return t.client.Del(ctx, loginFailuresKeyPrefix+accountKey(email)).Err()
+}
@@ -0,0 +1,118 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/config"
+)
+
+var testThrottleConfig = config.LoginThrottleConfig{
+	MaxAccountFailures: 3,
+	MaxIPFailures:      5,
+	FailureWindow:      10 * time.Minute,
+	LockoutDuration:    15 * time.Minute,
+}
+
+func TestLoginThrottleLocksOutAccount(t *testing.T) {
+	client, server := newTestRedisServer(t)
+	throttle := NewLoginThrottle(client, testThrottleConfig)
+	ctx := context.Background()
+
+	for i := 1; i < testThrottleConfig.MaxAccountFailures; i++ {
+		if err := throttle.Fail(ctx, "ada@example.com", "10.0.0.1"); err != nil {
+			t.Fatalf("failure %d: got %v, want no lockout yet", i, err)
+		}
+	}
+
+	var lockedOut *LockedOutError
+	err := throttle.Fail(ctx, " Ada@Example.com ", "10.0.0.1")
+	if !errors.As(err, &lockedOut) || lockedOut.Scope != LockoutAccount || !lockedOut.Started {
+		t.Fatalf("got %v on the last allowed failure, want the account lockout to start", err)
+	}
+
+	err = throttle.Check(ctx, "ada@example.com", "10.0.0.2")
+	if !errors.As(err, &lockedOut) || lockedOut.Scope != LockoutAccount || lockedOut.Started {
+		t.Fatalf("got %v, want the account locked out from any IP", err)
+	}
+	if !errors.Is(err, ErrLockedOut) {
+		t.Error("LockedOutError does not match ErrLockedOut")
+	}
+	if err := throttle.Check(ctx, "grace@example.com", "10.0.0.1"); err != nil {
+		t.Errorf("got %v, want other accounts unaffected", err)
+	}
+
+	server.FastForward(testThrottleConfig.LockoutDuration)
+	if err := throttle.Check(ctx, "ada@example.com", "10.0.0.1"); err != nil {
+		t.Errorf("got %v, want the lockout over after its duration", err)
+	}
+}
+
+func TestLoginThrottleForgetsFailuresOutsideWindow(t *testing.T) {
+	client, server := newTestRedisServer(t)
+	throttle := NewLoginThrottle(client, testThrottleConfig)
+	ctx := context.Background()
+
+	for i := 1; i < testThrottleConfig.MaxAccountFailures; i++ {
+		if err := throttle.Fail(ctx, "ada@example.com", "10.0.0.1"); err != nil {
+			t.Fatalf("failure %d: got %v, want no lockout yet", i, err)
+		}
+	}
+
+	server.FastForward(testThrottleConfig.FailureWindow)
+
+	if err := throttle.Fail(ctx, "ada@example.com", "10.0.0.2"); err != nil {
+		t.Errorf("got %v, want failures from an earlier window forgotten", err)
+	}
+}
+
+func TestLoginThrottleLocksOutIPAcrossAccounts(t *testing.T) {
+	client, _ := newTestRedisServer(t)
+	throttle := NewLoginThrottle(client, testThrottleConfig)
+	ctx := context.Background()
+
+	emails := []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com"}
+	for _, email := range emails {
+		if err := throttle.Fail(ctx, email, "10.0.0.1"); err != nil {
+			t.Fatalf("got %v for %s, want no lockout yet", err, email)
+		}
+	}
+
+	// A valid login clears the account's failures but not the IP's, so it
+	// cannot reset a guessing run across accounts.
+	if err := throttle.Succeed(ctx, "a@example.com"); err != nil {
+		t.Fatalf("succeed failed: %v", err)
+	}
+
+	var lockedOut *LockedOutError
+	err := throttle.Fail(ctx, "e@example.com", "10.0.0.1")
+	if !errors.As(err, &lockedOut) || lockedOut.Scope != LockoutIP {
+		t.Fatalf("got %v, want the IP locked out", err)
+	}
+
+	if err := throttle.Check(ctx, "a@example.com", "10.0.0.1"); !errors.As(err, &lockedOut) || lockedOut.Scope != LockoutIP {
+		t.Errorf("got %v, want every account locked out from that IP", err)
+	}
+	if err := throttle.Check(ctx, "a@example.com", "10.0.0.2"); err != nil {
+		t.Errorf("got %v, want other IPs unaffected", err)
+	}
+}
+
+func TestLoginThrottleSucceedClearsAccountFailures(t *testing.T) {
+	client, _ := newTestRedisServer(t)
+	throttle := NewLoginThrottle(client, testThrottleConfig)
+	ctx := context.Background()
+
+	for i := 1; i < testThrottleConfig.MaxAccountFailures; i++ {
+		throttle.Fail(ctx, "ada@example.com", "10.0.0.1")
+	}
+	if err := throttle.Succeed(ctx, "ada@example.com"); err != nil {
+		t.Fatalf("succeed failed: %v", err)
+	}
+
+	if err := throttle.Fail(ctx, "ada@example.com", "10.0.0.2"); err != nil {
+		t.Errorf("got %v, want the count to start over after a valid login", err)
+	}
+}
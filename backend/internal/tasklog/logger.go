@@ -0,0 +1,113 @@
+package tasklog
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+)
+
+const (
+	maxLines      = 1000
+	maxLineBytes  = 4096
+	truncatedMark = " …(truncated)"
+	writeTimeout  = 2 * time.Second
+)
+
+type contextKey struct{}
+
+// Logger collects what an executor logs during one attempt of a task. Lines
+// are echoed to the process log with the task and attempt, and pushed to Redis
+// so they can be followed while the attempt runs.
+type Logger struct {
+	store   *Store
+	taskID  string
+	attempt int
+
+	mu          sync.Mutex
+	lines       []models.TaskLogLine
+	dropped     int
+	closed      bool
+	writeFailed bool
+}
+
+// NewContext returns a context carrying the attempt's logger.
+func NewContext(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger for the running attempt. Outside an attempt
+// it returns one that only writes to the process log.
+func FromContext(ctx context.Context) *Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*Logger); ok {
+		return logger
+	}
+	return &Logger{}
+}
+
+func (l *Logger) Printf(format string, args ...interface{}) {
+	message := strings.TrimRight(fmt.Sprintf(format, args...), "\n")
+
+	if l.store == nil {
+		log.Print(message)
+		return
+	}
+
+	log.Printf("[task %s #%d] %s", l.taskID, l.attempt, message)
+
+	if len(message) > maxLineBytes {
+		message = strings.ToValidUTF8(message[:maxLineBytes], "") + truncatedMark
+	}
+	line := models.TaskLogLine{Time: time.Now(), Message: message}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// An executor that outlives its timeout can keep logging after the
+	// attempt has been recorded; those lines are not kept.
+	if l.closed {
+		return
+	}
+	if len(l.lines) >= maxLines {
+		l.dropped++
+		return
+	}
+	l.lines = append(l.lines, line)
+
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+
+	if err := l.store.push(ctx, l.taskID, l.attempt, line); err != nil && !l.writeFailed {
+		l.writeFailed = true
+		log.Printf("ERROR: Failed to stream log for task %s attempt %d: %v", l.taskID, l.attempt, err)
+	}
+}
+
+// Close ends the attempt's log and moves it to Postgres. Lines logged after
+// Close are dropped.
+func (l *Logger) Close(ctx context.Context) {
+	if l.store == nil {
+		return
+	}
+
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return
+	}
+	l.closed = true
+	taskLog := &models.TaskLog{
+		TaskID:  l.taskID,
+		Attempt: l.attempt,
+		Lines:   l.lines,
+		Dropped: l.dropped,
+		Done:    true,
+	}
+	l.mu.Unlock()
+
+	l.store.finish(ctx, taskLog)
+}
@@ -0,0 +1,158 @@
+package tasklog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+	redisClient "github.com/rudraprasaaad/task-scheduler/internal/redis"
+	"github.com/rudraprasaaad/task-scheduler/internal/repository"
+)
+
+const (
+	keyPrefix = "task_scheduler:logs:"
+
+	// Logs of a running attempt live in Redis for at most runningTTL. Once
+	// the attempt ends they are kept there for finishedTTL so followers see
+	// the end of the stream, and read from Postgres after that.
+	runningTTL  = 24 * time.Hour
+	finishedTTL = 10 * time.Minute
+)
+
+var ErrNotFound = errors.New("no logs recorded")
+
+// Store keeps executor logs in Redis while an attempt runs and in Postgres
+// once it ends.
+type Store struct {
+	client *redisClient.Client
+	repo   *repository.TaskLogRepository
+}
+
+func NewStore(client *redisClient.Client, repo *repository.TaskLogRepository) *Store {
+	return &Store{client: client, repo: repo}
+}
+
+func linesKey(taskID string, attempt int) string {
+	return fmt.Sprintf("%s%s:%d", keyPrefix, taskID, attempt)
+}
+
+func metaKey(taskID string, attempt int) string {
+	return linesKey(taskID, attempt) + ":meta"
+}
+
+func latestKey(taskID string) string {
+	return keyPrefix + taskID + ":latest"
+}
+
+// Start opens the log for an attempt, clearing anything left in Redis by an
+// earlier run of the same attempt that was interrupted.
+func (s *Store) Start(ctx context.Context, taskID string, attempt int) *Logger {
+	pipe := s.client.TxPipeline()
+	pipe.Del(ctx, linesKey(taskID, attempt))
+	pipe.HSet(ctx, metaKey(taskID, attempt), "done", 0)
+	pipe.Expire(ctx, metaKey(taskID, attempt), runningTTL)
+	pipe.Set(ctx, latestKey(taskID), attempt, runningTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("ERROR: Failed to start log for task %s attempt %d: %v", taskID, attempt, err)
+	}
+
+	return &Logger{store: s, taskID: taskID, attempt: attempt}
+}
+
+func (s *Store) push(ctx context.Context, taskID string, attempt int, line models.TaskLogLine) error {
+	lineJSON, err := json.Marshal(line)
+	if err != nil {
+		return err
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.RPush(ctx, linesKey(taskID, attempt), lineJSON)
+	pipe.Expire(ctx, linesKey(taskID, attempt), runningTTL)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// finish saves the log to Postgres before marking it done in Redis, so a
+// follower that sees done has every line either way.
+func (s *Store) finish(ctx context.Context, taskLog *models.TaskLog) {
+	if err := s.repo.Save(ctx, taskLog); err != nil {
+		log.Printf("ERROR: %v", err)
+		return
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, metaKey(taskLog.TaskID, taskLog.Attempt), "done", 1, "dropped", taskLog.Dropped)
+	pipe.Expire(ctx, metaKey(taskLog.TaskID, taskLog.Attempt), finishedTTL)
+	pipe.Expire(ctx, linesKey(taskLog.TaskID, taskLog.Attempt), finishedTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("ERROR: Failed to finish log for task %s attempt %d: %v", taskLog.TaskID, taskLog.Attempt, err)
+	}
+}
+
+// LatestAttempt returns the most recent attempt with a log, or 0 when the
+// task has none.
+func (s *Store) LatestAttempt(ctx context.Context, taskID string) (int, error) {
+	attempt, err := s.client.Get(ctx, latestKey(taskID)).Int()
+	if err == nil {
+		return attempt, nil
+	}
+	if !errors.Is(err, redis.Nil) {
+		log.Printf("Failed to read latest log attempt for task %s from Redis: %v", taskID, err)
+	}
+
+	return s.repo.LatestAttempt(ctx, taskID)
+}
+
+// Read returns an attempt's log from line offset on. It reads Redis while the
+// attempt is running or recently finished, and Postgres otherwise.
+func (s *Store) Read(ctx context.Context, taskID string, attempt, offset int) (*models.TaskLog, error) {
+	offset = max(offset, 0)
+
+	// The done flag is read before the lines so a log reported done is
+	// complete.
+	meta, err := s.client.HGetAll(ctx, metaKey(taskID, attempt)).Result()
+	if err != nil {
+		log.Printf("Failed to read log for task %s attempt %d from Redis: %v", taskID, attempt, err)
+	}
+
+	if len(meta) == 0 {
+		taskLog, err := s.repo.Get(ctx, taskID, attempt)
+		if errors.Is(err, repository.ErrTaskLogNotFound) {
+			return nil, ErrNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		taskLog.Lines = taskLog.Lines[min(offset, len(taskLog.Lines)):]
+		return taskLog, nil
+	}
+
+	raw, err := s.client.LRange(ctx, linesKey(taskID, attempt), int64(offset), -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read task log: %w", err)
+	}
+
+	taskLog := &models.TaskLog{
+		TaskID:  taskID,
+		Attempt: attempt,
+		Lines:   make([]models.TaskLogLine, 0, len(raw)),
+		Done:    meta["done"] == "1",
+	}
+	taskLog.Dropped, _ = strconv.Atoi(meta["dropped"])
+
+	for _, item := range raw {
+		var line models.TaskLogLine
+		if err := json.Unmarshal([]byte(item), &line); err != nil {
+			line.Message = item
+		}
+		taskLog.Lines = append(taskLog.Lines, line)
+	}
+
+	return taskLog, nil
+}
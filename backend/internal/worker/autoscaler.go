@@ -0,0 +1,205 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+)
+
+type scaleMetrics struct {
+	activeWorkers int
+	busySlots     int
+	totalSlots    int
+	queueSize     int
+	oldestAge     time.Duration
+}
+
+func (m scaleMetrics) utilization() float64 {
+	if m.totalSlots == 0 {
+		return 0
+	}
+
+	return float64(m.busySlots) / float64(m.totalSlots)
+}
+
+func (p *Pool) runAutoscaler(ctx context.Context) {
+	ticker := time.NewTicker(p.workerCfg.Autoscale.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.stopChan:
+			return
+		case <-ticker.C:
+			p.autoscale(ctx)
+		}
+	}
+}
+
+func (p *Pool) autoscale(ctx context.Context) {
+	metrics, err := p.collectScaleMetrics()
+	if err != nil {
+		log.Printf("Autoscaler failed to collect metrics: %v", err)
+		return
+	}
+
+	desired := p.desiredWorkers(metrics, time.Now())
+	if desired == metrics.activeWorkers {
+		return
+	}
+
+	log.Printf("Autoscaler resizing pool from %d to %d workers (queue: %d, oldest ready: %v, utilisation: %.2f)",
+		metrics.activeWorkers, desired, metrics.queueSize, metrics.oldestAge.Round(time.Second), metrics.utilization())
+
+	p.scaleTo(ctx, desired)
+}
+
+func (p *Pool) collectScaleMetrics() (scaleMetrics, error) {
+	var metrics scaleMetrics
+
+	p.mutex.RLock()
+	for workerID, runtime := range p.runtime {
+		if runtime.draining {
+			continue
+		}
+
+		worker := p.workers[workerID]
+		metrics.activeWorkers++
+		metrics.busySlots += worker.CurrentTasks
+		metrics.totalSlots += worker.MaxConcurrentTasks
+	}
+	p.mutex.RUnlock()
+
+	queueSize, err := p.queue.Size()
+	if err != nil {
+		return metrics, err
+	}
+	metrics.queueSize = queueSize
+
+	oldestAge, err := p.queue.OldestReadyAge()
+	if err != nil {
+		return metrics, err
+	}
+	metrics.oldestAge = oldestAge
+
+	return metrics, nil
+}
+
+// desiredWorkers applies the scaling policy. Scaling up and down use separate
+// thresholds so the pool does not flap around a single value, and each
+// direction has its own cooldown; scaling down also waits out the down
+// cooldown after the last scale up.
+func (p *Pool) desiredWorkers(metrics scaleMetrics, now time.Time) int {
+	cfg := p.workerCfg.Autoscale
+	current := metrics.activeWorkers
+
+	p.mutex.RLock()
+	lastScaleUp, lastScaleDown := p.lastScaleUp, p.lastScaleDown
+	p.mutex.RUnlock()
+
+	backlogged := cfg.QueuePerWorker > 0 && metrics.queueSize > current*cfg.QueuePerWorker
+	underPressure := metrics.queueSize > 0 &&
+		(backlogged || metrics.oldestAge > cfg.MaxQueueAge || metrics.utilization() >= cfg.ScaleUpUtilization)
+
+	if underPressure {
+		if now.Sub(lastScaleUp) < cfg.ScaleUpCooldown {
+			return current
+		}
+
+		desired := current + 1
+		if cfg.QueuePerWorker > 0 {
+			desired = max(desired, (metrics.queueSize+cfg.QueuePerWorker-1)/cfg.QueuePerWorker)
+		}
+
+		return min(desired, p.workerCount)
+	}
+
+	if metrics.queueSize == 0 && metrics.utilization() <= cfg.ScaleDownUtilization {
+		if now.Sub(lastScaleDown) < cfg.ScaleDownCooldown || now.Sub(lastScaleUp) < cfg.ScaleDownCooldown {
+			return current
+		}
+
+		return max(current-1, p.minWorkers)
+	}
+
+	return current
+}
+
+// Resize sets the number of active workers by hand. The autoscaler, if
+// enabled, treats this like one of its own decisions and waits out the
+// cooldown before reconsidering.
+func (p *Pool) Resize(workers int) error {
+	if workers < p.minWorkers || workers > p.workerCount {
+		return fmt.Errorf("worker count must be between %d and %d", p.minWorkers, p.workerCount)
+	}
+
+	p.mutex.RLock()
+	ctx := p.ctx
+	p.mutex.RUnlock()
+
+	if ctx == nil {
+		return fmt.Errorf("worker pool has not been started")
+	}
+
+	log.Printf("Resizing worker pool to %d workers", workers)
+	p.scaleTo(ctx, workers)
+	return nil
+}
+
+func (p *Pool) scaleTo(ctx context.Context, target int) {
+	target = min(max(target, p.minWorkers), p.workerCount)
+
+	p.mutex.Lock()
+	p.targetWorkers = target
+
+	var active []string
+	for workerID, runtime := range p.runtime {
+		if !runtime.draining {
+			active = append(active, workerID)
+		}
+	}
+
+	now := time.Now()
+	if target > len(active) {
+		p.lastScaleUp = now
+	} else if target < len(active) {
+		p.lastScaleDown = now
+	}
+
+	sort.Slice(active, func(i, j int) bool {
+		a, b := p.workers[active[i]], p.workers[active[j]]
+		if a.CurrentTasks != b.CurrentTasks {
+			return a.CurrentTasks < b.CurrentTasks
+		}
+		return a.ID > b.ID
+	})
+	p.mutex.Unlock()
+
+	for i := len(active); i < target; i++ {
+		p.addWorker(ctx)
+	}
+
+	if excess := len(active) - target; excess > 0 {
+		for _, workerID := range active[:excess] {
+			go p.retireWorker(workerID)
+		}
+	}
+}
+
+func (p *Pool) retireWorker(workerID string) {
+	if err := p.Drain(workerID, p.workerCfg.DrainGracePeriod); err != nil {
+		log.Printf("Failed to retire worker %s: %v", workerID, err)
+		return
+	}
+
+	p.mutex.Lock()
+	delete(p.workers, workerID)
+	delete(p.runtime, workerID)
+	p.mutex.Unlock()
+
+	log.Printf("Worker %s retired from the pool", workerID)
+}
@@ -0,0 +1,146 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+)
+
+func (p *Pool) HasWorker(workerID string) bool {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	_, ok := p.workers[workerID]
+	return ok
+}
+
+// Drain stops the worker from claiming new tasks and waits up to grace for its
+// in-flight tasks to finish. Tasks still running after that are cancelled and
+// put back on the queue with their lease released, then the worker stops.
+func (p *Pool) Drain(workerID string, grace time.Duration) error {
+	p.mutex.Lock()
+	worker, ok := p.workers[workerID]
+	runtime := p.runtime[workerID]
+	if !ok {
+		p.mutex.Unlock()
+		return fmt.Errorf("worker %s is not part of this pool", workerID)
+	}
+
+	if runtime.draining {
+		p.mutex.Unlock()
+		return fmt.Errorf("worker %s is already draining", workerID)
+	}
+	runtime.draining = true
+	p.mutex.Unlock()
+
+	ctx := context.Background()
+	p.updateWorkerStatus(ctx, worker, models.WorkerStatusDraining)
+	log.Printf("Draining worker %s with a grace period of %v", workerID, grace)
+
+	if !p.waitForRunning(runtime, grace) {
+		interrupted := p.interruptRunning(runtime)
+		log.Printf("Worker %s grace period expired, re-queueing %d in-flight tasks", workerID, interrupted)
+		p.waitForRunning(runtime, 10*time.Second)
+	}
+
+	close(runtime.stop)
+	p.updateWorkerStatus(ctx, worker, models.WorkerStatusStopped)
+	log.Printf("Worker %s drained", workerID)
+
+	return nil
+}
+
+func (p *Pool) DrainAll(grace time.Duration) {
+	p.mutex.RLock()
+	workerIDs := make([]string, 0, len(p.workers))
+	for workerID, runtime := range p.runtime {
+		if !runtime.draining {
+			workerIDs = append(workerIDs, workerID)
+		}
+	}
+	p.mutex.RUnlock()
+
+	log.Printf("Draining %d workers with a grace period of %v", len(workerIDs), grace)
+
+	var wg sync.WaitGroup
+	for _, workerID := range workerIDs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := p.Drain(workerID, grace); err != nil {
+				log.Printf("Failed to drain worker %s: %v", workerID, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func (p *Pool) canClaim(runtime *workerRuntime) bool {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return !runtime.draining && !runtime.paused
+}
+
+func (p *Pool) waitForRunning(runtime *workerRuntime, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		p.mutex.RLock()
+		remaining := len(runtime.running)
+		p.mutex.RUnlock()
+
+		if remaining == 0 {
+			return true
+		}
+
+		if time.Now().After(deadline) {
+			return false
+		}
+
+		<-ticker.C
+	}
+}
+
+func (p *Pool) interruptRunning(runtime *workerRuntime) int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for _, running := range runtime.running {
+		running.interrupted = true
+		running.cancel()
+	}
+
+	return len(runtime.running)
+}
+
+func (p *Pool) wasInterrupted(running *runningTask) bool {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return running.interrupted
+}
+
+func (p *Pool) requeueInterrupted(task *models.Task) {
+	task.Status = models.TaskStatusPending
+	task.WorkerID = ""
+	task.StartedAt = nil
+	task.UpdatedAt = time.Now()
+
+	if err := p.taskRepo.Save(context.Background(), task); err != nil {
+		log.Printf("Failed to record re-queue of task %s: %v", task.ID, err)
+	}
+
+	if err := p.queue.Requeue(task); err != nil {
+		log.Printf("CRITICAL ERROR: Failed to re-queue interrupted task %s: %v", task.ID, err)
+		return
+	}
+
+	log.Printf("Task %s interrupted by drain and returned to the queue", task.ID)
+}
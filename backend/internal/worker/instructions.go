@@ -0,0 +1,214 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rudraprasaaad/task-scheduler/internal/executor"
+	"github.com/rudraprasaaad/task-scheduler/internal/models"
+)
+
+// pollInstructions picks up instructions queued for this pool's workers. Local
+// workers have no heartbeat stream, so the pool reads the same table the gRPC
+// heartbeat serves remote workers from and reports back in the same way.
+func (p *Pool) pollInstructions(ctx context.Context) {
+	p.mutex.RLock()
+	workerIDs := make([]string, 0, len(p.workers))
+	for workerID := range p.workers {
+		workerIDs = append(workerIDs, workerID)
+	}
+	p.mutex.RUnlock()
+
+	for _, workerID := range workerIDs {
+		instructions, err := p.instructionRepo.TakeUndelivered(ctx, workerID)
+		if err != nil {
+			log.Printf("Failed to load instructions for worker %s: %v", workerID, err)
+			continue
+		}
+
+		for _, instruction := range instructions {
+			p.applyInstruction(ctx, instruction)
+		}
+	}
+}
+
+func (p *Pool) applyInstruction(ctx context.Context, instruction *models.WorkerInstruction) {
+	log.Printf("Worker %s applying %s instruction %s", instruction.WorkerID, instruction.Type, instruction.ID)
+
+	if instruction.Type == models.InstructionDrain {
+		p.applyDrain(ctx, instruction)
+		return
+	}
+
+	var err error
+	switch instruction.Type {
+	case models.InstructionPause:
+		err = p.setPaused(ctx, instruction.WorkerID, true)
+	case models.InstructionResume:
+		err = p.setPaused(ctx, instruction.WorkerID, false)
+	case models.InstructionCancelTask:
+		err = p.cancelTask(instruction.WorkerID, instruction.Params["task_id"])
+	case models.InstructionReloadExecutors:
+		p.reloadExecutors()
+	case models.InstructionSetConcurrency:
+		err = p.setConcurrency(ctx, instruction.WorkerID, instruction.Params)
+	default:
+		err = fmt.Errorf("unsupported instruction type %q", instruction.Type)
+	}
+
+	p.ackInstruction(ctx, instruction, err)
+}
+
+func (p *Pool) ackInstruction(ctx context.Context, instruction *models.WorkerInstruction, applyErr error) {
+	status := models.InstructionStatusApplied
+	errorMsg := ""
+	if applyErr != nil {
+		status = models.InstructionStatusFailed
+		errorMsg = applyErr.Error()
+		log.Printf("Worker %s failed to apply instruction %s: %v", instruction.WorkerID, instruction.ID, applyErr)
+	}
+
+	if err := p.instructionRepo.Acknowledge(ctx, instruction.ID, instruction.WorkerID, status, errorMsg); err != nil {
+		log.Printf("Failed to acknowledge instruction %s: %v", instruction.ID, err)
+	}
+}
+
+// applyDrain acknowledges the instruction straight away and only marks it
+// applied once the worker has finished draining, which can take up to the
+// grace period.
+func (p *Pool) applyDrain(ctx context.Context, instruction *models.WorkerInstruction) {
+	grace := p.workerCfg.DrainGracePeriod
+	if value, ok := instruction.Params["grace_period"]; ok {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			p.ackInstruction(ctx, instruction, fmt.Errorf("invalid grace_period: %w", err))
+			return
+		}
+		grace = parsed
+	}
+
+	if err := p.instructionRepo.Acknowledge(ctx, instruction.ID, instruction.WorkerID, models.InstructionStatusAcknowledged, ""); err != nil {
+		log.Printf("Failed to acknowledge instruction %s: %v", instruction.ID, err)
+		return
+	}
+
+	go func() {
+		err := p.Drain(instruction.WorkerID, grace)
+		p.ackInstruction(context.Background(), instruction, err)
+	}()
+}
+
+func (p *Pool) setPaused(ctx context.Context, workerID string, paused bool) error {
+	p.mutex.Lock()
+	worker, ok := p.workers[workerID]
+	runtime := p.runtime[workerID]
+	if !ok {
+		p.mutex.Unlock()
+		return fmt.Errorf("worker %s is not part of this pool", workerID)
+	}
+
+	if runtime.draining {
+		p.mutex.Unlock()
+		return fmt.Errorf("worker %s is draining", workerID)
+	}
+
+	runtime.paused = paused
+	status := models.WorkerStatusPaused
+	if !paused {
+		status = models.WorkerStatusIdle
+		if worker.CurrentTasks > 0 {
+			status = models.WorkerStatusRunning
+		}
+	}
+	p.mutex.Unlock()
+
+	p.updateWorkerStatus(ctx, worker, status)
+	return nil
+}
+
+func (p *Pool) cancelTask(workerID, taskID string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	runtime, ok := p.runtime[workerID]
+	if !ok {
+		return fmt.Errorf("worker %s is not part of this pool", workerID)
+	}
+
+	running, ok := runtime.running[taskID]
+	if !ok {
+		return fmt.Errorf("task %s is not running on worker %s", taskID, workerID)
+	}
+
+	running.cancelled = true
+	running.cancel()
+	return nil
+}
+
+func (p *Pool) wasCancelled(running *runningTask) bool {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return running.cancelled
+}
+
+// reloadExecutors swaps in a fresh registry. Tasks already running keep the
+// executor they started with.
+func (p *Pool) reloadExecutors() {
+	registry := executor.NewExecutorRegistry()
+
+	p.mutex.Lock()
+	p.executor = registry
+	p.mutex.Unlock()
+
+	log.Println("Executor registry reloaded")
+}
+
+func (p *Pool) setConcurrency(ctx context.Context, workerID string, params map[string]string) error {
+	p.mutex.Lock()
+	worker, ok := p.workers[workerID]
+	if !ok {
+		p.mutex.Unlock()
+		return fmt.Errorf("worker %s is not part of this pool", workerID)
+	}
+
+	typeLimits := maps.Clone(worker.TypeLimits)
+	if typeLimits == nil {
+		typeLimits = make(map[string]int)
+	}
+	slots := worker.MaxConcurrentTasks
+
+	for key, value := range params {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			p.mutex.Unlock()
+			return fmt.Errorf("invalid value %q for %s", value, key)
+		}
+
+		if key == "slots" {
+			slots = n
+			continue
+		}
+
+		taskType := strings.TrimPrefix(key, models.TypeLimitParamPrefix)
+		if n == 0 {
+			delete(typeLimits, taskType)
+		} else {
+			typeLimits[taskType] = n
+		}
+	}
+
+	worker.MaxConcurrentTasks = slots
+	worker.TypeLimits = typeLimits
+	status := worker.Status
+	p.mutex.Unlock()
+
+	log.Printf("Worker %s concurrency set to %d slots, type limits %v", workerID, slots, typeLimits)
+	p.updateWorkerStatus(ctx, worker, status)
+	return nil
+}
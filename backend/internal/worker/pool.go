@@ -2,18 +2,21 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"strconv"
+	"maps"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/rudraprasaaad/task-scheduler/internal/cache"
+	"github.com/rudraprasaaad/task-scheduler/internal/config"
 	"github.com/rudraprasaaad/task-scheduler/internal/executor"
 	"github.com/rudraprasaaad/task-scheduler/internal/models"
 	"github.com/rudraprasaaad/task-scheduler/internal/queue"
 	"github.com/rudraprasaaad/task-scheduler/internal/repository"
+	"github.com/rudraprasaaad/task-scheduler/internal/tasklog"
 )
 
 type Worker struct {
@@ -31,60 +34,131 @@ const (
 	WorkerStatusStopped WorkerStatus = "stopped"
 )
 
+// executorStopGrace is how long an executor gets to return once its context
+// is cancelled before the pool gives up waiting on it.
+const executorStopGrace = 10 * time.Second
+
+// errTaskAbandoned means the executor kept running past executorStopGrace.
+var errTaskAbandoned = errors.New("executor did not stop after cancellation")
+
+type runningTask struct {
+	task        *models.Task
+	cancel      context.CancelFunc
+	interrupted bool
+	cancelled   bool
+}
+
+type workerRuntime struct {
+	draining bool
+	paused   bool
+	running  map[string]*runningTask
+	stop     chan struct{}
+}
+
 type Pool struct {
 	workers    map[string]*models.Worker
+	runtime    map[string]*workerRuntime
 	queue      *queue.RedisQueue
 	executor   *executor.ExecutorRegistry
 	workerRepo *repository.WorkerRepository
 	taskRepo   *repository.TaskRepository
 	execRepo   *repository.TaskExecutionRepository
 	cache      *cache.RedisCache
-
-	workerCount int
-	stopChan    chan struct{}
-	wg          sync.WaitGroup
-	mutex       sync.RWMutex
+	logs       *tasklog.Store
+
+	instructionRepo *repository.WorkerInstructionRepository
+
+	workerCount   int
+	minWorkers    int
+	targetWorkers int
+	nextWorkerNum int
+	lastScaleUp   time.Time
+	lastScaleDown time.Time
+	ctx           context.Context
+
+	workerCfg config.WorkerConfig
+	stopChan  chan struct{}
+	wg        sync.WaitGroup
+	mutex     sync.RWMutex
 }
 
-func NewPool(workerCount int, queue *queue.RedisQueue, workerRepo *repository.WorkerRepository, taskRepo *repository.TaskRepository, execRepo *repository.TaskExecutionRepository, cache *cache.RedisCache) *Pool {
+func NewPool(workerCount int, workerCfg config.WorkerConfig, queue *queue.RedisQueue, workerRepo *repository.WorkerRepository, taskRepo *repository.TaskRepository, execRepo *repository.TaskExecutionRepository, instructionRepo *repository.WorkerInstructionRepository, cache *cache.RedisCache, logs *tasklog.Store) *Pool {
 	return &Pool{
-		workers:     make(map[string]*models.Worker),
-		queue:       queue,
-		executor:    executor.NewExecutorRegistry(),
-		workerRepo:  workerRepo,
-		taskRepo:    taskRepo,
-		execRepo:    execRepo,
-		cache:       cache,
-		workerCount: workerCount,
-		stopChan:    make(chan struct{}),
+		workers:         make(map[string]*models.Worker),
+		runtime:         make(map[string]*workerRuntime),
+		queue:           queue,
+		executor:        executor.NewExecutorRegistry(),
+		workerRepo:      workerRepo,
+		taskRepo:        taskRepo,
+		execRepo:        execRepo,
+		instructionRepo: instructionRepo,
+		cache:           cache,
+		logs:            logs,
+		workerCount:     workerCount,
+		minWorkers:      min(max(workerCfg.Autoscale.MinWorkers, 1), workerCount),
+		workerCfg:       workerCfg,
+		stopChan:        make(chan struct{}),
 	}
 }
 
 func (p *Pool) Start(ctx context.Context) {
-	log.Printf("Starting worker pool with %d workers", p.workerCount)
-
-	for i := 0; i < p.workerCount; i++ {
-		workerID := fmt.Sprintf("worker-%d", i+1)
-		worker := &models.Worker{
-			ID:       workerID,
-			Status:   models.WorkerStatusIdle,
-			LastSeen: time.Now(),
-		}
+	initial := p.workerCount
+	if p.workerCfg.Autoscale.Enabled {
+		initial = p.minWorkers
+	}
 
-		if err := p.registerWorker(ctx, worker); err != nil {
-			log.Printf("Failed to register worker %s: %v", workerID, err)
-			continue
-		}
+	log.Printf("Starting worker pool with %d workers (min %d, max %d)", initial, p.minWorkers, p.workerCount)
 
-		p.mutex.Lock()
-		p.workers[workerID] = worker
-		p.mutex.Unlock()
+	p.mutex.Lock()
+	p.ctx = ctx
+	p.targetWorkers = initial
+	p.mutex.Unlock()
 
-		p.wg.Add(1)
-		go p.runWorker(ctx, worker)
+	for i := 0; i < initial; i++ {
+		p.addWorker(ctx)
 	}
 
 	go p.monitorWorkers(ctx)
+
+	if p.workerCfg.Autoscale.Enabled {
+		go p.runAutoscaler(ctx)
+	}
+}
+
+func (p *Pool) addWorker(ctx context.Context) {
+	p.mutex.Lock()
+	p.nextWorkerNum++
+	workerID := fmt.Sprintf("worker-%d", p.nextWorkerNum)
+	p.mutex.Unlock()
+
+	worker := &models.Worker{
+		ID:                 workerID,
+		Status:             models.WorkerStatusIdle,
+		LastSeen:           time.Now(),
+		Labels:             p.workerCfg.Labels,
+		TaskTypes:          p.workerCfg.TaskTypes,
+		MaxConcurrentTasks: max(p.workerCfg.Slots, 1),
+		TypeLimits:         p.workerCfg.TypeLimits,
+		RunningByType:      make(map[string]int),
+	}
+
+	if err := p.registerWorker(ctx, worker); err != nil {
+		log.Printf("Failed to register worker %s: %v", workerID, err)
+		return
+	}
+
+	runtime := &workerRuntime{
+		running: make(map[string]*runningTask),
+		stop:    make(chan struct{}),
+	}
+
+	p.mutex.Lock()
+	p.workers[workerID] = worker
+	p.runtime[workerID] = runtime
+	p.mutex.Unlock()
+
+	p.wg.Add(1)
+	go p.runWorker(ctx, worker, runtime)
 }
 
 func (p *Pool) Stop() {
@@ -106,7 +180,7 @@ func (p *Pool) registerWorker(ctx context.Context, worker *models.Worker) error
 	return p.workerRepo.Register(ctx, worker)
 }
 
-func (p *Pool) runWorker(ctx context.Context, worker *models.Worker) {
+func (p *Pool) runWorker(ctx context.Context, worker *models.Worker, runtime *workerRuntime) {
 	defer p.wg.Done()
 	log.Printf("Worker %s started", worker.ID)
 
@@ -123,39 +197,163 @@ func (p *Pool) runWorker(ctx context.Context, worker *models.Worker) {
 			log.Printf("Worker %s stopping", worker.ID)
 			p.updateWorkerStatus(ctx, worker, models.WorkerStatusStopped)
 			return
+		case <-runtime.stop:
+			log.Printf("Worker %s drained and stopped", worker.ID)
+			return
 		case <-ticker.C:
-			tasks, err := p.queue.Dequeue(1)
-			if err != nil {
-				log.Printf("Worker %s failed to dequeue tasks: %v", worker.ID, err)
-				continue
-			}
+			p.fillSlots(ctx, worker, runtime)
+		}
+	}
+}
 
-			if len(tasks) == 0 {
-				continue
-			}
+// fillSlots claims tasks one at a time until the worker has no free slot or
+// the queue has nothing it can run, so per-type limits hold within a batch.
+func (p *Pool) fillSlots(ctx context.Context, worker *models.Worker, runtime *workerRuntime) {
+	for {
+		if !p.canClaim(runtime) {
+			return
+		}
 
-			task := tasks[0]
-			p.processTask(ctx, worker, task)
+		snapshot := p.snapshotWorker(worker)
+		if snapshot.CurrentTasks >= snapshot.MaxConcurrentTasks {
+			return
 		}
+
+		tasks, err := p.queue.Dequeue(snapshot, 1)
+		if err != nil {
+			log.Printf("Worker %s failed to dequeue tasks: %v", worker.ID, err)
+			return
+		}
+
+		if len(tasks) == 0 {
+			return
+		}
+
+		task := tasks[0]
+		p.acquireSlot(ctx, worker, task)
+
+		taskCtx, cancel := context.WithCancel(context.Background())
+		running := &runningTask{task: task, cancel: cancel}
+
+		p.mutex.Lock()
+		runtime.running[task.ID] = running
+		p.mutex.Unlock()
+
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			defer cancel()
+			p.processTask(ctx, taskCtx, worker, running)
+
+			p.mutex.Lock()
+			delete(runtime.running, task.ID)
+			p.mutex.Unlock()
+		}()
 	}
 }
 
-func (p *Pool) processTask(ctx context.Context, worker *models.Worker, task *models.Task) {
+func (p *Pool) snapshotWorker(worker *models.Worker) *models.Worker {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	snapshot := *worker
+	snapshot.RunningByType = maps.Clone(worker.RunningByType)
+	return &snapshot
+}
+
+func (p *Pool) acquireSlot(ctx context.Context, worker *models.Worker, task *models.Task) {
+	p.mutex.Lock()
+	worker.CurrentTasks++
+	worker.RunningByType[task.Type]++
+	p.mutex.Unlock()
+
 	p.updateWorkerStatus(ctx, worker, models.WorkerStatusRunning)
-	p.cache.SetWorkerStats(ctx, worker.ID, map[string]interface{}{"status": "running", "task_id": task.ID})
+}
+
+func (p *Pool) releaseSlot(ctx context.Context, worker *models.Worker, task *models.Task, finished bool) {
+	p.mutex.Lock()
+	worker.CurrentTasks--
+	worker.RunningByType[task.Type]--
+	if worker.RunningByType[task.Type] <= 0 {
+		delete(worker.RunningByType, task.Type)
+	}
+	if finished {
+		worker.TasksRun++
+	}
+	status := models.WorkerStatusRunning
+	if worker.CurrentTasks == 0 {
+		status = models.WorkerStatusIdle
+	}
+	if worker.Status == models.WorkerStatusPaused || worker.Status == models.WorkerStatusDraining || worker.Status == models.WorkerStatusStopped {
+		status = worker.Status
+	}
+	p.mutex.Unlock()
+
+	p.updateWorkerStatus(ctx, worker, status)
+}
 
+// processTask runs a claimed task under taskCtx, which is independent of the
+// pool's lifetime context so shutting the server down does not tear down
+// in-flight work; only a drain that runs out of grace period cancels it.
+func (p *Pool) processTask(ctx, taskCtx context.Context, worker *models.Worker, running *runningTask) {
+	task := running.task
 	task.Status = models.TaskStatusRunning
 	task.WorkerID = worker.ID
 	startTime := time.Now()
 	task.StartedAt = &startTime
 
+	task.UpdatedAt = startTime
+
 	log.Printf("Worker %s processing task %s (%s)", worker.ID, task.ID, task.Type)
 
-	execErr := p.executeTask(ctx, task)
+	if err := p.taskRepo.Save(ctx, task); err != nil {
+		log.Printf("Failed to record start of task %s: %v", task.ID, err)
+	}
+	if err := p.queue.UpdateTask(task); err != nil {
+		log.Printf("Failed to update queued copy of task %s: %v", task.ID, err)
+	}
+
+	// Attempts are numbered by their execution records, which are written
+	// when an attempt ends, so this one is the next after those.
+	attempt := 1
+	if count, err := p.execRepo.CountByTaskID(ctx, task.ID); err != nil {
+		log.Printf("Failed to count attempts of task %s: %v", task.ID, err)
+	} else {
+		attempt = count + 1
+	}
+	taskLog := p.logs.Start(ctx, task.ID, attempt)
+
+	stopped, execErr := p.executeTask(tasklog.NewContext(taskCtx, taskLog), task)
+	abandoned := errors.Is(execErr, errTaskAbandoned)
 	endTime := time.Now()
 	durationMs := endTime.Sub(startTime).Milliseconds()
 
 	if execErr != nil {
+		taskLog.Printf("attempt failed: %v", execErr)
+	}
+	logCtx, logCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	taskLog.Close(logCtx)
+	logCancel()
+
+	if p.wasInterrupted(running) && !abandoned {
+		p.requeueInterrupted(task)
+		p.releaseSlot(ctx, worker, task, false)
+		return
+	}
+
+	if p.wasCancelled(running) {
+		task.Status = models.TaskStatusCancelled
+		task.CompletedAt = &endTime
+		task.Error = "cancelled by instruction"
+		log.Printf("Task %s cancelled on worker %s", task.ID, worker.ID)
+	} else if abandoned {
+		// The executor may still finish the work, so running the task again
+		// could do it twice.
+		task.Status = models.TaskStatusFailed
+		task.CompletedAt = &endTime
+		task.Error = execErr.Error()
+		log.Printf("Task %s abandoned on worker %s: %v", task.ID, worker.ID, execErr)
+	} else if execErr != nil {
 		p.handleTaskFailure(task, execErr)
 	} else {
 		p.handleTaskSuccess(task)
@@ -164,7 +362,8 @@ func (p *Pool) processTask(ctx context.Context, worker *models.Worker, task *mod
 	dbCtx, dbCancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer dbCancel()
 
-	if err := p.taskRepo.Create(dbCtx, task); err != nil {
+	task.UpdatedAt = time.Now()
+	if err := p.taskRepo.Save(dbCtx, task); err != nil {
 		log.Printf("CRITICAL ERROR: Failed to persist final task state for task %s: %v", task.ID, err)
 	} else {
 		executionRecord := &models.TaskExecution{
@@ -173,9 +372,14 @@ func (p *Pool) processTask(ctx context.Context, worker *models.Worker, task *mod
 			WorkerID:        worker.ID,
 			StartedAt:       startTime,
 			CompletedAt:     endTime,
-			ExecutionTimeMs: strconv.FormatInt(durationMs, 10),
+			ExecutionTimeMs: durationMs,
 		}
-		if execErr != nil {
+		if task.Status == models.TaskStatusCancelled {
+			executionRecord.Status = string(models.TaskStatusCancelled)
+		} else if abandoned {
+			executionRecord.Status = "abandoned"
+			executionRecord.Error = execErr.Error()
+		} else if execErr != nil {
 			executionRecord.Status = "failed"
 			executionRecord.Error = execErr.Error()
 		} else {
@@ -187,22 +391,69 @@ func (p *Pool) processTask(ctx context.Context, worker *models.Worker, task *mod
 		}
 	}
 
-	worker.TasksRun++
+	// Retries were re-enqueued by handleTaskFailure; final states release the
+	// queue lease here.
+	if task.Status != models.TaskStatusPending {
+		if err := p.queue.UpdateTask(task); err != nil {
+			log.Printf("Failed to update queued copy of task %s: %v", task.ID, err)
+		}
+	}
+
 	p.workerRepo.IncrementTaskCount(ctx, worker.ID)
-	p.updateWorkerStatus(ctx, worker, models.WorkerStatusIdle)
-	p.cache.SetWorkerStats(ctx, worker.ID, map[string]interface{}{"status": "idle"})
+
+	// An abandoned executor keeps its slot until it actually returns.
+	if abandoned {
+		go func() {
+			<-stopped
+			log.Printf("Abandoned executor for task %s returned on worker %s", task.ID, worker.ID)
+			p.releaseSlot(context.Background(), worker, task, true)
+		}()
+		return
+	}
+	p.releaseSlot(ctx, worker, task, true)
 }
 
-func (p *Pool) executeTask(ctx context.Context, task *models.Task) error {
-	exec, err := p.executor.GetExecutor(task.Type)
+// executeTask runs the task's executor and waits for it to return, giving it
+// executorStopGrace to stop after a timeout or cancellation. An executor that
+// outlasts that is abandoned: executeTask returns errTaskAbandoned and a
+// channel that is closed once the executor finally returns.
+func (p *Pool) executeTask(ctx context.Context, task *models.Task) (<-chan struct{}, error) {
+	p.mutex.RLock()
+	registry := p.executor
+	p.mutex.RUnlock()
+
+	exec, err := registry.GetExecutor(task.Type)
 	if err != nil {
-		return fmt.Errorf("executor not found: %v", err)
+		return nil, fmt.Errorf("executor not found: %v", err)
 	}
 
 	taskCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	return exec.Execute(taskCtx, task)
+	done := make(chan error, 1)
+	go func() {
+		done <- exec.Execute(taskCtx, task)
+	}()
+
+	select {
+	case err := <-done:
+		return nil, err
+	case <-taskCtx.Done():
+	}
+
+	cause := taskCtx.Err()
+	select {
+	case <-done:
+		return nil, cause
+	case <-time.After(executorStopGrace):
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		<-done
+		close(stopped)
+	}()
+	return stopped, fmt.Errorf("%w within %v: %v", errTaskAbandoned, executorStopGrace, cause)
 }
 
 func (p *Pool) handleTaskSuccess(task *models.Task) {
@@ -245,17 +496,24 @@ func (p *Pool) updateWorkerStatus(ctx context.Context, worker *models.Worker, st
 	p.mutex.Lock()
 	worker.Status = status
 	worker.LastSeen = time.Now()
+	stats := map[string]interface{}{
+		"status":               status,
+		"tasks_run":            worker.TasksRun,
+		"last_seen":            worker.LastSeen.UTC().Format(time.RFC3339),
+		"labels":               worker.Labels,
+		"task_types":           worker.TaskTypes,
+		"current_tasks":        worker.CurrentTasks,
+		"max_concurrent_tasks": worker.MaxConcurrentTasks,
+		"running_by_type":      maps.Clone(worker.RunningByType),
+	}
+	currentTasks := worker.CurrentTasks
 	p.mutex.Unlock()
 
-	if err := p.workerRepo.UpdateStatus(ctx, worker.ID, status); err != nil {
+	if err := p.workerRepo.UpdateLoad(ctx, worker.ID, status, currentTasks); err != nil {
 		log.Printf("Failed to update worker status in database: %v", err)
 	}
 
-	p.cache.SetWorkerStats(ctx, worker.ID, map[string]interface{}{
-		"status":    status,
-		"tasks_run": worker.TasksRun,
-		"last_seen": worker.LastSeen.UTC().Format(time.RFC3339),
-	})
+	p.cache.SetWorkerStats(ctx, worker.ID, stats)
 }
 
 func (p *Pool) monitorWorkers(ctx context.Context) {
@@ -265,6 +523,9 @@ func (p *Pool) monitorWorkers(ctx context.Context) {
 	cleanupTicker := time.NewTicker(1 * time.Minute)
 	defer cleanupTicker.Stop()
 
+	instructionTicker := time.NewTicker(2 * time.Second)
+	defer instructionTicker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -273,6 +534,8 @@ func (p *Pool) monitorWorkers(ctx context.Context) {
 			return
 		case <-ticker.C:
 			p.logWorkerStats()
+		case <-instructionTicker.C:
+			p.pollInstructions(ctx)
 		case <-cleanupTicker.C:
 			if err := p.workerRepo.CleanupStaleWorkers(ctx, 2*time.Minute); err != nil {
 				log.Printf("Failed to cleanup stale workers: %v", err)
@@ -283,59 +546,115 @@ func (p *Pool) monitorWorkers(ctx context.Context) {
 
 func (p *Pool) logWorkerStats() {
 	p.mutex.RLock()
-	defer p.mutex.RUnlock()
 
-	var idle, running, stopped int
+	var idle, running, paused, stopped, busySlots, totalSlots int
 	totalTasks := 0
 
 	for _, worker := range p.workers {
+		busySlots += worker.CurrentTasks
+		totalSlots += worker.MaxConcurrentTasks
 		switch worker.Status {
 		case models.WorkerStatusIdle:
 			idle++
 		case models.WorkerStatusRunning:
 			running++
+		case models.WorkerStatusPaused:
+			paused++
 		case models.WorkerStatusStopped:
 			stopped++
 		}
 		totalTasks += worker.TasksRun
 	}
+	p.mutex.RUnlock()
 
 	queueSize, _ := p.queue.Size()
 
-	log.Printf("Worker Stats - Idle: %d, Running: %d, Stopped: %d, Total Tasks: %d, Queue Size: %d",
-		idle, running, stopped, totalTasks, queueSize)
+	log.Printf("Worker Stats - Idle: %d, Running: %d, Paused: %d, Stopped: %d, Slots: %d/%d, Total Tasks: %d, Queue Size: %d",
+		idle, running, paused, stopped, busySlots, totalSlots, totalTasks, queueSize)
 }
 
 func (p *Pool) GetWorkerStats() (map[string]interface{}, error) {
 	ctx := context.Background()
+
+	// Copy the local workers under the lock and go to Redis and the
+	// database after releasing it, so slow I/O does not hold up the slots.
 	p.mutex.RLock()
-	defer p.mutex.RUnlock()
+	local := make([]*models.Worker, 0, len(p.workers))
+	var activeWorkers int
+	for workerID, worker := range p.workers {
+		snapshot := *worker
+		snapshot.RunningByType = maps.Clone(worker.RunningByType)
+		local = append(local, &snapshot)
+		if !p.runtime[workerID].draining {
+			activeWorkers++
+		}
+	}
+	targetWorkers := p.targetWorkers
+	p.mutex.RUnlock()
 
-	workers := make([]map[string]interface{}, 0, len(p.workers))
-	for workerID := range p.workers {
-		stats, err := p.cache.GetWorkerStats(ctx, workerID)
+	var busySlots, totalSlots int
+	workers := make([]map[string]interface{}, 0, len(local))
+	localIDs := make(map[string]bool, len(local))
+	for _, worker := range local {
+		busySlots += worker.CurrentTasks
+		totalSlots += worker.MaxConcurrentTasks
+		localIDs[worker.ID] = true
+
+		stats, err := p.cache.GetWorkerStats(ctx, worker.ID)
 		if err != nil {
-			dbWorker, dbErr := p.workerRepo.GetByID(ctx, workerID)
-			if dbErr == nil {
-				workers = append(workers, map[string]interface{}{
-					"id":        dbWorker.ID,
-					"status":    dbWorker.Status,
-					"last_seen": dbWorker.LastSeen,
-					"tasks_run": dbWorker.TasksRun,
-				})
+			stats = map[string]interface{}{
+				"status":               worker.Status,
+				"last_seen":            worker.LastSeen,
+				"tasks_run":            worker.TasksRun,
+				"labels":               worker.Labels,
+				"task_types":           worker.TaskTypes,
+				"current_tasks":        worker.CurrentTasks,
+				"max_concurrent_tasks": worker.MaxConcurrentTasks,
+				"running_by_type":      worker.RunningByType,
 			}
-			continue
 		}
 
-		stats["id"] = workerID
+		stats["id"] = worker.ID
 		workers = append(workers, stats)
 	}
 
+	remoteWorkers, err := p.workerRepo.GetAll(ctx)
+	if err != nil {
+		log.Printf("Failed to load remote workers for stats: %v", err)
+	}
+
+	for _, remote := range remoteWorkers {
+		if localIDs[remote.ID] || remote.Status == models.WorkerStatusStopped {
+			continue
+		}
+
+		busySlots += remote.CurrentTasks
+		totalSlots += remote.MaxConcurrentTasks
+		workers = append(workers, map[string]interface{}{
+			"id":                   remote.ID,
+			"status":               remote.Status,
+			"last_seen":            remote.LastSeen,
+			"tasks_run":            remote.TasksRun,
+			"labels":               remote.Labels,
+			"task_types":           remote.TaskTypes,
+			"current_tasks":        remote.CurrentTasks,
+			"max_concurrent_tasks": remote.MaxConcurrentTasks,
+			"remote":               true,
+		})
+	}
+
 	queueSize, _ := p.queue.Size()
 
 	return map[string]interface{}{
-		"workers":       workers,
-		"total_workers": len(p.workers),
-		"queue_size":    queueSize,
+		"workers":         workers,
+		"total_workers":   len(workers),
+		"current_workers": activeWorkers,
+		"target_workers":  targetWorkers,
+		"min_workers":     p.minWorkers,
+		"max_workers":     p.workerCount,
+		"autoscale":       p.workerCfg.Autoscale.Enabled,
+		"busy_slots":      busySlots,
+		"total_slots":     totalSlots,
+		"queue_size":      queueSize,
 	}, nil
 }
@@ -0,0 +1,7 @@
+-- ==== WORKER CAPABILITIES & TASK ROUTING ====
+ALTER TABLE workers ADD COLUMN IF NOT EXISTS labels JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE workers ADD COLUMN IF NOT EXISTS task_types TEXT[] NOT NULL DEFAULT '{}';
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS selector JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_selector ON tasks USING GIN (selector);
@@ -0,0 +1,3 @@
+-- ==== WORKER CONCURRENCY ====
+ALTER TABLE workers ADD COLUMN IF NOT EXISTS max_concurrent_tasks INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE workers ADD COLUMN IF NOT EXISTS current_tasks INTEGER NOT NULL DEFAULT 0;
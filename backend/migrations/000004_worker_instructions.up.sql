@@ -0,0 +1,16 @@
+-- ==== WORKER INSTRUCTIONS ====
+CREATE TABLE IF NOT EXISTS worker_instructions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    worker_id VARCHAR(100) NOT NULL REFERENCES workers(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    params JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    error TEXT,
+    created_by VARCHAR(100),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    acknowledged_at TIMESTAMP WITH TIME ZONE,
+    applied_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_worker_instructions_worker_status ON worker_instructions (worker_id, status);
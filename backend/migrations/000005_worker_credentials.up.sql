@@ -0,0 +1,22 @@
+-- ==== WORKER ENROLLMENT ====
+CREATE TABLE IF NOT EXISTS worker_enrollment_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    worker_id VARCHAR(100),
+    created_by VARCHAR(100),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    used_by VARCHAR(100)
+);
+
+CREATE TABLE IF NOT EXISTS worker_credentials (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    worker_id VARCHAR(100) NOT NULL,
+    secret_hash VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_worker_credentials_worker_id ON worker_credentials (worker_id) WHERE revoked_at IS NULL;
@@ -0,0 +1,6 @@
+-- ==== TASK LISTING ====
+CREATE INDEX IF NOT EXISTS idx_tasks_created_at_id ON tasks (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_tasks_completed_at ON tasks (completed_at) WHERE completed_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_worker_id ON tasks (worker_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_name_prefix ON tasks (name text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_tasks_payload ON tasks USING GIN (payload jsonb_path_ops);
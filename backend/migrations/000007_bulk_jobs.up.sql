@@ -0,0 +1,28 @@
+-- ==== BULK TASK OPERATIONS ====
+CREATE TABLE IF NOT EXISTS bulk_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    operation VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    params JSONB NOT NULL DEFAULT '{}',
+    total INTEGER NOT NULL DEFAULT 0,
+    processed INTEGER NOT NULL DEFAULT 0,
+    succeeded INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    created_by VARCHAR(100),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE IF NOT EXISTS bulk_job_items (
+    job_id UUID NOT NULL REFERENCES bulk_jobs(id) ON DELETE CASCADE,
+    item_index INTEGER NOT NULL,
+    task_id VARCHAR(100),
+    ok BOOLEAN NOT NULL,
+    error TEXT,
+    PRIMARY KEY (job_id, item_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bulk_jobs_created_at ON bulk_jobs (created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_bulk_job_items_failed ON bulk_job_items (job_id) WHERE NOT ok;
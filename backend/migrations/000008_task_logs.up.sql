@@ -0,0 +1,11 @@
+-- ==== EXECUTOR LOGS ====
+-- Lines an executor logged during one attempt, copied here from Redis once the
+-- attempt ends.
+CREATE TABLE IF NOT EXISTS task_logs (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    attempt INTEGER NOT NULL,
+    lines JSONB NOT NULL DEFAULT '[]',
+    dropped INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, attempt)
+);
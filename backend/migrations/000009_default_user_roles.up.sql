@@ -0,0 +1,7 @@
+-- ==== ROLE BACKFILL ====
+-- Users registered before roles were enforced get the default user role.
+INSERT INTO user_roles (user_id, role_id)
+SELECT users.id, roles.id
+FROM users, roles
+WHERE roles.name = 'user'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,19 @@
+-- ==== TENANTS & TASK OWNERSHIP ====
+CREATE TABLE IF NOT EXISTS tenants (
+    id VARCHAR(100) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Existing users and tasks land in the default tenant. Tasks created by the
+-- scheduler itself, such as cron jobs, belong to the system tenant.
+INSERT INTO tenants (id, name) VALUES ('default', 'Default'), ('system', 'System') ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(100) NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(100) NOT NULL DEFAULT 'default';
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS created_by VARCHAR(100);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_tenant_created_at ON tasks (tenant_id, created_at DESC, id DESC);
+
+ALTER TABLE bulk_jobs ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(100);
@@ -0,0 +1,6 @@
+-- ==== TENANT QUOTAS ====
+-- NULL keeps the server-wide default for that limit.
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS max_pending INTEGER CHECK (max_pending >= 0);
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS enqueue_per_minute INTEGER CHECK (enqueue_per_minute >= 0);
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS max_running INTEGER CHECK (max_running >= 0);
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS weight INTEGER CHECK (weight >= 1);
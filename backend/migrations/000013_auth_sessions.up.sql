@@ -0,0 +1,18 @@
+-- ==== LOGIN SESSIONS & REFRESH TOKENS ====
+-- A session starts at login and holds the hash of its current refresh token.
+-- Each refresh rotates the token and keeps the previous hash, so replaying an
+-- already used token is detected and ends the session.
+CREATE TABLE IF NOT EXISTS auth_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    refresh_hash VARCHAR(64) NOT NULL,
+    previous_hash VARCHAR(64),
+    user_agent TEXT,
+    ip_address VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    refreshed_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_user_id ON auth_sessions (user_id) WHERE revoked_at IS NULL;
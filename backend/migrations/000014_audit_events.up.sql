@@ -0,0 +1,42 @@
+-- ==== AUDIT LOG ====
+CREATE TABLE IF NOT EXISTS audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    actor_type VARCHAR(20) NOT NULL,
+    actor_id VARCHAR(255),
+    tenant_id VARCHAR(100),
+    action VARCHAR(100) NOT NULL,
+    target_type VARCHAR(50),
+    target_id VARCHAR(255),
+    outcome VARCHAR(20) NOT NULL,
+    status_code INTEGER,
+    before JSONB,
+    after JSONB,
+    changes JSONB,
+    method VARCHAR(255),
+    path TEXT,
+    ip_address VARCHAR(64),
+    user_agent TEXT,
+    details JSONB
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_occurred_at ON audit_events (occurred_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_target ON audit_events (target_type, target_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor ON audit_events (actor_type, actor_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_tenant ON audit_events (tenant_id, id DESC);
+
+-- The audit log is append-only: rows can be inserted but never changed or
+-- removed, not even by the application's own database user.
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_events_no_update ON audit_events;
+CREATE TRIGGER audit_events_no_update BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE PROCEDURE audit_events_append_only();
+
+DROP TRIGGER IF EXISTS audit_events_no_truncate ON audit_events;
+CREATE TRIGGER audit_events_no_truncate BEFORE TRUNCATE ON audit_events
+    FOR EACH STATEMENT EXECUTE PROCEDURE audit_events_append_only();
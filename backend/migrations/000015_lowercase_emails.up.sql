@@ -0,0 +1,19 @@
+-- ==== CASE-INSENSITIVE EMAILS ====
+-- Register and login now lower-case emails. Lower-case the stored ones too,
+-- except where two accounts differ only by case; an operator has to merge
+-- or rename those before the unique index below can be created.
+UPDATE users SET email = LOWER(email)
+WHERE email <> LOWER(email)
+  AND NOT EXISTS (
+    SELECT 1 FROM users other
+    WHERE other.id <> users.id AND LOWER(other.email) = LOWER(users.email)
+  );
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM users GROUP BY LOWER(email) HAVING COUNT(*) > 1
+    ) THEN
+        CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_lower ON users (LOWER(email));
+    END IF;
+END $$;
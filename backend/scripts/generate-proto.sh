@@ -3,13 +3,19 @@
 # Create output directory
 mkdir -p internal/grpc/generated
 
-# Generate Go code from proto files
-protoc --go_out=. --go_opt=paths=source_relative \
+# Generate Go code, the REST gateway and the merged OpenAPI document from proto
+# files. google/api annotations are vendored under third_party/googleapis.
+protoc -I . -I third_party/googleapis \
+    --go_out=. --go_opt=paths=source_relative \
     --go-grpc_out=. --go-grpc_opt=paths=source_relative \
-    proto/task/task.proto
+    --grpc-gateway_out=. --grpc-gateway_opt=paths=source_relative \
+    --openapiv2_out=internal/grpc/generated/openapi \
+    --openapiv2_opt=allow_merge=true,merge_file_name=task_scheduler \
+    proto/task/task.proto proto/worker/worker.proto
 
-protoc --go_out=. --go_opt=paths=source_relative \
-    --go-grpc_out=. --go-grpc_opt=paths=source_relative \
-    proto/worker/worker.proto
+for service in task worker; do
+    mkdir -p internal/grpc/generated/$service
+    mv proto/$service/*.go internal/grpc/generated/$service/
+done
 
 echo "Protocol buffer code generated successfully!"
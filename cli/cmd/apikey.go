@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rudraprasaaad/task-scheduler/cli/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	apiKeyAccount       string
+	apiKeyName          string
+	apiKeyScopes        []string
+	apiKeyExpiresIn     string
+	apiKeyCreateAccount bool
+)
+
+var apiKeyCmd = &cobra.Command{
+	Use:   "apikey",
+	Short: "Manage API keys for service accounts",
+	Long: `The apikey command manages the API keys that services use to call the Task
+Scheduler API. Services send a key as "Authorization: Bearer <key>" or in the
+X-API-Key header.`,
+}
+
+var apiKeyCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an API key for a service account",
+	Long: `Create an API key for a service account. Each scope is a permission such as
+tasks:read or tasks:write, and you can only grant scopes you hold yourself.`,
+	Example: `task-cli apikey create --account ci-bot --name deploys --scope tasks:read --scope tasks:write --create-account`,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiURL := viper.GetString("api_url")
+		cli, err := client.NewClient(apiURL)
+		if err != nil {
+			log.Fatalf("Failed to create API client: %v", err)
+		}
+
+		if apiKeyCreateAccount {
+			account, existed, err := cli.CreateServiceAccount(apiKeyAccount, "")
+			if err != nil {
+				log.Fatalf("Failed to create service account: %v", err)
+			}
+			if !existed {
+				fmt.Printf("Created service account %s in tenant %s.\n", account.Name, account.TenantID)
+			}
+		}
+
+		created, err := cli.CreateAPIKey(client.CreateAPIKeyPayload{
+			ServiceAccount: apiKeyAccount,
+			Name:           apiKeyName,
+			Scopes:         apiKeyScopes,
+			ExpiresIn:      apiKeyExpiresIn,
+		})
+		if err != nil {
+			log.Fatalf("Failed to create API key: %v", err)
+		}
+
+		fmt.Println("✅ API key created. It is shown only once:")
+		fmt.Println()
+		fmt.Printf("  %s\n\n", created.Key)
+		fmt.Printf("Key ID:          %s\n", created.APIKey.ID)
+		fmt.Printf("Service account: %s\n", created.APIKey.ServiceAccountName)
+		fmt.Printf("Scopes:          %s\n", strings.Join(created.APIKey.Scopes, ", "))
+		if created.APIKey.ExpiresAt != nil {
+			fmt.Printf("Expires at:      %s\n", created.APIKey.ExpiresAt.Format("2006-01-02 15:04:05"))
+		}
+	},
+}
+
+var apiKeyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List API keys",
+	Run: func(cmd *cobra.Command, args []string) {
+		apiURL := viper.GetString("api_url")
+		cli, err := client.NewClient(apiURL)
+		if err != nil {
+			log.Fatalf("Failed to create API client: %v", err)
+		}
+
+		keys, err := cli.ListAPIKeys(apiKeyAccount)
+		if err != nil {
+			log.Fatalf("Failed to list API keys: %v", err)
+		}
+
+		if len(keys) == 0 {
+			fmt.Println("No API keys found.")
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "ID\tACCOUNT\tNAME\tSCOPES\tSTATUS\tLAST USED")
+		for _, key := range keys {
+			lastUsed := "never"
+			if key.LastUsedAt != nil {
+				lastUsed = key.LastUsedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", key.ID, key.ServiceAccountName, key.Name, strings.Join(key.Scopes, ","), apiKeyStatus(key), lastUsed)
+		}
+		w.Flush()
+	},
+}
+
+func apiKeyStatus(key client.APIKey) string {
+	switch {
+	case key.RevokedAt != nil:
+		return "revoked"
+	case key.ExpiresAt != nil && key.ExpiresAt.Before(time.Now()):
+		return "expired"
+	default:
+		return "active"
+	}
+}
+
+var apiKeyRevokeCmd = &cobra.Command{
+	Use:   "revoke [KEY_ID]",
+	Short: "Revoke an API key",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiURL := viper.GetString("api_url")
+		cli, err := client.NewClient(apiURL)
+		if err != nil {
+			log.Fatalf("Failed to create API client: %v", err)
+		}
+
+		if err := cli.RevokeAPIKey(args[0]); err != nil {
+			log.Fatalf("Failed to revoke API key: %v", err)
+		}
+
+		fmt.Printf("✅ Revoked API key %s.\n", args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(apiKeyCmd)
+	apiKeyCmd.AddCommand(apiKeyCreateCmd)
+	apiKeyCmd.AddCommand(apiKeyListCmd)
+	apiKeyCmd.AddCommand(apiKeyRevokeCmd)
+
+	apiKeyCreateCmd.Flags().StringVar(&apiKeyAccount, "account", "", "Service account name or ID the key belongs to")
+	apiKeyCreateCmd.Flags().StringVarP(&apiKeyName, "name", "n", "", "A name that says what the key is for")
+	apiKeyCreateCmd.Flags().StringSliceVarP(&apiKeyScopes, "scope", "s", nil, "Permission to grant the key; repeat for more than one")
+	apiKeyCreateCmd.Flags().StringVar(&apiKeyExpiresIn, "expires-in", "", "How long the key stays valid, e.g. 720h (default never)")
+	apiKeyCreateCmd.Flags().BoolVar(&apiKeyCreateAccount, "create-account", false, "Create the service account first if it does not exist")
+	apiKeyCreateCmd.MarkFlagRequired("account")
+	apiKeyCreateCmd.MarkFlagRequired("name")
+	apiKeyCreateCmd.MarkFlagRequired("scope")
+
+	apiKeyListCmd.Flags().StringVar(&apiKeyAccount, "account", "", "Only list keys of this service account")
+}
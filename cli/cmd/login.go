@@ -39,3 +39,7 @@ var loginCmd = &cobra.Command{
 		fmt.Println("Login successful. Session cookies saved.")
 	},
 }
+
+func init() {
+	rootCmd.AddCommand(loginCmd)
+}
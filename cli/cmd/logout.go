@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/rudraprasaaad/task-scheduler/cli/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var logoutAll bool
+
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "End your session with the Task Scheduler service",
+	Run: func(cmd *cobra.Command, args []string) {
+		apiURL := viper.GetString("api_url")
+		cli, err := client.NewClient(apiURL)
+		if err != nil {
+			log.Fatalf("Failed to create API client: %v", err)
+		}
+
+		if err := cli.Logout(logoutAll); err != nil {
+			log.Fatalf("Logout failed: %v", err)
+		}
+
+		if logoutAll {
+			fmt.Println("Logged out of all sessions.")
+			return
+		}
+		fmt.Println("Logged out.")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(logoutCmd)
+
+	logoutCmd.Flags().BoolVar(&logoutAll, "all", false, "Log out every session of your user, on every device")
+}
@@ -33,18 +33,20 @@ var statusCmd = &cobra.Command{
 		summaryTable.Header([]string{"Metric", "Value"})
 		summaryTable.Append([]string{"Pending Tasks in Queue", fmt.Sprintf("%d", status.QueueSize)})
 		summaryTable.Append([]string{"Total Workers", fmt.Sprintf("%d", len(status.Workers))})
+		summaryTable.Append([]string{"Local Workers (current / target)", fmt.Sprintf("%d / %d", status.CurrentWorkers, status.TargetWorkers)})
 		summaryTable.Render()
 		fmt.Println()
 
 		if len(status.Workers) > 0 {
 			fmt.Println("--- Worker Details ---")
 			workerTable := tablewriter.NewWriter(os.Stdout)
-			workerTable.Header([]string{"Worker ID", "Status", "Tasks Run", "Last Seen"})
+			workerTable.Header([]string{"Worker ID", "Status", "Slots", "Tasks Run", "Last Seen"})
 
 			for _, worker := range status.Workers {
 				workerTable.Append([]string{
 					worker.ID,
 					worker.Status,
+					fmt.Sprintf("%d/%d", worker.CurrentTasks, worker.MaxConcurrentTasks),
 					fmt.Sprintf("%d", worker.TasksRun),
 					worker.LastSeen.Format(time.RFC1123),
 				})
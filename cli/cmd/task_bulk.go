@@ -0,0 +1,308 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rudraprasaaad/task-scheduler/cli/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	bulkIDs        []string
+	bulkIDsFile    string
+	bulkFilter     string
+	bulkPriority   int
+	bulkAt         string
+	bulkWait       bool
+	bulkFailedOnly bool
+	bulkItemsLimit int
+	bulkJobsLimit  int
+)
+
+var taskBulkCmd = &cobra.Command{
+	Use:   "bulk",
+	Short: "Act on many tasks at once in a tracked background job",
+	Long: `Bulk commands submit a background job and print its ID. Follow it with
+'task-cli task bulk status' and read the per-task outcome with
+'task-cli task bulk report'.
+
+Tasks are selected with --ids, --ids-file or --filter. The filter takes the
+same query string as the task list API, e.g. "status=failed&type=email".`,
+}
+
+var taskBulkCreateCmd = &cobra.Command{
+	Use:   "create [FILE]",
+	Short: "Create tasks from a JSON array or NDJSON file (- for stdin)",
+	Args:  cobra.ExactArgs(1),
+	Example: `task-cli task bulk create tasks.ndjson --wait
+generate-tasks | task-cli task bulk create -`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli := newBulkClient()
+
+		var body io.Reader = os.Stdin
+		if args[0] != "-" {
+			file, err := os.Open(args[0])
+			if err != nil {
+				log.Fatalf("Failed to open %s: %v", args[0], err)
+			}
+			defer file.Close()
+			body = file
+		}
+
+		job, err := cli.CreateTasksBulk(body)
+		if err != nil {
+			log.Fatalf("Failed to submit bulk create: %v", err)
+		}
+
+		reportSubmitted(cli, job)
+	},
+}
+
+func newBulkOperationCmd(operation, short, example string) *cobra.Command {
+	return &cobra.Command{
+		Use:     operation,
+		Short:   short,
+		Args:    cobra.NoArgs,
+		Example: example,
+		Run: func(cmd *cobra.Command, args []string) {
+			cli := newBulkClient()
+
+			request := client.BulkTaskRequest{
+				TaskIDs: bulkIDs,
+				Filter:  bulkFilter,
+			}
+
+			if bulkIDsFile != "" {
+				ids, err := readIDsFile(bulkIDsFile)
+				if err != nil {
+					log.Fatalf("Failed to read %s: %v", bulkIDsFile, err)
+				}
+				request.TaskIDs = append(request.TaskIDs, ids...)
+			}
+
+			if cmd.Flags().Changed("priority") {
+				request.Priority = &bulkPriority
+			}
+
+			if bulkAt != "" {
+				at, err := parseScheduleFlag(bulkAt)
+				if err != nil {
+					log.Fatalf("Invalid --at: %v", err)
+				}
+				request.ScheduledAt = &at
+			}
+
+			job, err := cli.SubmitBulkOperation(operation, request)
+			if err != nil {
+				log.Fatalf("Failed to submit bulk %s: %v", operation, err)
+			}
+
+			reportSubmitted(cli, job)
+		},
+	}
+}
+
+var (
+	taskBulkCancelCmd = newBulkOperationCmd("cancel", "Cancel pending tasks",
+		`task-cli task bulk cancel --filter "status=pending&type=email"`)
+	taskBulkRetryCmd = newBulkOperationCmd("retry", "Retry failed or cancelled tasks",
+		`task-cli task bulk retry --filter "status=failed&created_after=2026-01-01T00:00:00Z" --wait`)
+	taskBulkDeleteCmd = newBulkOperationCmd("delete", "Delete tasks that are not running",
+		`task-cli task bulk delete --ids-file old-tasks.txt`)
+	taskBulkReprioritizeCmd = newBulkOperationCmd("reprioritize", "Change the priority of pending tasks",
+		`task-cli task bulk reprioritize --filter "type=report" --priority 8`)
+	taskBulkRescheduleCmd = newBulkOperationCmd("reschedule", "Move pending tasks to a new time",
+		`task-cli task bulk reschedule --ids 3f2a...,9b1c... --at 2h`)
+)
+
+var taskBulkStatusCmd = &cobra.Command{
+	Use:   "status [JOB_ID]",
+	Short: "Show a bulk job's progress",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cli := newBulkClient()
+
+		job, err := cli.GetBulkJob(args[0])
+		if err != nil {
+			log.Fatalf("Failed to get bulk job: %v", err)
+		}
+
+		if bulkWait {
+			job = waitForBulkJob(cli, job)
+		}
+
+		printBulkJob(job)
+	},
+}
+
+var taskBulkReportCmd = &cobra.Command{
+	Use:   "report [JOB_ID]",
+	Short: "List the outcome for each task in a bulk job",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cli := newBulkClient()
+
+		items, err := cli.ListBulkJobItems(args[0], bulkFailedOnly, bulkItemsLimit, 0)
+		if err != nil {
+			log.Fatalf("Failed to get bulk job report: %v", err)
+		}
+
+		if len(items) == 0 {
+			fmt.Println("No items found.")
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "INDEX\tTASK ID\tRESULT\tERROR")
+		for _, item := range items {
+			result := "ok"
+			if !item.OK {
+				result = "failed"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", item.Index, item.TaskID, result, item.Error)
+		}
+		w.Flush()
+	},
+}
+
+var taskBulkJobsCmd = &cobra.Command{
+	Use:   "jobs",
+	Short: "List recent bulk jobs",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli := newBulkClient()
+
+		jobs, err := cli.ListBulkJobs(bulkJobsLimit)
+		if err != nil {
+			log.Fatalf("Failed to list bulk jobs: %v", err)
+		}
+
+		if len(jobs) == 0 {
+			fmt.Println("No bulk jobs found.")
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "ID\tOPERATION\tSTATUS\tPROGRESS\tFAILED\tCREATED AT")
+		for _, job := range jobs {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d/%d\t%d\t%s\n", job.ID, job.Operation, job.Status,
+				job.Processed, job.Total, job.Failed, job.CreatedAt.Format("2006-01-02 15:04:05"))
+		}
+		w.Flush()
+	},
+}
+
+func newBulkClient() *client.Client {
+	cli, err := client.NewClient(viper.GetString("api_url"))
+	if err != nil {
+		log.Fatalf("Failed to create API client: %v", err)
+	}
+
+	return cli
+}
+
+func reportSubmitted(cli *client.Client, job *client.BulkJob) {
+	fmt.Printf("✅ Bulk %s job %s submitted.\n", job.Operation, job.ID)
+
+	if bulkWait {
+		printBulkJob(waitForBulkJob(cli, job))
+	}
+}
+
+func waitForBulkJob(cli *client.Client, job *client.BulkJob) *client.BulkJob {
+	for !job.Done() {
+		fmt.Fprintf(os.Stderr, "\r%s: %d/%d processed, %d failed", job.Status, job.Processed, job.Total, job.Failed)
+		time.Sleep(time.Second)
+
+		var err error
+		job, err = cli.GetBulkJob(job.ID)
+		if err != nil {
+			log.Fatalf("Failed to get bulk job: %v", err)
+		}
+	}
+	fmt.Fprintln(os.Stderr)
+
+	return job
+}
+
+func printBulkJob(job *client.BulkJob) {
+	fmt.Printf("Job:       %s\n", job.ID)
+	fmt.Printf("Operation: %s\n", job.Operation)
+	fmt.Printf("Status:    %s\n", job.Status)
+	fmt.Printf("Progress:  %d/%d processed, %d succeeded, %d failed\n", job.Processed, job.Total, job.Succeeded, job.Failed)
+	if job.Error != "" {
+		fmt.Printf("Error:     %s\n", job.Error)
+	}
+	if job.Failed > 0 {
+		fmt.Printf("\nSee the failures with: task-cli task bulk report %s --failed\n", job.ID)
+	}
+}
+
+// readIDsFile reads one task ID per line, skipping blanks and # comments.
+func readIDsFile(path string) ([]string, error) {
+	var reader io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	var ids []string
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			ids = append(ids, line)
+		}
+	}
+
+	return ids, scanner.Err()
+}
+
+// parseScheduleFlag accepts an RFC 3339 time or a duration from now.
+func parseScheduleFlag(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected an RFC 3339 time or a duration like 2h")
+	}
+
+	return time.Now().Add(d), nil
+}
+
+func init() {
+	taskCmd.AddCommand(taskBulkCmd)
+	taskBulkCmd.AddCommand(taskBulkCreateCmd, taskBulkStatusCmd, taskBulkReportCmd, taskBulkJobsCmd)
+
+	taskBulkCmd.PersistentFlags().BoolVarP(&bulkWait, "wait", "w", false, "Wait for the job to finish, showing progress")
+
+	for _, cmd := range []*cobra.Command{taskBulkCancelCmd, taskBulkRetryCmd, taskBulkDeleteCmd, taskBulkReprioritizeCmd, taskBulkRescheduleCmd} {
+		taskBulkCmd.AddCommand(cmd)
+		cmd.Flags().StringSliceVar(&bulkIDs, "ids", nil, "Task IDs to act on (repeat or comma-separate)")
+		cmd.Flags().StringVar(&bulkIDsFile, "ids-file", "", "File with one task ID per line (- for stdin)")
+		cmd.Flags().StringVarP(&bulkFilter, "filter", "f", "", `Select tasks by list query, e.g. "status=failed&type=email"`)
+	}
+
+	taskBulkReprioritizeCmd.Flags().IntVarP(&bulkPriority, "priority", "p", 0, "New priority (1-10)")
+	taskBulkReprioritizeCmd.MarkFlagRequired("priority")
+	taskBulkRescheduleCmd.Flags().StringVar(&bulkAt, "at", "", "New time, RFC 3339 or a duration from now like 2h")
+	taskBulkRescheduleCmd.MarkFlagRequired("at")
+
+	taskBulkReportCmd.Flags().BoolVar(&bulkFailedOnly, "failed", false, "Only show items that failed")
+	taskBulkReportCmd.Flags().IntVarP(&bulkItemsLimit, "limit", "l", 1000, "Maximum number of rows to show")
+	taskBulkJobsCmd.Flags().IntVarP(&bulkJobsLimit, "limit", "l", 20, "Number of jobs to list")
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/rudraprasaaad/task-scheduler/cli/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const maxTaskPriority = 10
+
+var bumpPriority int
+
+var taskBumpCmd = &cobra.Command{
+	Use:   "bump [TASK_ID]",
+	Short: "Raise the priority of a pending task",
+	Long: `Raise a pending task's priority by one, or set it with --priority.
+Priorities run from 0 to 10; higher runs first.`,
+	Args: cobra.ExactArgs(1),
+	Example: `task-cli task bump 3f2a...
+task-cli task bump 3f2a... --priority 10`,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiURL := viper.GetString("api_url")
+		cli, err := client.NewClient(apiURL)
+		if err != nil {
+			log.Fatalf("Failed to create API client: %v", err)
+		}
+
+		priority := bumpPriority
+		if !cmd.Flags().Changed("priority") {
+			task, err := cli.GetTask(args[0])
+			if err != nil {
+				log.Fatalf("Failed to get task: %v", err)
+			}
+			if task.Priority >= maxTaskPriority {
+				fmt.Printf("Task %s already has the highest priority (%d).\n", task.ID, task.Priority)
+				return
+			}
+			priority = task.Priority + 1
+		}
+
+		task, err := cli.UpdateTask(args[0], client.TaskUpdate{Priority: &priority})
+		if err != nil {
+			log.Fatalf("Failed to bump task: %v", err)
+		}
+
+		fmt.Printf("✅ Task %s now has priority %d.\n", task.ID, task.Priority)
+	},
+}
+
+func init() {
+	taskCmd.AddCommand(taskBumpCmd)
+
+	taskBumpCmd.Flags().IntVarP(&bumpPriority, "priority", "p", 0, "Set this priority instead of raising by one")
+}
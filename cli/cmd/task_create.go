@@ -15,12 +15,13 @@ var (
 	taskType     string
 	taskPayload  string
 	taskPriority int
+	taskSelector map[string]string
 )
 
 var taskCreateCmd = &cobra.Command{
 	Use:     "create",
 	Short:   "Create a new task",
-	Example: `task-cli task create --name "Process video" --type "video_processing" --payload '{"url": "https://hey.com/me.mp4"}'`,
+	Example: `task-cli task create --name "Process video" --type "video_processing" --payload '{"url": "https://hey.com/me.mp4"}' --selector gpu=true`,
 	Run: func(cmd *cobra.Command, args []string) {
 		apiURL := viper.GetString("api_url")
 		cli, err := client.NewClient(apiURL)
@@ -37,6 +38,7 @@ var taskCreateCmd = &cobra.Command{
 			Type:     taskType,
 			Payload:  []byte(taskPayload),
 			Priority: taskPriority,
+			Selector: taskSelector,
 		}
 
 		createdTask, err := cli.CreateTask(payload)
@@ -57,6 +59,7 @@ func init() {
 	taskCreateCmd.Flags().StringVarP(&taskType, "type", "t", "", "Type of the task (required)")
 	taskCreateCmd.Flags().StringVarP(&taskPayload, "payload", "p", "{}", "JSON payload for the task")
 	taskCreateCmd.Flags().IntVarP(&taskPriority, "priority", "", 5, "Task priority (0 - 10)")
+	taskCreateCmd.Flags().StringToStringVarP(&taskSelector, "selector", "s", nil, "Worker labels required to run the task (key=value)")
 
 	taskCreateCmd.MarkFlagRequired("name")
 	taskCreateCmd.MarkFlagRequired("type")
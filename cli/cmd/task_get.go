@@ -39,10 +39,23 @@ var taskGetCmd = &cobra.Command{
 		fmt.Printf("Created At:\t%s\n", task.CreatedAt.Format(time.RFC1123))
 		fmt.Printf("Scheduled At:\t%s\n", task.ScheduledAt.Format(time.RFC1123))
 
+		if task.TenantID != "" {
+			fmt.Printf("Tenant:\t\t%s\n", task.TenantID)
+		}
+
+		if task.CreatedBy != "" {
+			fmt.Printf("Created By:\t%s\n", task.CreatedBy)
+		}
+
 		if task.WorkerID != "" {
 			fmt.Printf("Worker ID:\t%s\n", task.WorkerID)
 		}
 
+		if len(task.Selector) > 0 {
+			selector, _ := json.Marshal(task.Selector)
+			fmt.Printf("Selector:\t%s\n", string(selector))
+		}
+
 		if task.Error != "" {
 			fmt.Printf("Last Error:\t%s\n", task.Error)
 		}
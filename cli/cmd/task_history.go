@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rudraprasaaad/task-scheduler/cli/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var historyAttempts bool
+
+var taskHistoryCmd = &cobra.Command{
+	Use:   "history [TASK_ID]",
+	Short: "Show a task's timeline from creation to its final state",
+	Long: `Show when a task was created and scheduled, each attempt with its worker,
+duration and outcome, the retries between them and how the task ended.
+
+--attempts lists just the recorded attempts instead.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiURL := viper.GetString("api_url")
+		cli, err := client.NewClient(apiURL)
+		if err != nil {
+			log.Fatalf("Failed to create API client: %v", err)
+		}
+
+		history, err := cli.GetTaskHistory(args[0])
+		if err != nil {
+			log.Fatalf("Failed to get task history: %v", err)
+		}
+
+		task := history.Task
+		fmt.Printf("Task %s (%s, %s) is %s after %d attempt(s)\n\n", task.ID, task.Name, task.Type, task.Status, len(history.Executions))
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		defer w.Flush()
+
+		if historyAttempts {
+			if len(history.Executions) == 0 {
+				fmt.Println("No attempts recorded.")
+				return
+			}
+
+			fmt.Fprintln(w, "ATTEMPT\tWORKER\tSTARTED AT\tDURATION\tSTATUS\tERROR")
+			for _, execution := range history.Executions {
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", execution.Attempt, orDash(execution.WorkerID),
+					execution.StartedAt.Local().Format("2006-01-02 15:04:05"),
+					formatDurationMs(execution.ExecutionTimeMs), execution.Status, execution.Error)
+			}
+			return
+		}
+
+		fmt.Fprintln(w, "TIME\tEVENT\tATTEMPT\tWORKER\tDETAIL")
+		for _, event := range history.Timeline {
+			attempt := "-"
+			if event.Attempt > 0 {
+				attempt = fmt.Sprint(event.Attempt)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", event.At.Local().Format("2006-01-02 15:04:05"),
+				event.Type, attempt, orDash(event.WorkerID), timelineDetail(event))
+		}
+	},
+}
+
+func timelineDetail(event client.TimelineEvent) string {
+	switch event.Type {
+	case "attempt_finished":
+		detail := fmt.Sprintf("%s in %s", event.Status, formatDurationMs(event.DurationMs))
+		if event.Error != "" {
+			detail += ": " + event.Error
+		}
+		return detail
+	case "scheduled":
+		if until := time.Until(event.At); until > 0 {
+			return "runs in " + until.Round(time.Second).String()
+		}
+		return "due"
+	default:
+		return event.Error
+	}
+}
+
+func formatDurationMs(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).String()
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func init() {
+	taskCmd.AddCommand(taskHistoryCmd)
+
+	taskHistoryCmd.Flags().BoolVar(&historyAttempts, "attempts", false, "List the recorded attempts instead of the timeline")
+}
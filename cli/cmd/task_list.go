@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/rudraprasaaad/task-scheduler/cli/client"
 	"github.com/spf13/cobra"
@@ -14,11 +16,38 @@ import (
 var (
 	limit  int
 	offset int
+
+	listStatuses    []string
+	listTypes       []string
+	listWorkerID    string
+	listMinPriority int
+	listMaxPriority int
+	listNamePrefix  string
+	listPayload     string
+	listTimeRanges  = map[string]*string{}
+	listSort        string
+	listOrder       string
+	listPageToken   string
+	listAll         bool
 )
 
+var listTimeFlags = []string{
+	"created-after", "created-before",
+	"scheduled-after", "scheduled-before",
+	"completed-after", "completed-before",
+}
+
 var taskListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List tasks in the system",
+	Long: `List tasks, newest first by default.
+
+Time filters take an RFC 3339 time or a duration counted back from now,
+so --created-after 24h lists tasks created in the last day.`,
+	Example: `task-cli task list --status failed,cancelled --type email
+task-cli task list --min-priority 5 --sort priority --order desc
+task-cli task list --payload '{"customer":"acme"}' --completed-after 1h
+task-cli task list --all --name-prefix nightly-`,
 	Run: func(cmd *cobra.Command, args []string) {
 		apiURL := viper.GetString("api_url")
 		cli, err := client.NewClient(apiURL)
@@ -26,9 +55,53 @@ var taskListCmd = &cobra.Command{
 			log.Fatalf("Failed to create API client: %v", err)
 		}
 
-		tasks, err := cli.ListTasks(limit, offset)
-		if err != nil {
-			log.Fatalf("Failed to list tasks: %v", err)
+		opts := client.ListTasksOptions{
+			Limit:      limit,
+			Offset:     offset,
+			Statuses:   listStatuses,
+			Types:      listTypes,
+			WorkerID:   listWorkerID,
+			NamePrefix: listNamePrefix,
+			Payload:    listPayload,
+			Sort:       listSort,
+			Order:      listOrder,
+			PageToken:  listPageToken,
+		}
+
+		if cmd.Flags().Changed("min-priority") {
+			opts.MinPriority = &listMinPriority
+		}
+		if cmd.Flags().Changed("max-priority") {
+			opts.MaxPriority = &listMaxPriority
+		}
+
+		times := make(map[string]string, len(listTimeFlags))
+		for _, name := range listTimeFlags {
+			value, err := parseTimeFlag(*listTimeRanges[name])
+			if err != nil {
+				log.Fatalf("Invalid --%s: %v", name, err)
+			}
+			times[name] = value
+		}
+		opts.CreatedAfter = times["created-after"]
+		opts.CreatedBefore = times["created-before"]
+		opts.ScheduledAfter = times["scheduled-after"]
+		opts.ScheduledBefore = times["scheduled-before"]
+		opts.CompletedAfter = times["completed-after"]
+		opts.CompletedBefore = times["completed-before"]
+
+		var tasks []client.Task
+		for {
+			page, nextPageToken, err := cli.ListTasks(opts)
+			if err != nil {
+				log.Fatalf("Failed to list tasks: %v", err)
+			}
+			tasks = append(tasks, page...)
+			opts.PageToken = nextPageToken
+
+			if !listAll || nextPageToken == "" {
+				break
+			}
 		}
 
 		if len(tasks) == 0 {
@@ -37,17 +110,61 @@ var taskListCmd = &cobra.Command{
 		}
 
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-		fmt.Println(w, "ID\tNAME\tTYPE\tSTATUS\tCREATED AT")
+		fmt.Fprintln(w, "ID\tNAME\tTYPE\tSTATUS\tPRIORITY\tCREATED AT")
 		for _, task := range tasks {
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", task.ID, task.Name, task.Type, task.Status, task.CreatedAt.Format("2006-01-02 15:04:05"))
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n", task.ID, task.Name, task.Type, task.Status, task.Priority, task.CreatedAt.Format("2006-01-02 15:04:05"))
 		}
 		w.Flush()
+
+		if opts.PageToken != "" {
+			fmt.Fprintf(os.Stderr, "\nMore tasks available, continue with --page-token %s\n", opts.PageToken)
+		}
 	},
 }
 
+// parseTimeFlag accepts an RFC 3339 time or a duration before now and returns
+// it in RFC 3339 for the API.
+func parseTimeFlag(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.Format(time.RFC3339), nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return "", fmt.Errorf("expected an RFC 3339 time or a duration like 24h")
+	}
+
+	return time.Now().Add(-d).UTC().Format(time.RFC3339), nil
+}
+
 func init() {
 	taskCmd.AddCommand(taskListCmd)
 
 	taskListCmd.Flags().IntVarP(&limit, "limit", "l", 20, "Number of tasks to return")
 	taskListCmd.Flags().IntVarP(&offset, "offset", "o", 0, "offset for pagination")
+
+	taskListCmd.Flags().StringSliceVarP(&listStatuses, "status", "s", nil, "Only tasks with these statuses (repeat or comma-separate)")
+	taskListCmd.Flags().StringSliceVarP(&listTypes, "type", "t", nil, "Only tasks of these types (repeat or comma-separate)")
+	taskListCmd.Flags().StringVar(&listWorkerID, "worker", "", "Only tasks run by this worker")
+	taskListCmd.Flags().IntVar(&listMinPriority, "min-priority", 0, "Lowest priority to include")
+	taskListCmd.Flags().IntVar(&listMaxPriority, "max-priority", 0, "Highest priority to include")
+	taskListCmd.Flags().StringVar(&listNamePrefix, "name-prefix", "", "Only tasks whose name starts with this prefix")
+	taskListCmd.Flags().StringVar(&listPayload, "payload", "", "Only tasks whose payload contains this JSON object")
+	for _, name := range listTimeFlags {
+		field, bound, _ := strings.Cut(name, "-")
+		if bound == "after" {
+			bound = "at or after"
+		}
+		usage := fmt.Sprintf("Only tasks %s %s this time (RFC 3339 or duration before now)", field, bound)
+		listTimeRanges[name] = taskListCmd.Flags().String(name, "", usage)
+	}
+
+	taskListCmd.Flags().StringVar(&listSort, "sort", "", "Sort by created_at, updated_at, scheduled_at, completed_at, priority or name")
+	taskListCmd.Flags().StringVar(&listOrder, "order", "", "Sort order: asc or desc (default desc)")
+	taskListCmd.Flags().StringVar(&listPageToken, "page-token", "", "Continue from a previous listing")
+	taskListCmd.Flags().BoolVar(&listAll, "all", false, "Follow page tokens until every matching task is listed")
 }
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/rudraprasaaad/task-scheduler/cli/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	logsAttempt int
+	logsFollow  bool
+)
+
+var taskLogsCmd = &cobra.Command{
+	Use:   "logs [TASK_ID]",
+	Short: "Show what a task's executor logged",
+	Long: `Show the executor output of a task's latest attempt, or of --attempt N.
+With --follow the output streams until the attempt ends.`,
+	Args: cobra.ExactArgs(1),
+	Example: `task-cli task logs 3f2a...
+task-cli task logs 3f2a... --attempt 2
+task-cli task logs 3f2a... -f`,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiURL := viper.GetString("api_url")
+		cli, err := client.NewClient(apiURL)
+		if err != nil {
+			log.Fatalf("Failed to create API client: %v", err)
+		}
+
+		if logsFollow {
+			if err := cli.FollowTaskLogs(args[0], logsAttempt, os.Stdout); err != nil {
+				log.Fatalf("Failed to follow task logs: %v", err)
+			}
+			return
+		}
+
+		taskLog, err := cli.GetTaskLogs(args[0], logsAttempt)
+		if err != nil {
+			log.Fatalf("Failed to get task logs: %v", err)
+		}
+
+		fmt.Fprintf(os.Stderr, "--- Attempt %d ---\n", taskLog.Attempt)
+		for _, line := range taskLog.Lines {
+			fmt.Printf("%s %s\n", line.Time.Local().Format(time.RFC3339), line.Message)
+		}
+		if taskLog.Dropped > 0 {
+			fmt.Fprintf(os.Stderr, "... %d more lines were dropped after the limit\n", taskLog.Dropped)
+		}
+		if !taskLog.Done {
+			fmt.Fprintln(os.Stderr, "Attempt still running, use -f to follow it.")
+		}
+	},
+}
+
+func init() {
+	taskCmd.AddCommand(taskLogsCmd)
+
+	taskLogsCmd.Flags().IntVarP(&logsAttempt, "attempt", "a", 0, "Attempt to show (default latest)")
+	taskLogsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Stream the log until the attempt ends")
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/rudraprasaaad/task-scheduler/cli/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	rescheduleAt         string
+	rescheduleMaxRetries int
+)
+
+var taskRescheduleCmd = &cobra.Command{
+	Use:   "reschedule [TASK_ID]",
+	Short: "Move a pending task to a new time",
+	Args:  cobra.ExactArgs(1),
+	Example: `task-cli task reschedule 3f2a... --at 2026-11-01T09:00:00Z
+task-cli task reschedule 3f2a... --at 30m --max-retries 5`,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiURL := viper.GetString("api_url")
+		cli, err := client.NewClient(apiURL)
+		if err != nil {
+			log.Fatalf("Failed to create API client: %v", err)
+		}
+
+		var update client.TaskUpdate
+		if rescheduleAt != "" {
+			at, err := parseScheduleFlag(rescheduleAt)
+			if err != nil {
+				log.Fatalf("Invalid --at: %v", err)
+			}
+			update.ScheduledAt = &at
+		}
+		if cmd.Flags().Changed("max-retries") {
+			update.MaxRetries = &rescheduleMaxRetries
+		}
+
+		if update.ScheduledAt == nil && update.MaxRetries == nil {
+			log.Fatalf("Nothing to change: pass --at or --max-retries")
+		}
+
+		task, err := cli.UpdateTask(args[0], update)
+		if err != nil {
+			log.Fatalf("Failed to reschedule task: %v", err)
+		}
+
+		fmt.Printf("✅ Task %s scheduled for %s (max retries %d).\n", task.ID, task.ScheduledAt.Local().Format("2006-01-02 15:04:05"), task.MaxRetries)
+	},
+}
+
+func init() {
+	taskCmd.AddCommand(taskRescheduleCmd)
+
+	taskRescheduleCmd.Flags().StringVar(&rescheduleAt, "at", "", "New time, RFC 3339 or a duration from now like 2h")
+	taskRescheduleCmd.Flags().IntVar(&rescheduleMaxRetries, "max-retries", 0, "New retry budget")
+}
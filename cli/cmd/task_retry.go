@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/rudraprasaaad/task-scheduler/cli/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	retryResetRetries bool
+	retryPayload      string
+)
+
+var taskRetryCmd = &cobra.Command{
+	Use:   "retry [TASK_ID]",
+	Short: "Run a failed or cancelled task again",
+	Long: `Put a failed or cancelled task back on the queue to run now.
+
+By default the task keeps its retry count and gets one more attempt.
+--reset-retries gives it its full retry budget again.`,
+	Args: cobra.ExactArgs(1),
+	Example: `task-cli task retry 3f2a...
+task-cli task retry 3f2a... --reset-retries --payload '{"to":"ops@example.com"}'`,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiURL := viper.GetString("api_url")
+		cli, err := client.NewClient(apiURL)
+		if err != nil {
+			log.Fatalf("Failed to create API client: %v", err)
+		}
+
+		var payload json.RawMessage
+		if retryPayload != "" {
+			if !json.Valid([]byte(retryPayload)) {
+				log.Fatalf("Invalid --payload: not valid JSON")
+			}
+			payload = json.RawMessage(retryPayload)
+		}
+
+		task, err := cli.RetryTask(args[0], retryResetRetries, payload)
+		if err != nil {
+			log.Fatalf("Failed to retry task: %v", err)
+		}
+
+		fmt.Printf("✅ Task %s queued again (retries %d/%d).\n", task.ID, task.Retries, task.MaxRetries)
+	},
+}
+
+func init() {
+	taskCmd.AddCommand(taskRetryCmd)
+
+	taskRetryCmd.Flags().BoolVar(&retryResetRetries, "reset-retries", false, "Reset the retry count to zero")
+	taskRetryCmd.Flags().StringVar(&retryPayload, "payload", "", "Replace the task payload with this JSON object")
+}
@@ -0,0 +1,13 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var workerCmd = &cobra.Command{
+	Use:   "worker",
+	Short: "Manage scheduler workers",
+	Long:  `The worker command provides tools to inspect and control the workers that execute tasks for the Task Scheduler service.`,
+}
+
+func init() {
+	rootCmd.AddCommand(workerCmd)
+}
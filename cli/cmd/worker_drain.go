@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/rudraprasaaad/task-scheduler/cli/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var drainGracePeriod string
+
+var workerDrainCmd = &cobra.Command{
+	Use:     "drain [WORKER_ID]",
+	Short:   "Stop a worker from claiming new tasks and let in-flight tasks finish",
+	Args:    cobra.ExactArgs(1),
+	Example: `task-cli worker drain worker-3 --grace 1m`,
+	Run: func(cmd *cobra.Command, args []string) {
+		workerID := args[0]
+		apiURL := viper.GetString("api_url")
+		cli, err := client.NewClient(apiURL)
+
+		if err != nil {
+			log.Fatalf("Failed to create API client: %v", err)
+		}
+
+		grace, err := cli.DrainWorker(workerID, drainGracePeriod)
+		if err != nil {
+			log.Fatalf("Failed to drain worker: %v", err)
+		}
+
+		fmt.Printf("✅ Drain requested for worker %s (grace period %s).\n", workerID, grace)
+		fmt.Println("Tasks still running after the grace period are returned to the queue.")
+	},
+}
+
+func init() {
+	workerCmd.AddCommand(workerDrainCmd)
+
+	workerDrainCmd.Flags().StringVarP(&drainGracePeriod, "grace", "g", "", "How long to wait for in-flight tasks, e.g. 30s (server default if empty)")
+}
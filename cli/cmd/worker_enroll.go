@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/rudraprasaaad/task-scheduler/cli/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	enrollWorkerID string
+	enrollTTL      string
+)
+
+var workerEnrollTokenCmd = &cobra.Command{
+	Use:   "enroll-token",
+	Short: "Mint a single-use enrollment token for a new worker",
+	Long: `Mint a single-use enrollment token. A worker exchanges it over gRPC for its
+own long-lived credential. Binding the token to a worker ID with --worker-id
+also lets it rotate the credential of an already enrolled worker.`,
+	Example: `task-cli worker enroll-token --worker-id gpu-1 --ttl 15m`,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiURL := viper.GetString("api_url")
+		cli, err := client.NewClient(apiURL)
+		if err != nil {
+			log.Fatalf("Failed to create API client: %v", err)
+		}
+
+		token, err := cli.CreateEnrollmentToken(enrollWorkerID, enrollTTL)
+		if err != nil {
+			log.Fatalf("Failed to create enrollment token: %v", err)
+		}
+
+		fmt.Println("✅ Enrollment token created. It is shown only once:")
+		fmt.Println()
+		fmt.Printf("  %s\n\n", token.Token)
+		if token.WorkerID != "" {
+			fmt.Printf("Bound to worker: %s\n", token.WorkerID)
+		}
+		fmt.Printf("Expires at:      %s\n", token.ExpiresAt.Format("2006-01-02 15:04:05"))
+	},
+}
+
+var workerRevokeCmd = &cobra.Command{
+	Use:   "revoke [WORKER_ID]",
+	Short: "Revoke a worker's credentials",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiURL := viper.GetString("api_url")
+		cli, err := client.NewClient(apiURL)
+		if err != nil {
+			log.Fatalf("Failed to create API client: %v", err)
+		}
+
+		revoked, err := cli.RevokeWorkerCredentials(args[0])
+		if err != nil {
+			log.Fatalf("Failed to revoke worker credentials: %v", err)
+		}
+
+		fmt.Printf("✅ Revoked %d credential(s) for worker %s.\n", revoked, args[0])
+	},
+}
+
+func init() {
+	workerCmd.AddCommand(workerEnrollTokenCmd)
+	workerCmd.AddCommand(workerRevokeCmd)
+
+	workerEnrollTokenCmd.Flags().StringVarP(&enrollWorkerID, "worker-id", "w", "", "Only allow the token to enroll this worker ID")
+	workerEnrollTokenCmd.Flags().StringVarP(&enrollTTL, "ttl", "t", "", "How long the token stays valid, e.g. 15m (server default 1h)")
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/rudraprasaaad/task-scheduler/cli/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	instructionParams map[string]string
+	instructionStatus string
+	instructionLimit  int
+)
+
+var workerInstructCmd = &cobra.Command{
+	Use:   "instruct [WORKER_ID] [TYPE]",
+	Short: "Queue an instruction for a worker",
+	Long: `Queue an instruction that the worker picks up on its next heartbeat.
+
+Supported types: pause, resume, drain, cancel_task, reload_executors, set_concurrency.`,
+	Args: cobra.ExactArgs(2),
+	Example: `task-cli worker instruct worker-2 pause
+task-cli worker instruct worker-2 cancel_task --param task_id=3f2a...
+task-cli worker instruct worker-2 set_concurrency --param slots=8 --param limit.report=2`,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiURL := viper.GetString("api_url")
+		cli, err := client.NewClient(apiURL)
+		if err != nil {
+			log.Fatalf("Failed to create API client: %v", err)
+		}
+
+		instruction, err := cli.CreateInstruction(args[0], args[1], instructionParams)
+		if err != nil {
+			log.Fatalf("Failed to queue instruction: %v", err)
+		}
+
+		fmt.Printf("✅ Instruction %s (%s) queued for worker %s.\n", instruction.ID, instruction.Type, instruction.WorkerID)
+	},
+}
+
+var workerInstructionsCmd = &cobra.Command{
+	Use:   "instructions [WORKER_ID]",
+	Short: "List instructions queued for a worker and their progress",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiURL := viper.GetString("api_url")
+		cli, err := client.NewClient(apiURL)
+		if err != nil {
+			log.Fatalf("Failed to create API client: %v", err)
+		}
+
+		instructions, err := cli.ListInstructions(args[0], instructionStatus, instructionLimit)
+		if err != nil {
+			log.Fatalf("Failed to list instructions: %v", err)
+		}
+
+		if len(instructions) == 0 {
+			fmt.Println("No instructions found.")
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "ID\tTYPE\tPARAMS\tSTATUS\tCREATED AT\tERROR")
+		for _, instruction := range instructions {
+			fmt.Fprintf(w, "%s\t%s\t%v\t%s\t%s\t%s\n",
+				instruction.ID, instruction.Type, instruction.Params, instruction.Status,
+				instruction.CreatedAt.Format("2006-01-02 15:04:05"), instruction.Error)
+		}
+		w.Flush()
+	},
+}
+
+func init() {
+	workerCmd.AddCommand(workerInstructCmd)
+	workerCmd.AddCommand(workerInstructionsCmd)
+
+	workerInstructCmd.Flags().StringToStringVarP(&instructionParams, "param", "p", nil, "Instruction parameter as key=value (repeatable)")
+	workerInstructionsCmd.Flags().StringVarP(&instructionStatus, "status", "s", "", "Only show instructions in these statuses (comma separated)")
+	workerInstructionsCmd.Flags().IntVarP(&instructionLimit, "limit", "l", 20, "Number of instructions to return")
+}